@@ -78,11 +78,24 @@ is defined as an explicit anonymity set containing several public keys
 rather than just one. For example, a member of an organization's board of trustees
 might prove to be a member of the board without revealing which member she is.
 
+- sign/blind provides blind Schnorr and blind BLS signatures, where a signer signs a
+message it does not see and cannot later link to the signing session.
+
 - sign/cosi provides collective signature algorithm, where a bunch of signers create a
 unique, compact and efficiently verifiable signature using the Schnorr signature as a basis.
 
 - sign/eddsa provides a kyber-native implementation of the EdDSA signature scheme.
 
+- sign/frost provides the FROST threshold Schnorr signature protocol of RFC 9591,
+whose signatures verify as plain Schnorr or EdDSA signatures.
+
+- sign/musig2 provides the MuSig2 two-round multi-signature scheme, whose signatures
+are plain Schnorr signatures under an aggregated public key.
+
+- sign/oneofmany provides the one-out-of-many proofs of Groth and Kohlweiss, and
+optionally linkable ring signatures over anon.Set whose size grows with the logarithm
+of the size of the anonymity set.
+
 - sign/schnorr provides a basic vanilla Schnorr signature scheme implementation.
 
 - shuffle: Verifiable cryptographic shuffles of ElGamal ciphertexts,
@@ -91,6 +104,10 @@ that keep the sources of individual votes or bids private
 without anyone having to trust more than one of the shuffler(s) to shuffle
 votes/bids honestly.
 
+- vrf: The elliptic curve verifiable random functions of RFC 9381,
+whose pseudorandom outputs come with a proof checked against the public key,
+as used for example in leader election.
+
 Target Use-cases
 
 As should be obvious, this library is intended to be used by
@@ -19,14 +19,21 @@ import (
 // values to encrypt the given message via AES-GCM. If the hash input parameter
 // is nil then SHA256 is used as a default. Encrypt returns a byte slice
 // containing the ephemeral elliptic curve point of the DH key exchange and the
-// ciphertext or an error.
+// ciphertext or an error. The ephemeral key is picked from fresh randomness;
+// see EncryptWithRand to supply the random stream.
 func Encrypt(group kyber.Group, public kyber.Point, message []byte, hash func() hash.Hash) ([]byte, error) {
+	return EncryptWithRand(group, public, message, hash, random.New())
+}
+
+// EncryptWithRand is like Encrypt, but picks the ephemeral key from the given
+// random stream, e.g. a deterministic one to replay an encryption in tests.
+func EncryptWithRand(group kyber.Group, public kyber.Point, message []byte, hash func() hash.Hash, rand cipher.Stream) ([]byte, error) {
 	if hash == nil {
 		hash = sha256.New
 	}
 
 	// Generate an ephemeral elliptic curve scalar and point
-	r := group.Scalar().Pick(random.New())
+	r := group.Scalar().Pick(rand)
 	R := group.Point().Mul(r, nil)
 
 	// Compute shared DH key
@@ -4,7 +4,9 @@ import (
 	"testing"
 
 	"github.com/stretchr/testify/require"
+	"go.dedis.ch/kyber/v3"
 	"go.dedis.ch/kyber/v3/group/edwards25519"
+	"go.dedis.ch/kyber/v3/group/nist"
 	"go.dedis.ch/kyber/v3/util/random"
 )
 
@@ -44,3 +46,32 @@ func TestECIESFailCiphertext(t *testing.T) {
 	_, err = Decrypt(suite, private, ciphertext, nil)
 	require.NotNil(t, err)
 }
+
+func TestECIESDeterministic(t *testing.T) {
+	message := []byte("Hello ECIES")
+	seed := []byte("some seed of at least thirty-two bytes")
+	for _, suite := range []kyber.Group{
+		edwards25519.NewBlakeSHA256Ed25519(),
+		nist.NewBlakeSHA256P256(),
+	} {
+		private := suite.Scalar().Pick(random.New())
+		public := suite.Point().Mul(private, nil)
+		c1, err := EncryptWithRand(suite, public, message, nil, random.NewHMACDRBG(seed, nil, nil))
+		require.NoError(t, err)
+		c2, err := EncryptWithRand(suite, public, message, nil, random.NewHMACDRBG(seed, nil, nil))
+		require.NoError(t, err)
+		require.Equal(t, c1, c2)
+		plaintext, err := Decrypt(suite, private, c1, nil)
+		require.NoError(t, err)
+		require.Equal(t, message, plaintext)
+	}
+
+	// Encrypt does not draw from the stream of the suite.
+	suite := edwards25519.NewBlakeSHA256Ed25519WithRand(random.NewHMACDRBG(seed, nil, nil))
+	public := suite.Point().Pick(random.New())
+	c1, err := Encrypt(suite, public, message, nil)
+	require.NoError(t, err)
+	c2, err := EncryptWithRand(suite, public, message, nil, random.NewHMACDRBG(seed, nil, nil))
+	require.NoError(t, err)
+	require.NotEqual(t, c1, c2)
+}
@@ -17,6 +17,7 @@ import (
 // SuiteCurve25519 is the suite for the 25519 curve
 type SuiteCurve25519 struct {
 	ProjectiveCurve
+	r cipher.Stream
 }
 
 // Hash returns the instance associated with the suite
@@ -45,6 +46,9 @@ func (s *SuiteCurve25519) New(t reflect.Type) interface{} {
 // RandomStream returns a cipher.Stream that returns a key stream
 // from crypto/rand.
 func (s *SuiteCurve25519) RandomStream() cipher.Stream {
+	if s.r != nil {
+		return s.r
+	}
 	return random.New()
 }
 
@@ -61,3 +65,12 @@ func NewBlakeSHA256Curve25519(fullGroup bool) *SuiteCurve25519 {
 	suite.Init(Param25519(), fullGroup)
 	return suite
 }
+
+// NewBlakeSHA256Curve25519WithRand returns the same cipher suite as
+// NewBlakeSHA256Curve25519, which produces random numbers via the provided
+// stream r.
+func NewBlakeSHA256Curve25519WithRand(fullGroup bool, r cipher.Stream) *SuiteCurve25519 {
+	suite := NewBlakeSHA256Curve25519(fullGroup)
+	suite.r = r
+	return suite
+}
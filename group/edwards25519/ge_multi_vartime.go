@@ -0,0 +1,158 @@
+package edwards25519
+
+import "math/bits"
+
+// strausThreshold is the number of points from which Pippenger's
+// algorithm is faster than Straus' algorithm.
+const strausThreshold = 190
+
+// geMultiScalarMultVartime computes h = a[0]*A[0] + ... + a[n-1]*A[n-1],
+// where the scalars a[i] are reduced modulo the order of the base point.
+func geMultiScalarMultVartime(h *extendedGroupElement, a []*[32]byte,
+	A []*extendedGroupElement) {
+
+	if len(a) < strausThreshold {
+		geStrausVartime(h, a, A)
+	} else {
+		gePippengerVartime(h, a, A)
+	}
+}
+
+// geStrausVartime interleaves the sliding window multiplications of
+// geScalarMultVartime, so that the doublings are shared by all points.
+func geStrausVartime(h *extendedGroupElement, a []*[32]byte,
+	A []*extendedGroupElement) {
+
+	aSlide := make([][256]int8, len(a))
+	Ai := make([][8]cachedGroupElement, len(a)) // A,3A,5A,7A,9A,11A,13A,15A
+	var t completedGroupElement
+	var u, A2 extendedGroupElement
+	var r projectiveGroupElement
+
+	for k := range a {
+		slide(&aSlide[k], a[k])
+		A[k].ToCached(&Ai[k][0])
+		A[k].Double(&t)
+		t.ToExtended(&A2)
+		for i := 0; i < 7; i++ {
+			t.Add(&A2, &Ai[k][i])
+			t.ToExtended(&u)
+			u.ToCached(&Ai[k][i+1])
+		}
+	}
+
+	// Find the most-significant nonzero clump of bits
+	i := 255
+	for ; i >= 0; i-- {
+		nonzero := false
+		for k := range aSlide {
+			if aSlide[k][i] != 0 {
+				nonzero = true
+				break
+			}
+		}
+		if nonzero {
+			break
+		}
+	}
+	if i < 0 { // no bits set
+		h.Zero()
+		return
+	}
+
+	r.Zero()
+	for ; i >= 0; i-- {
+		r.Double(&t)
+		for k := range aSlide {
+			if aSlide[k][i] > 0 {
+				t.ToExtended(&u)
+				t.Add(&u, &Ai[k][aSlide[k][i]/2])
+			} else if aSlide[k][i] < 0 {
+				t.ToExtended(&u)
+				t.Sub(&u, &Ai[k][(-aSlide[k][i])/2])
+			}
+		}
+		t.ToProjective(&r)
+	}
+	t.ToExtended(h)
+}
+
+// gePippengerVartime computes the multi-scalar multiplication with the
+// bucket method of Pippenger: the scalars are cut into windows of c bits,
+// and for each window the points are sorted into buckets according to
+// their digit, whose weighted sum is computed with a running sum.
+func gePippengerVartime(h *extendedGroupElement, a []*[32]byte,
+	A []*extendedGroupElement) {
+
+	c := bits.Len(uint(len(a))) - 3
+	if c < 4 {
+		c = 4
+	} else if c > 16 {
+		c = 16
+	}
+
+	Ai := make([]cachedGroupElement, len(A))
+	for k := range A {
+		A[k].ToCached(&Ai[k])
+	}
+	buckets := make([]extendedGroupElement, 1<<uint(c)-1)
+	used := make([]bool, len(buckets))
+
+	var t completedGroupElement
+	var r projectiveGroupElement
+	var sum, total extendedGroupElement
+	var tc cachedGroupElement
+
+	h.Zero()
+	for w := (253+c-1)/c - 1; w >= 0; w-- {
+		// h = 2^c * h
+		h.ToProjective(&r)
+		for i := 0; i < c; i++ {
+			r.Double(&t)
+			t.ToProjective(&r)
+		}
+		t.ToExtended(h)
+
+		for b := range used {
+			used[b] = false
+		}
+		for k := range a {
+			d := scalarDigit(a[k], uint(w*c), uint(c))
+			if d == 0 {
+				continue
+			}
+			if !used[d-1] {
+				buckets[d-1].Zero()
+				used[d-1] = true
+			}
+			t.Add(&buckets[d-1], &Ai[k])
+			t.ToExtended(&buckets[d-1])
+		}
+
+		// total = sum of (b+1)*buckets[b]
+		sum.Zero()
+		total.Zero()
+		for b := len(buckets) - 1; b >= 0; b-- {
+			if used[b] {
+				buckets[b].ToCached(&tc)
+				t.Add(&sum, &tc)
+				t.ToExtended(&sum)
+			}
+			sum.ToCached(&tc)
+			t.Add(&total, &tc)
+			t.ToExtended(&total)
+		}
+		total.ToCached(&tc)
+		t.Add(h, &tc)
+		t.ToExtended(h)
+	}
+}
+
+// scalarDigit returns the c bits of a starting at bit pos.
+func scalarDigit(a *[32]byte, pos, c uint) int {
+	var v uint32
+	for i := uint(0); i < 4 && pos/8+i < 32; i++ {
+		v |= uint32(a[pos/8+i]) << (8 * i)
+	}
+	return int((v >> (pos % 8)) & (1<<c - 1))
+}
@@ -5,9 +5,35 @@ import (
 	"testing"
 
 	"github.com/stretchr/testify/require"
+	"go.dedis.ch/kyber/v3"
+	"go.dedis.ch/kyber/v3/util/random"
 )
 
 func TestPoint_Marshal(t *testing.T) {
 	p := point{}
 	require.Equal(t, "ed.point", fmt.Sprintf("%s", p.MarshalID()))
 }
+
+func TestMultiScalarMulVarTime(t *testing.T) {
+	c := new(Curve)
+	stream := random.New()
+	// Both sides of the threshold between Straus and Pippenger.
+	for _, n := range []int{0, 1, 2, 17, strausThreshold + 1, 600} {
+		scalars := make([]kyber.Scalar, n)
+		points := make([]kyber.Point, n)
+		sum := c.Point().Null()
+		for i := range scalars {
+			scalars[i] = c.Scalar().Pick(stream)
+			points[i] = c.Point().Pick(stream)
+			if i == 1 {
+				scalars[i].Zero()
+			}
+			if i == 2 {
+				points[i].Base()
+			}
+			sum.Add(sum, c.Point().Mul(scalars[i], points[i]))
+		}
+		require.True(t, sum.Equal(c.MultiScalarMulVarTime(scalars, points)), "n = %d", n)
+	}
+	require.Panics(t, func() { c.MultiScalarMulVarTime(make([]kyber.Scalar, 1), nil) })
+}
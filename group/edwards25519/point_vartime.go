@@ -1,5 +1,7 @@
 package edwards25519
 
+import "go.dedis.ch/kyber/v3"
+
 // AllowVarTime sets a flag in this object which determines if a faster
 // but variable time implementation can be used. Set this only on Points
 // which represent public information. Using variable time algorithms to
@@ -7,3 +9,27 @@ package edwards25519
 func (P *point) AllowVarTime(varTime bool) {
 	P.varTime = varTime
 }
+
+// MultiScalarMulVarTime returns the sum of scalars[i]*points[i], computed in
+// variable time with a single multi-scalar multiplication, which is much
+// faster than summing the individual products. The base point must be
+// given explicitly. As for AllowVarTime, it must only be used on public
+// information. It panics if the slices have different lengths.
+func (c *Curve) MultiScalarMulVarTime(scalars []kyber.Scalar, points []kyber.Point) kyber.Point {
+	if len(scalars) != len(points) {
+		panic("edwards25519: mismatched number of scalars and points")
+	}
+	a := make([]*[32]byte, len(scalars))
+	A := make([]*extendedGroupElement, len(points))
+	for i := range scalars {
+		// reduce the scalar, as required by the multiplication
+		var wide [64]byte
+		copy(wide[:], scalars[i].(*scalar).v[:])
+		a[i] = new([32]byte)
+		scReduce(a[i], &wide)
+		A[i] = &points[i].(*point).ge
+	}
+	P := new(point)
+	geMultiScalarMultVartime(&P.ge, a, A)
+	return P
+}
@@ -18,6 +18,7 @@ import (
 // QrSuite is a quadratic residue suite
 type QrSuite struct {
 	ResidueGroup
+	r cipher.Stream
 }
 
 // Hash returns the instance associated with the suite
@@ -33,6 +34,9 @@ func (s QrSuite) XOF(key []byte) kyber.XOF {
 // RandomStream returns a cipher.Stream that returns a key stream
 // from crypto/rand.
 func (s QrSuite) RandomStream() cipher.Stream {
+	if s.r != nil {
+		return s.r
+	}
 	return random.New()
 }
 
@@ -65,3 +69,12 @@ func NewBlakeSHA256QR512() *QrSuite {
 	suite.SetParams(p, q, r, g)
 	return suite
 }
+
+// NewBlakeSHA256QR512WithRand returns the same cipher suite as
+// NewBlakeSHA256QR512, which produces random numbers via the provided
+// stream r.
+func NewBlakeSHA256QR512WithRand(r cipher.Stream) *QrSuite {
+	suite := NewBlakeSHA256QR512()
+	suite.r = r
+	return suite
+}
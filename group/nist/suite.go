@@ -17,6 +17,7 @@ import (
 // Suite128 is the suite for P256 curve
 type Suite128 struct {
 	p256
+	r cipher.Stream
 }
 
 // Hash returns the instance associated with the suite
@@ -32,6 +33,9 @@ func (s *Suite128) XOF(key []byte) kyber.XOF {
 // RandomStream returns a cipher.Stream that returns a key stream
 // from crypto/rand.
 func (s *Suite128) RandomStream() cipher.Stream {
+	if s.r != nil {
+		return s.r
+	}
 	return random.New()
 }
 
@@ -60,3 +64,12 @@ func NewBlakeSHA256P256() *Suite128 {
 	suite.p256.Init()
 	return suite
 }
+
+// NewBlakeSHA256P256WithRand returns the same cipher suite as
+// NewBlakeSHA256P256, which produces random numbers via the provided
+// stream r.
+func NewBlakeSHA256P256WithRand(r cipher.Stream) *Suite128 {
+	suite := NewBlakeSHA256P256()
+	suite.r = r
+	return suite
+}
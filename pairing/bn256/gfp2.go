@@ -1,5 +1,7 @@
 package bn256
 
+import "math/big"
+
 // For details of the algorithms used, see "Multiplication and Squaring on
 // Pairing-Friendly Fields, Devegili et al.
 // http://eprint.iacr.org/2006/471.pdf.
@@ -157,3 +159,58 @@ func (e *gfP2) Clone() gfP2 {
 
 	return n
 }
+
+func (e *gfP2) Exp(a *gfP2, power *big.Int) *gfP2 {
+	sum := (&gfP2{}).SetOne()
+	t := &gfP2{}
+
+	for i := power.BitLen() - 1; i >= 0; i-- {
+		t.Square(sum)
+		if power.Bit(i) != 0 {
+			sum.Mul(t, a)
+		} else {
+			sum.Set(t)
+		}
+	}
+
+	e.Set(sum)
+	return e
+}
+
+var (
+	pMinus3Over4 = new(big.Int).Rsh(new(big.Int).Sub(p, big.NewInt(3)), 2)
+	pMinus1Over2 = new(big.Int).Rsh(new(big.Int).Sub(p, big.NewInt(1)), 1)
+)
+
+// Sqrt sets e to a square root of a and returns true if a is a square,
+// and returns false otherwise.
+// See "Square root computation over even extension fields", Adj and
+// Rodríguez-Henríquez, algorithm 9, which applies as p = 3 mod 4.
+// https://eprint.iacr.org/2012/685.pdf
+func (e *gfP2) Sqrt(a *gfP2) bool {
+	a1 := (&gfP2{}).Exp(a, pMinus3Over4)
+	alpha := (&gfP2{}).Square(a1)
+	alpha.Mul(alpha, a)
+	// alpha^p is the conjugate of alpha
+	a0 := (&gfP2{}).Conjugate(alpha)
+	a0.Mul(a0, alpha)
+
+	minusOne := (&gfP2{}).SetOne()
+	minusOne.Neg(minusOne)
+	if *a0 == *minusOne {
+		return false
+	}
+
+	x0 := (&gfP2{}).Mul(a1, a)
+	if *alpha == *minusOne {
+		// x = i*x0
+		i := &gfP2{x: *newGFp(1)}
+		e.Mul(i, x0)
+		return true
+	}
+	b := (&gfP2{}).SetOne()
+	b.Add(b, alpha)
+	b.Exp(b, pMinus1Over2)
+	e.Mul(b, x0)
+	return true
+}
@@ -3,6 +3,7 @@ package bn256
 import (
 	"crypto/cipher"
 	"crypto/sha256"
+	"crypto/sha512"
 	"crypto/subtle"
 	"errors"
 	"io"
@@ -435,6 +436,52 @@ func (p *pointG2) String() string {
 	return "bn256.G2" + p.g.String()
 }
 
+// Hash hashes m to a point of G2. See hashToTwistPoint.
+func (p *pointG2) Hash(m []byte) kyber.Point {
+	p.g = hashToTwistPoint(m)
+	return p
+}
+
+// twistCofactor is the cofactor of G2 in the group of points of the twist,
+// 2p - Order.
+var twistCofactor = new(big.Int).Sub(new(big.Int).Lsh(p, 1), Order)
+
+// hashToTwistPoint hashes m to a point of G2. The SHA-512 digest of m gives
+// the first candidate abscissa, which is incremented until it is the
+// abscissa of a point of the twist. That point is then multiplied by the
+// cofactor so that it lies in G2.
+func hashToTwistPoint(m []byte) *twistPoint {
+	toGFp := func(in []byte) gfP {
+		v := new(big.Int).SetBytes(in)
+		v.Mod(v, p)
+		buf := make([]byte, 32)
+		b := v.Bytes()
+		copy(buf[32-len(b):], b)
+		e := gfP{}
+		e.Unmarshal(buf)
+		montEncode(&e, &e)
+		return e
+	}
+
+	h := sha512.Sum512(m)
+	x := &gfP2{x: toGFp(h[:32]), y: toGFp(h[32:])}
+	y, y2 := &gfP2{}, &gfP2{}
+	one := (&gfP2{}).SetOne()
+	for {
+		y2.Square(x).Mul(y2, x).Add(y2, twistB)
+		if y.Sqrt(y2) {
+			break
+		}
+		x.Add(x, one)
+	}
+
+	q := &twistPoint{x: *x, y: *y}
+	q.z.SetOne()
+	q.t.SetOne()
+	q.Mul(q, twistCofactor)
+	return q
+}
+
 type pointGT struct {
 	g *gfP12
 }
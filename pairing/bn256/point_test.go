@@ -39,3 +39,39 @@ func TestPointG1_HashToPoint(t *testing.T) {
 		t.Error("hash does not match reference")
 	}
 }
+
+func TestPointG2_HashToPoint(t *testing.T) {
+	p := new(pointG2).Hash([]byte("abc")).(*pointG2)
+	if p.g.IsInfinity() || !p.g.IsOnCurve() {
+		t.Fatal("hash is not a point of the twist")
+	}
+	// the point lies in G2, the subgroup of order Order
+	q := &twistPoint{}
+	q.Mul(p.g, Order)
+	if !q.IsInfinity() {
+		t.Error("hash is not in G2")
+	}
+	if !p.Equal(new(pointG2).Hash([]byte("abc"))) {
+		t.Error("hash is not deterministic")
+	}
+	if p.Equal(new(pointG2).Hash([]byte("abd"))) {
+		t.Error("hashes of different messages are equal")
+	}
+}
+
+func TestGFp2Sqrt(t *testing.T) {
+	x := &gfP2{x: *newGFp(5), y: *newGFp(7)}
+	sq := (&gfP2{}).Square(x)
+	r := &gfP2{}
+	if !r.Sqrt(sq) {
+		t.Fatal("square has no root")
+	}
+	if r2 := (&gfP2{}).Square(r); *r2 != *sq {
+		t.Error("wrong square root")
+	}
+	// a square times a non-square is a non-square: ξ = i+3 is not a square
+	xi := &gfP2{x: *newGFp(1), y: *newGFp(3)}
+	if r.Sqrt((&gfP2{}).Mul(sq, xi)) {
+		t.Error("non-square has a root")
+	}
+}
@@ -239,6 +239,22 @@ func TestBilinearity(t *testing.T) {
 	require.Equal(t, pc, pd)
 }
 
+func TestMultiPair(t *testing.T) {
+	suite := NewSuite()
+	var p1s, p2s []kyber.Point
+	sum := suite.GT().Point().Null()
+	for i := 0; i < 4; i++ {
+		p1 := suite.G1().Point().Pick(random.New())
+		p2 := suite.G2().Point().Pick(random.New())
+		p1s, p2s = append(p1s, p1), append(p2s, p2)
+		sum.Add(sum, suite.Pair(p1, p2))
+	}
+	p1s = append(p1s, suite.G1().Point().Null())
+	p2s = append(p2s, suite.G2().Point().Pick(random.New()))
+	require.True(t, suite.MultiPair(p1s, p2s).Equal(sum))
+	require.True(t, suite.MultiPair(nil, nil).Equal(suite.GT().Point().Null()))
+}
+
 func TestTripartiteDiffieHellman(t *testing.T) {
 	suite := NewSuite()
 	a := suite.G1().Scalar().Pick(random.New())
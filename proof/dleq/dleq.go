@@ -9,6 +9,7 @@ import (
 	"errors"
 
 	"go.dedis.ch/kyber/v3"
+	"go.dedis.ch/kyber/v3/proof/transcript"
 )
 
 // Suite wraps the functionalities needed by the dleq package.
@@ -132,3 +133,53 @@ func (p *Proof) Verify(suite Suite, G kyber.Point, H kyber.Point, xG kyber.Point
 	}
 	return nil
 }
+
+// appendStatement absorbs the statement of a dlog-equality proof into t.
+func appendStatement(t *transcript.Transcript, G, H, xG, xH kyber.Point) {
+	t.AppendMessage("dom-sep", []byte("dleq"))
+	t.AppendPoint("G", G)
+	t.AppendPoint("H", H)
+	t.AppendPoint("xG", xG)
+	t.AppendPoint("xH", xH)
+}
+
+// NewDLEQProofTranscript is like NewDLEQProof, but binds the proof to the
+// transcript t, which is updated with the statement and the commitments.
+// The commitment v is derived from t, the secret x and
+// suite.RandomStream(). The proof must be verified with VerifyTranscript on
+// a transcript in the same state as t.
+func NewDLEQProofTranscript(suite Suite, t *transcript.Transcript, G kyber.Point, H kyber.Point, x kyber.Scalar) (proof *Proof, xG kyber.Point, xH kyber.Point, err error) {
+	xG = suite.Point().Mul(x, G)
+	xH = suite.Point().Mul(x, H)
+	appendStatement(t, G, H, xG, xH)
+
+	// Commitment
+	rng := t.BuildRNG().RekeyWithWitnessScalar("x", x).Finalize(suite.RandomStream())
+	v := suite.Scalar().Pick(rng)
+	vG := suite.Point().Mul(v, G)
+	vH := suite.Point().Mul(v, H)
+	t.AppendPoint("vG", vG)
+	t.AppendPoint("vH", vH)
+
+	// Challenge
+	c := t.ChallengeScalar("c")
+
+	// Response
+	r := suite.Scalar()
+	r.Mul(x, c).Sub(v, r)
+
+	return &Proof{c, r, vG, vH}, xG, xH, nil
+}
+
+// VerifyTranscript checks a proof created by NewDLEQProofTranscript. Unlike
+// Verify, it also recomputes the challenge from the transcript t, which is
+// updated the same way as the prover's.
+func (p *Proof) VerifyTranscript(suite Suite, t *transcript.Transcript, G kyber.Point, H kyber.Point, xG kyber.Point, xH kyber.Point) error {
+	appendStatement(t, G, H, xG, xH)
+	t.AppendPoint("vG", p.VG)
+	t.AppendPoint("vH", p.VH)
+	if !t.ChallengeScalar("c").Equal(p.C) {
+		return errorInvalidProof
+	}
+	return p.Verify(suite, G, H, xG, xH)
+}
@@ -6,6 +6,7 @@ import (
 	"github.com/stretchr/testify/require"
 	"go.dedis.ch/kyber/v3"
 	"go.dedis.ch/kyber/v3/group/edwards25519"
+	"go.dedis.ch/kyber/v3/proof/transcript"
 	"go.dedis.ch/kyber/v3/util/random"
 )
 
@@ -59,3 +60,28 @@ func TestDLEQLengths(t *testing.T) {
 	_, _, _, err := NewDLEQProofBatch(suite, g, h, x)
 	require.Equal(t, err, errorDifferentLengths)
 }
+
+func TestDLEQProofTranscript(t *testing.T) {
+	suite := edwards25519.NewBlakeSHA256Ed25519()
+	x := suite.Scalar().Pick(rng)
+	g := suite.Point().Pick(rng)
+	h := suite.Point().Pick(rng)
+
+	proof, xG, xH, err := NewDLEQProofTranscript(suite, transcript.New(suite, "test"), g, h, x)
+	require.NoError(t, err)
+	require.NoError(t, proof.VerifyTranscript(suite, transcript.New(suite, "test"), g, h, xG, xH))
+	require.Equal(t, errorInvalidProof, proof.VerifyTranscript(suite, transcript.New(suite, "other"), g, h, xG, xH))
+
+	// A proof whose challenge was not derived from the transcript passes
+	// Verify, but not VerifyTranscript.
+	c := suite.Scalar().Pick(rng)
+	r := suite.Scalar().Pick(rng)
+	forged := &Proof{
+		C:  c,
+		R:  r,
+		VG: suite.Point().Add(suite.Point().Mul(r, g), suite.Point().Mul(c, xG)),
+		VH: suite.Point().Add(suite.Point().Mul(r, h), suite.Point().Mul(c, xH)),
+	}
+	require.NoError(t, forged.Verify(suite, g, h, xG, xH))
+	require.Equal(t, errorInvalidProof, forged.VerifyTranscript(suite, transcript.New(suite, "test"), g, h, xG, xH))
+}
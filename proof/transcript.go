@@ -0,0 +1,126 @@
+package proof
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+
+	"go.dedis.ch/kyber/v3/proof/transcript"
+)
+
+// Transcript-based noninteractive Sigma-protocol prover context
+type transcriptProver struct {
+	suite   Suite
+	t       *transcript.Transcript
+	proof   bytes.Buffer
+	msg     bytes.Buffer
+	prirand io.Reader
+}
+
+func newTranscriptProver(suite Suite, t *transcript.Transcript) *transcriptProver {
+	t.AppendMessage("dom-sep", []byte("proof"))
+	rng := t.BuildRNG().Finalize(suite.RandomStream())
+	return &transcriptProver{
+		suite:   suite,
+		t:       t,
+		prirand: &cipherStreamReader{rng},
+	}
+}
+
+func (c *transcriptProver) Put(message interface{}) error {
+	return c.suite.Write(&c.msg, message)
+}
+
+func (c *transcriptProver) consumeMsg() {
+	if c.msg.Len() > 0 {
+		buf := c.msg.Bytes()
+		c.t.AppendMessage("message", buf)
+		c.proof.Write(buf)
+		c.msg.Reset()
+	}
+}
+
+// Get public randomness that depends on every bit in the transcript so far.
+func (c *transcriptProver) PubRand(data ...interface{}) error {
+	c.consumeMsg()
+	return c.suite.Read(c.t.ChallengeStream("challenge"), data...)
+}
+
+// Get private randomness
+func (c *transcriptProver) PriRand(data ...interface{}) error {
+	if err := c.suite.Read(c.prirand, data...); err != nil {
+		return fmt.Errorf("error reading random stream: %v", err.Error())
+	}
+	return nil
+}
+
+// Obtain the encoded proof once the Sigma protocol is complete.
+func (c *transcriptProver) Proof() []byte {
+	c.consumeMsg()
+	return c.proof.Bytes()
+}
+
+// Transcript-based noninteractive Sigma-protocol verifier context
+type transcriptVerifier struct {
+	suite Suite
+	t     *transcript.Transcript
+	proof bytes.Buffer // Buffer with which to read the proof
+	prbuf []byte       // Byte-slice underlying proof buffer
+}
+
+func newTranscriptVerifier(suite Suite, t *transcript.Transcript,
+	proof []byte) (*transcriptVerifier, error) {
+	c := &transcriptVerifier{suite: suite, t: t}
+	if _, err := c.proof.Write(proof); err != nil {
+		return nil, err
+	}
+	c.prbuf = c.proof.Bytes()
+	t.AppendMessage("dom-sep", []byte("proof"))
+	return c, nil
+}
+
+func (c *transcriptVerifier) consumeMsg() {
+	l := len(c.prbuf) - c.proof.Len() // How many bytes read?
+	if l > 0 {
+		c.t.AppendMessage("message", c.prbuf[:l])
+		c.prbuf = c.proof.Bytes() // Reset to remaining bytes
+	}
+}
+
+// Read structured data from the proof
+func (c *transcriptVerifier) Get(message interface{}) error {
+	return c.suite.Read(&c.proof, message)
+}
+
+// Get public randomness that depends on every bit in the transcript so far.
+func (c *transcriptVerifier) PubRand(data ...interface{}) error {
+	c.consumeMsg()
+	return c.suite.Read(c.t.ChallengeStream("challenge"), data...)
+}
+
+// TranscriptProve runs a given Sigma-protocol prover with a ProverContext
+// that produces a non-interactive proof, deriving its challenges from the
+// transcript t rather than from a bare hash of the messages. Every message
+// of the proof is appended to t, so t must be in the same state when it is
+// handed to TranscriptVerify. Private randomness is derived from t and
+// from suite.RandomStream().
+func TranscriptProve(suite Suite, t *transcript.Transcript, prover Prover) ([]byte, error) {
+	ctx := newTranscriptProver(suite, t)
+	if e := (func(ProverContext) error)(prover)(ctx); e != nil {
+		return nil, e
+	}
+	return ctx.Proof(), nil
+}
+
+// TranscriptVerify checks a noninteractive proof generated with
+// TranscriptProve. The transcript t must be in the same state as the one
+// given to TranscriptProve. Returns nil if the proof checks out, or an
+// error on any failure.
+func TranscriptVerify(suite Suite, t *transcript.Transcript,
+	verifier Verifier, proof []byte) error {
+	ctx, err := newTranscriptVerifier(suite, t, proof)
+	if err != nil {
+		return err
+	}
+	return (func(VerifierContext) error)(verifier)(ctx)
+}
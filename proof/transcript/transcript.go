@@ -0,0 +1,190 @@
+// Package transcript implements a Merlin-style transcript for
+// non-interactive zero-knowledge proofs and signatures built with the
+// Fiat-Shamir heuristic.
+//
+// A Transcript absorbs every message exchanged by a protocol, each one
+// framed with a label, and derives the verifier's challenges from all the
+// data absorbed so far. Since the protocol name, every label and the
+// length of every message are part of the hashed data, a challenge
+// computed in one protocol can never be replayed in another one, nor in
+// a different position of the same protocol.
+//
+// Transcripts are built on kyber.XOF: the XOF of the suite absorbs the
+// framed messages and is squeezed and then reseeded for each challenge.
+//
+// Both the prover and the verifier feed the same sequence of operations
+// into their own transcript:
+//
+//	t := transcript.New(suite, "my protocol")
+//	t.AppendPoint("commitment", V)
+//	c := t.ChallengeScalar("challenge")
+//
+// The prover can additionally derive its secret nonces from the
+// transcript, its witness and fresh randomness with BuildRNG, so that a
+// weak random source alone cannot leak its secrets.
+package transcript
+
+import (
+	"crypto/cipher"
+	"encoding/binary"
+
+	"go.dedis.ch/kyber/v3"
+	"go.dedis.ch/kyber/v3/util/random"
+)
+
+// Suite represents the set of functionalities needed by the package
+// transcript.
+type Suite interface {
+	kyber.Group
+	kyber.XOFFactory
+}
+
+// protocolLabel is the initial seed of every transcript, it identifies the
+// framing used by this package.
+const protocolLabel = "kyber-transcript-v1"
+
+// Operation codes prepended to every framed message.
+const (
+	opDomain byte = iota + 1
+	opMessage
+	opChallenge
+	opWitness
+	opRandomness
+)
+
+// rngSeedSize is the number of bytes drawn from the external random stream
+// when finalizing an RNG.
+const rngSeedSize = 32
+
+// Transcript is the running state of a Fiat-Shamir transform. A
+// Transcript is not safe for concurrent use.
+type Transcript struct {
+	suite Suite
+	xof   kyber.XOF
+}
+
+// New returns a fresh transcript for the protocol identified by label.
+func New(suite Suite, label string) *Transcript {
+	t := &Transcript{
+		suite: suite,
+		xof:   suite.XOF([]byte(protocolLabel)),
+	}
+	frame(t.xof, opDomain, label, []byte(label))
+	return t
+}
+
+// frame writes a message to w, prefixed by its operation code, label and
+// length.
+func frame(w kyber.XOF, op byte, label string, msg []byte) {
+	var hdr [13]byte
+	hdr[0] = op
+	binary.LittleEndian.PutUint32(hdr[1:5], uint32(len(label)))
+	binary.LittleEndian.PutUint64(hdr[5:], uint64(len(msg)))
+	_, _ = w.Write(hdr[:5])
+	_, _ = w.Write([]byte(label))
+	_, _ = w.Write(hdr[5:])
+	_, _ = w.Write(msg)
+}
+
+// Suite returns the suite of the transcript.
+func (t *Transcript) Suite() Suite {
+	return t.suite
+}
+
+// AppendMessage absorbs msg under the given label.
+func (t *Transcript) AppendMessage(label string, msg []byte) {
+	frame(t.xof, opMessage, label, msg)
+}
+
+// AppendPoint absorbs the canonical encoding of p under the given label.
+func (t *Transcript) AppendPoint(label string, p kyber.Point) {
+	buf, err := p.MarshalBinary()
+	if err != nil {
+		panic("transcript: cannot marshal point: " + err.Error())
+	}
+	t.AppendMessage(label, buf)
+}
+
+// AppendScalar absorbs the canonical encoding of s under the given label.
+func (t *Transcript) AppendScalar(label string, s kyber.Scalar) {
+	buf, err := s.MarshalBinary()
+	if err != nil {
+		panic("transcript: cannot marshal scalar: " + err.Error())
+	}
+	t.AppendMessage(label, buf)
+}
+
+// ChallengeBytes fills dst with challenge bytes that depend on everything
+// absorbed so far, the label and the length of dst. The challenge is
+// absorbed in turn, so that successive challenges are independent.
+func (t *Transcript) ChallengeBytes(label string, dst []byte) {
+	var l [8]byte
+	binary.LittleEndian.PutUint64(l[:], uint64(len(dst)))
+	frame(t.xof, opChallenge, label, l[:])
+	_, _ = t.xof.Read(dst)
+	t.xof.Reseed()
+}
+
+// ChallengeScalar returns a uniformly distributed challenge scalar that
+// depends on everything absorbed so far and the label.
+func (t *Transcript) ChallengeScalar(label string) kyber.Scalar {
+	frame(t.xof, opChallenge, label, nil)
+	c := t.suite.Scalar().Pick(t.xof)
+	t.xof.Reseed()
+	return c
+}
+
+// ChallengeStream returns an XOF seeded with challenge bytes, to be used
+// when a protocol needs an unbounded amount of public randomness.
+func (t *Transcript) ChallengeStream(label string) kyber.XOF {
+	var seed [32]byte
+	t.ChallengeBytes(label, seed[:])
+	return t.suite.XOF(seed[:])
+}
+
+// Clone returns an independent copy of the transcript in its current
+// state. It can be used to run several proofs that share a common prefix.
+func (t *Transcript) Clone() *Transcript {
+	return &Transcript{suite: t.suite, xof: t.xof.Clone()}
+}
+
+// RNGBuilder derives a prover's private random stream from a transcript.
+// It is created by Transcript.BuildRNG.
+type RNGBuilder struct {
+	xof kyber.XOF
+}
+
+// BuildRNG forks the transcript to derive private randomness for the
+// prover. The transcript itself is not modified.
+func (t *Transcript) BuildRNG() *RNGBuilder {
+	return &RNGBuilder{xof: t.xof.Clone()}
+}
+
+// RekeyWithWitness absorbs secret prover data, such as a private key,
+// into the random stream. It returns the builder to allow chaining.
+func (b *RNGBuilder) RekeyWithWitness(label string, witness []byte) *RNGBuilder {
+	frame(b.xof, opWitness, label, witness)
+	return b
+}
+
+// RekeyWithWitnessScalar is like RekeyWithWitness for a secret scalar.
+func (b *RNGBuilder) RekeyWithWitnessScalar(label string, witness kyber.Scalar) *RNGBuilder {
+	buf, err := witness.MarshalBinary()
+	if err != nil {
+		panic("transcript: cannot marshal scalar: " + err.Error())
+	}
+	return b.RekeyWithWitness(label, buf)
+}
+
+// Finalize mixes randomness from rand into the builder and returns the
+// resulting stream. If rand is nil, crypto/rand is used. The builder must
+// not be used afterwards.
+func (b *RNGBuilder) Finalize(rand cipher.Stream) cipher.Stream {
+	if rand == nil {
+		rand = random.New()
+	}
+	seed := make([]byte, rngSeedSize)
+	random.Bytes(seed, rand)
+	frame(b.xof, opRandomness, "rng", seed)
+	return b.xof
+}
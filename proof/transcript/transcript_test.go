@@ -0,0 +1,95 @@
+package transcript
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"go.dedis.ch/kyber/v3/group/edwards25519"
+	"go.dedis.ch/kyber/v3/xof/blake2xb"
+)
+
+var suite = edwards25519.NewBlakeSHA256Ed25519()
+
+func TestTranscriptDeterminism(t *testing.T) {
+	p := suite.Point().Pick(blake2xb.New([]byte("point")))
+	s := suite.Scalar().Pick(blake2xb.New([]byte("scalar")))
+	run := func() *Transcript {
+		tr := New(suite, "test")
+		tr.AppendMessage("msg", []byte("hello"))
+		tr.AppendPoint("point", p)
+		tr.AppendScalar("scalar", s)
+		return tr
+	}
+
+	t1, t2 := run(), run()
+	require.True(t, t1.ChallengeScalar("c").Equal(t2.ChallengeScalar("c")))
+
+	// Successive challenges differ.
+	c1 := make([]byte, 32)
+	c2 := make([]byte, 32)
+	t1.ChallengeBytes("c", c1)
+	t1.ChallengeBytes("c", c2)
+	require.NotEqual(t, c1, c2)
+
+	// The transcript remains usable after a challenge.
+	t2.ChallengeBytes("c", c1)
+	t1 = t2.Clone()
+	t1.AppendMessage("more", []byte("data"))
+	t2.AppendMessage("more", []byte("data"))
+	t1.ChallengeBytes("c", c1)
+	t2.ChallengeBytes("c", c2)
+	require.Equal(t, c1, c2)
+}
+
+func TestTranscriptDomainSeparation(t *testing.T) {
+	challenge := func(tr *Transcript) []byte {
+		buf := make([]byte, 32)
+		tr.ChallengeBytes("c", buf)
+		return buf
+	}
+
+	t1 := New(suite, "protocol A")
+	t2 := New(suite, "protocol B")
+	require.NotEqual(t, challenge(t1), challenge(t2))
+
+	// Moving bytes between the label and the message changes the
+	// challenge.
+	t1 = New(suite, "test")
+	t1.AppendMessage("ab", []byte("c"))
+	t2 = New(suite, "test")
+	t2.AppendMessage("a", []byte("bc"))
+	require.NotEqual(t, challenge(t1), challenge(t2))
+
+	// So does splitting a message.
+	t1 = New(suite, "test")
+	t1.AppendMessage("m", []byte("ab"))
+	t2 = New(suite, "test")
+	t2.AppendMessage("m", []byte("a"))
+	t2.AppendMessage("m", []byte("b"))
+	require.NotEqual(t, challenge(t1), challenge(t2))
+}
+
+func TestTranscriptRNG(t *testing.T) {
+	tr := New(suite, "test")
+	before := tr.Clone()
+
+	rng1 := tr.BuildRNG().RekeyWithWitness("w", []byte("secret")).Finalize(blake2xb.New(nil))
+	rng2 := tr.BuildRNG().RekeyWithWitness("w", []byte("secret")).Finalize(blake2xb.New(nil))
+	rng3 := tr.BuildRNG().RekeyWithWitness("w", []byte("other")).Finalize(blake2xb.New(nil))
+	b1 := make([]byte, 32)
+	b2 := make([]byte, 32)
+	b3 := make([]byte, 32)
+	rng1.XORKeyStream(b1, b1)
+	rng2.XORKeyStream(b2, b2)
+	rng3.XORKeyStream(b3, b3)
+	require.Equal(t, b1, b2)
+	require.NotEqual(t, b1, b3)
+
+	// Fresh randomness is used when no stream is given.
+	rng4 := tr.BuildRNG().RekeyWithWitness("w", []byte("secret")).Finalize(nil)
+	rng4.XORKeyStream(b3, make([]byte, 32))
+	require.NotEqual(t, b1, b3)
+
+	// Building an RNG does not modify the transcript.
+	require.True(t, tr.ChallengeScalar("c").Equal(before.ChallengeScalar("c")))
+}
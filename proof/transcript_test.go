@@ -0,0 +1,37 @@
+package proof
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"go.dedis.ch/kyber/v3"
+	"go.dedis.ch/kyber/v3/group/edwards25519"
+	"go.dedis.ch/kyber/v3/proof/transcript"
+)
+
+func TestTranscriptProve(t *testing.T) {
+	suite := edwards25519.NewBlakeSHA256Ed25519()
+	B := suite.Point().Base()
+	x := suite.Scalar().Pick(suite.RandomStream())
+	X := suite.Point().Mul(x, nil)
+
+	rep := Rep("X", "x", "B")
+	sec := map[string]kyber.Scalar{"x": x}
+	pub := map[string]kyber.Point{"B": B, "X": X}
+
+	proof, err := TranscriptProve(suite, transcript.New(suite, "test"), rep.Prover(suite, sec, pub, nil))
+	require.NoError(t, err)
+
+	err = TranscriptVerify(suite, transcript.New(suite, "test"), rep.Verifier(suite, pub), proof)
+	require.NoError(t, err)
+
+	// The proof is bound to the protocol label of the transcript.
+	err = TranscriptVerify(suite, transcript.New(suite, "other"), rep.Verifier(suite, pub), proof)
+	require.Error(t, err)
+
+	// And to any context appended before the proof.
+	tr := transcript.New(suite, "test")
+	tr.AppendMessage("context", []byte("hello"))
+	err = TranscriptVerify(suite, tr, rep.Verifier(suite, pub), proof)
+	require.Error(t, err)
+}
@@ -5,6 +5,15 @@ import (
 )
 
 // Random is an interface that can be mixed in to local suite definitions.
+//
+// It is the way to supply the randomness of the protocols: the packages
+// under sign, share and proof draw theirs from the RandomStream of their
+// suite, so that a suite returning a deterministic stream, such as one of
+// suites.WithRandomStream, replays them. The functions that are not given a
+// suite, such as shuffle.Shuffle, share.NewPriPoly or
+// ecies.EncryptWithRand, take the stream as a parameter instead. The checks
+// whose soundness relies on unpredictable randomness, such as the batch
+// verifications of signatures, always use fresh randomness.
 type Random interface {
 	// RandomStream returns a cipher.Stream that produces a
 	// cryptographically random key stream. The stream must
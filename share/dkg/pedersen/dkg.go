@@ -10,6 +10,7 @@
 package dkg
 
 import (
+	"crypto/cipher"
 	"crypto/rand"
 	"errors"
 	"fmt"
@@ -85,12 +86,36 @@ type Config struct {
 	// Reader is an optional field that can hold a user-specified entropy source.
 	// If it is set, Reader's data will be combined with random data from crypto/rand
 	// to create a random stream which will pick the dkg's secret coefficient. Otherwise,
-	// the random stream will only use crypto/rand's entropy.
+	// the random stream of the suite is used, which draws from crypto/rand
+	// unless the suite was created with another stream.
 	Reader io.Reader
 
 	// When UserReaderOnly it set to true, only the user-specified entropy source
 	// Reader will be used. This should only be used in tests, allowing reproducibility.
 	UserReaderOnly bool
+
+	// EntropyHealth optionally enables the health tests of
+	// random.NewWithHealth, with the given configuration, on Reader and
+	// crypto/rand when combined with it. The DKG then refuses to start if a
+	// source fails the tests with the FailClosed policy.
+	EntropyHealth *random.HealthConfig
+}
+
+// pickSecret picks the secret coefficient of the dkg from the stream. It
+// returns the error of a stream which failed its health tests, instead of
+// panicking.
+func pickSecret(suite Suite, stream cipher.Stream) (secret kyber.Scalar, err error) {
+	if s, ok := stream.(random.Stream); ok {
+		defer func() {
+			if r := recover(); r != nil {
+				if s.Err() == nil {
+					panic(r)
+				}
+				err = fmt.Errorf("dkg: gathering entropy: %v", s.Err())
+			}
+		}()
+	}
+	return suite.Scalar().Pick(stream), nil
 }
 
 // DistKeyGenerator is the struct that runs the DKG protocol.
@@ -177,14 +202,22 @@ func NewDistKeyHandler(c *Config) (*DistKeyGenerator, error) {
 		canIssue = true
 	} else if !isResharing && newPresent {
 		// fresh DKG case
-		randomStream := random.New()
+		randomStream := c.Suite.RandomStream()
 		// if the user provided a reader, use it alone or combined with crypto/rand
-		if c.Reader != nil && !c.UserReaderOnly {
-			randomStream = random.New(c.Reader, rand.Reader)
-		} else if c.Reader != nil && c.UserReaderOnly {
-			randomStream = random.New(c.Reader)
+		if c.Reader != nil {
+			readers := []io.Reader{c.Reader, rand.Reader}
+			if c.UserReaderOnly {
+				readers = readers[:1]
+			}
+			randomStream = random.New(readers...)
+			if c.EntropyHealth != nil {
+				randomStream = random.NewWithHealth(*c.EntropyHealth, readers...)
+			}
+		}
+		var secretCoeff kyber.Scalar
+		if secretCoeff, err = pickSecret(c.Suite, randomStream); err != nil {
+			return nil, err
 		}
-		secretCoeff := c.Suite.Scalar().Pick(randomStream)
 		dealer, err = vss.NewDealer(c.Suite, c.Longterm, secretCoeff, c.NewNodes, newThreshold)
 		canIssue = true
 		c.OldNodes = c.NewNodes
@@ -1,6 +1,7 @@
 package dkg
 
 import (
+	"bytes"
 	"crypto/rand"
 	"fmt"
 	mathRand "math/rand"
@@ -12,6 +13,7 @@ import (
 	"go.dedis.ch/kyber/v3/group/edwards25519"
 	"go.dedis.ch/kyber/v3/share"
 	vss "go.dedis.ch/kyber/v3/share/vss/pedersen"
+	"go.dedis.ch/kyber/v3/util/random"
 )
 
 var suite = edwards25519.NewBlakeSHA256Ed25519()
@@ -1327,3 +1329,38 @@ func TestUserOnlyFlagFalseBehavior(t *testing.T) {
 
 	require.False(t, dkg1.dealer.PrivatePoly().Secret().Equal(dkg2.dealer.PrivatePoly().Secret()))
 }
+
+func TestStuckReader(t *testing.T) {
+	partPubs, partSec, _ := generate(defaultN, defaultT)
+	var events []*random.HealthError
+	c := &Config{
+		Suite:     suite,
+		Longterm:  partSec[0],
+		NewNodes:  partPubs,
+		Threshold: defaultT,
+		Reader:    bytes.NewReader(make([]byte, 64)),
+		EntropyHealth: &random.HealthConfig{
+			Monitor: func(e *random.HealthError) { events = append(events, e) },
+		},
+	}
+	_, err := NewDistKeyHandler(c)
+	require.Error(t, err)
+	require.Len(t, events, 1)
+	require.Equal(t, random.RepetitionCountTest, events[0].Test)
+}
+
+func TestSuiteRandomStream(t *testing.T) {
+	partPubs, partSec, _ := generate(defaultN, defaultT)
+	seed := []byte("some seed of at least thirty-two bytes")
+	newDKG := func() *DistKeyGenerator {
+		dkg, err := NewDistKeyHandler(&Config{
+			Suite:     edwards25519.NewBlakeSHA256Ed25519WithRand(random.NewHMACDRBG(seed, nil, nil)),
+			Longterm:  partSec[0],
+			NewNodes:  partPubs,
+			Threshold: defaultT,
+		})
+		require.NoError(t, err)
+		return dkg
+	}
+	require.True(t, newDKG().dealer.PrivatePoly().Secret().Equal(newDKG().dealer.PrivatePoly().Secret()))
+}
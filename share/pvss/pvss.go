@@ -14,9 +14,11 @@ package pvss
 
 import (
 	"errors"
+	"strconv"
 
 	"go.dedis.ch/kyber/v3"
 	"go.dedis.ch/kyber/v3/proof/dleq"
+	"go.dedis.ch/kyber/v3/proof/transcript"
 	"go.dedis.ch/kyber/v3/share"
 )
 
@@ -47,6 +49,18 @@ type PubVerShare struct {
 // t and the base point H. The function returns the list of shares and the
 // public commitment polynomial.
 func EncShares(suite Suite, H kyber.Point, X []kyber.Point, secret kyber.Scalar, t int) (shares []*PubVerShare, commit *share.PubPoly, err error) {
+	return encShares(suite, nil, H, X, secret, t)
+}
+
+// EncSharesTranscript is like EncShares, but each encryption consistency
+// proof is bound to a copy of the transcript tr, extended with the index of
+// the share. The shares must be verified with the Transcript variants of the
+// verification functions, given a transcript in the same state as tr.
+func EncSharesTranscript(suite Suite, tr *transcript.Transcript, H kyber.Point, X []kyber.Point, secret kyber.Scalar, t int) (shares []*PubVerShare, commit *share.PubPoly, err error) {
+	return encShares(suite, tr, H, X, secret, t)
+}
+
+func encShares(suite Suite, tr *transcript.Transcript, H kyber.Point, X []kyber.Point, secret kyber.Scalar, t int) (shares []*PubVerShare, commit *share.PubPoly, err error) {
 	n := len(X)
 	encShares := make([]*PubVerShare, n)
 
@@ -59,6 +73,17 @@ func EncShares(suite Suite, H kyber.Point, X []kyber.Point, secret kyber.Scalar,
 	// Create public polynomial commitments with respect to basis H
 	pubPoly := priPoly.Commit(H)
 
+	if tr != nil {
+		for i := 0; i < n; i++ {
+			P, _, sX, err := dleq.NewDLEQProofTranscript(suite, shareTranscript(tr, "enc", priShares[i].I), H, X[i], priShares[i].V)
+			if err != nil {
+				return nil, nil, err
+			}
+			encShares[i] = &PubVerShare{share.PubShare{I: priShares[i].I, V: sX}, *P}
+		}
+		return encShares, pubPoly, nil
+	}
+
 	// Prepare data for encryption consistency proofs ...
 	indices := make([]int, n)
 	values := make([]kyber.Scalar, n)
@@ -83,11 +108,39 @@ func EncShares(suite Suite, H kyber.Point, X []kyber.Point, secret kyber.Scalar,
 	return encShares, pubPoly, nil
 }
 
+// shareTranscript returns a copy of tr bound to the given step of the
+// protocol and share index.
+func shareTranscript(tr *transcript.Transcript, step string, i int) *transcript.Transcript {
+	c := tr.Clone()
+	c.AppendMessage("dom-sep", []byte("pvss-"+step))
+	c.AppendMessage("index", []byte(strconv.Itoa(i)))
+	return c
+}
+
+// verifyProof checks the proof of a share, against the transcript tr if it
+// is not nil.
+func verifyProof(suite Suite, tr *transcript.Transcript, step string, s *PubVerShare, G, H, xG, xH kyber.Point) error {
+	if tr == nil {
+		return s.P.Verify(suite, G, H, xG, xH)
+	}
+	return s.P.VerifyTranscript(suite, shareTranscript(tr, step, s.S.I), G, H, xG, xH)
+}
+
 // VerifyEncShare checks that the encrypted share sX satisfies
 // log_{H}(sH) == log_{X}(sX) where sH is the public commitment computed by
 // evaluating the public commitment polynomial at the encrypted share's index i.
 func VerifyEncShare(suite Suite, H kyber.Point, X kyber.Point, sH kyber.Point, encShare *PubVerShare) error {
-	if err := encShare.P.Verify(suite, H, X, sH, encShare.S.V); err != nil {
+	return verifyEncShare(suite, nil, H, X, sH, encShare)
+}
+
+// VerifyEncShareTranscript is like VerifyEncShare for a share created by
+// EncSharesTranscript.
+func VerifyEncShareTranscript(suite Suite, tr *transcript.Transcript, H kyber.Point, X kyber.Point, sH kyber.Point, encShare *PubVerShare) error {
+	return verifyEncShare(suite, tr, H, X, sH, encShare)
+}
+
+func verifyEncShare(suite Suite, tr *transcript.Transcript, H kyber.Point, X kyber.Point, sH kyber.Point, encShare *PubVerShare) error {
+	if err := verifyProof(suite, tr, "enc", encShare, H, X, sH, encShare.S.V); err != nil {
 		return errorEncVerification
 	}
 	return nil
@@ -97,13 +150,23 @@ func VerifyEncShare(suite Suite, H kyber.Point, X kyber.Point, sH kyber.Point, e
 // slices of encrypted shares. The function returns the valid encrypted shares
 // together with the corresponding public keys.
 func VerifyEncShareBatch(suite Suite, H kyber.Point, X []kyber.Point, sH []kyber.Point, encShares []*PubVerShare) ([]kyber.Point, []*PubVerShare, error) {
+	return verifyEncShareBatch(suite, nil, H, X, sH, encShares)
+}
+
+// VerifyEncShareBatchTranscript is like VerifyEncShareBatch for shares
+// created by EncSharesTranscript.
+func VerifyEncShareBatchTranscript(suite Suite, tr *transcript.Transcript, H kyber.Point, X []kyber.Point, sH []kyber.Point, encShares []*PubVerShare) ([]kyber.Point, []*PubVerShare, error) {
+	return verifyEncShareBatch(suite, tr, H, X, sH, encShares)
+}
+
+func verifyEncShareBatch(suite Suite, tr *transcript.Transcript, H kyber.Point, X []kyber.Point, sH []kyber.Point, encShares []*PubVerShare) ([]kyber.Point, []*PubVerShare, error) {
 	if len(X) != len(sH) || len(sH) != len(encShares) {
 		return nil, nil, errorDifferentLengths
 	}
 	var K []kyber.Point  // good public keys
 	var E []*PubVerShare // good encrypted shares
 	for i := 0; i < len(X); i++ {
-		if err := VerifyEncShare(suite, H, X[i], sH[i], encShares[i]); err == nil {
+		if err := verifyEncShare(suite, tr, H, X[i], sH[i], encShares[i]); err == nil {
 			K = append(K, X[i])
 			E = append(E, encShares[i])
 		}
@@ -115,13 +178,30 @@ func VerifyEncShareBatch(suite Suite, H kyber.Point, X []kyber.Point, sH []kyber
 // consistency proof and, if valid, decrypts it and creates a decryption
 // consistency proof.
 func DecShare(suite Suite, H kyber.Point, X kyber.Point, sH kyber.Point, x kyber.Scalar, encShare *PubVerShare) (*PubVerShare, error) {
-	if err := VerifyEncShare(suite, H, X, sH, encShare); err != nil {
+	return decShare(suite, nil, H, X, sH, x, encShare)
+}
+
+// DecShareTranscript is like DecShare for a share created by
+// EncSharesTranscript. The decryption consistency proof is bound to a copy
+// of the transcript tr as well.
+func DecShareTranscript(suite Suite, tr *transcript.Transcript, H kyber.Point, X kyber.Point, sH kyber.Point, x kyber.Scalar, encShare *PubVerShare) (*PubVerShare, error) {
+	return decShare(suite, tr, H, X, sH, x, encShare)
+}
+
+func decShare(suite Suite, tr *transcript.Transcript, H kyber.Point, X kyber.Point, sH kyber.Point, x kyber.Scalar, encShare *PubVerShare) (*PubVerShare, error) {
+	if err := verifyEncShare(suite, tr, H, X, sH, encShare); err != nil {
 		return nil, err
 	}
 	G := suite.Point().Base()
 	V := suite.Point().Mul(suite.Scalar().Inv(x), encShare.S.V) // decryption: x^{-1} * (xS)
 	ps := &share.PubShare{I: encShare.S.I, V: V}
-	P, _, _, err := dleq.NewDLEQProof(suite, G, V, x)
+	var P *dleq.Proof
+	var err error
+	if tr != nil {
+		P, _, _, err = dleq.NewDLEQProofTranscript(suite, shareTranscript(tr, "dec", ps.I), G, V, x)
+	} else {
+		P, _, _, err = dleq.NewDLEQProof(suite, G, V, x)
+	}
 	if err != nil {
 		return nil, err
 	}
@@ -132,6 +212,16 @@ func DecShare(suite Suite, H kyber.Point, X kyber.Point, sH kyber.Point, x kyber
 // encrypted shares. The function returns the valid encrypted and decrypted
 // shares as well as the corresponding public keys.
 func DecShareBatch(suite Suite, H kyber.Point, X []kyber.Point, sH []kyber.Point, x kyber.Scalar, encShares []*PubVerShare) ([]kyber.Point, []*PubVerShare, []*PubVerShare, error) {
+	return decShareBatch(suite, nil, H, X, sH, x, encShares)
+}
+
+// DecShareBatchTranscript is like DecShareBatch for shares created by
+// EncSharesTranscript.
+func DecShareBatchTranscript(suite Suite, tr *transcript.Transcript, H kyber.Point, X []kyber.Point, sH []kyber.Point, x kyber.Scalar, encShares []*PubVerShare) ([]kyber.Point, []*PubVerShare, []*PubVerShare, error) {
+	return decShareBatch(suite, tr, H, X, sH, x, encShares)
+}
+
+func decShareBatch(suite Suite, tr *transcript.Transcript, H kyber.Point, X []kyber.Point, sH []kyber.Point, x kyber.Scalar, encShares []*PubVerShare) ([]kyber.Point, []*PubVerShare, []*PubVerShare, error) {
 	if len(X) != len(sH) || len(sH) != len(encShares) {
 		return nil, nil, nil, errorDifferentLengths
 	}
@@ -139,7 +229,7 @@ func DecShareBatch(suite Suite, H kyber.Point, X []kyber.Point, sH []kyber.Point
 	var E []*PubVerShare // good encrypted shares
 	var D []*PubVerShare // good decrypted shares
 	for i := 0; i < len(encShares); i++ {
-		if ds, err := DecShare(suite, H, X[i], sH[i], x, encShares[i]); err == nil {
+		if ds, err := decShare(suite, tr, H, X[i], sH[i], x, encShares[i]); err == nil {
 			K = append(K, X[i])
 			E = append(E, encShares[i])
 			D = append(D, ds)
@@ -151,7 +241,17 @@ func DecShareBatch(suite Suite, H kyber.Point, X []kyber.Point, sH []kyber.Point
 // VerifyDecShare checks that the decrypted share sG satisfies
 // log_{G}(X) == log_{sG}(sX). Note that X = xG and sX = s(xG) = x(sG).
 func VerifyDecShare(suite Suite, G kyber.Point, X kyber.Point, encShare *PubVerShare, decShare *PubVerShare) error {
-	if err := decShare.P.Verify(suite, G, decShare.S.V, X, encShare.S.V); err != nil {
+	return verifyDecShare(suite, nil, G, X, encShare, decShare)
+}
+
+// VerifyDecShareTranscript is like VerifyDecShare for a share decrypted by
+// DecShareTranscript.
+func VerifyDecShareTranscript(suite Suite, tr *transcript.Transcript, G kyber.Point, X kyber.Point, encShare *PubVerShare, decShare *PubVerShare) error {
+	return verifyDecShare(suite, tr, G, X, encShare, decShare)
+}
+
+func verifyDecShare(suite Suite, tr *transcript.Transcript, G kyber.Point, X kyber.Point, encShare *PubVerShare, decShare *PubVerShare) error {
+	if err := verifyProof(suite, tr, "dec", decShare, G, decShare.S.V, X, encShare.S.V); err != nil {
 		return errorDecVerification
 	}
 	return nil
@@ -160,12 +260,22 @@ func VerifyDecShare(suite Suite, G kyber.Point, X kyber.Point, encShare *PubVerS
 // VerifyDecShareBatch provides the same functionality as VerifyDecShare but for
 // slices of decrypted shares. The function returns the the valid decrypted shares.
 func VerifyDecShareBatch(suite Suite, G kyber.Point, X []kyber.Point, encShares []*PubVerShare, decShares []*PubVerShare) ([]*PubVerShare, error) {
+	return verifyDecShareBatch(suite, nil, G, X, encShares, decShares)
+}
+
+// VerifyDecShareBatchTranscript is like VerifyDecShareBatch for shares
+// decrypted by DecShareTranscript.
+func VerifyDecShareBatchTranscript(suite Suite, tr *transcript.Transcript, G kyber.Point, X []kyber.Point, encShares []*PubVerShare, decShares []*PubVerShare) ([]*PubVerShare, error) {
+	return verifyDecShareBatch(suite, tr, G, X, encShares, decShares)
+}
+
+func verifyDecShareBatch(suite Suite, tr *transcript.Transcript, G kyber.Point, X []kyber.Point, encShares []*PubVerShare, decShares []*PubVerShare) ([]*PubVerShare, error) {
 	if len(X) != len(encShares) || len(encShares) != len(decShares) {
 		return nil, errorDifferentLengths
 	}
 	var D []*PubVerShare // good decrypted shares
 	for i := 0; i < len(X); i++ {
-		if err := VerifyDecShare(suite, G, X[i], encShares[i], decShares[i]); err == nil {
+		if err := verifyDecShare(suite, tr, G, X[i], encShares[i], decShares[i]); err == nil {
 			D = append(D, decShares[i])
 		}
 	}
@@ -175,7 +285,17 @@ func VerifyDecShareBatch(suite Suite, G kyber.Point, X []kyber.Point, encShares
 // RecoverSecret first verifies the given decrypted shares against their
 // decryption consistency proofs and then tries to recover the shared secret.
 func RecoverSecret(suite Suite, G kyber.Point, X []kyber.Point, encShares []*PubVerShare, decShares []*PubVerShare, t int, n int) (kyber.Point, error) {
-	D, err := VerifyDecShareBatch(suite, G, X, encShares, decShares)
+	return recoverSecret(suite, nil, G, X, encShares, decShares, t, n)
+}
+
+// RecoverSecretTranscript is like RecoverSecret for shares decrypted by
+// DecShareTranscript.
+func RecoverSecretTranscript(suite Suite, tr *transcript.Transcript, G kyber.Point, X []kyber.Point, encShares []*PubVerShare, decShares []*PubVerShare, t int, n int) (kyber.Point, error) {
+	return recoverSecret(suite, tr, G, X, encShares, decShares, t, n)
+}
+
+func recoverSecret(suite Suite, tr *transcript.Transcript, G kyber.Point, X []kyber.Point, encShares []*PubVerShare, decShares []*PubVerShare, t int, n int) (kyber.Point, error) {
+	D, err := verifyDecShareBatch(suite, tr, G, X, encShares, decShares)
 	if err != nil {
 		return nil, err
 	}
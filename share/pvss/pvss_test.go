@@ -6,6 +6,7 @@ import (
 	"github.com/stretchr/testify/require"
 	"go.dedis.ch/kyber/v3"
 	"go.dedis.ch/kyber/v3/group/edwards25519"
+	"go.dedis.ch/kyber/v3/proof/transcript"
 )
 
 func TestPVSS(test *testing.T) {
@@ -256,3 +257,56 @@ func TestPVSSBatch(test *testing.T) {
 	require.True(test, suite.Point().Mul(s1, nil).Equal(S1))
 	require.True(test, suite.Point().Mul(s2, nil).Equal(S2))
 }
+
+func TestPVSSTranscript(test *testing.T) {
+	suite := edwards25519.NewBlakeSHA256Ed25519()
+	G := suite.Point().Base()
+	H := suite.Point().Pick(suite.XOF([]byte("H")))
+	tr := transcript.New(suite, "test")
+	tr.AppendMessage("round", []byte("1"))
+	n := 10
+	t := 2*n/3 + 1
+	x := make([]kyber.Scalar, n) // trustee private keys
+	X := make([]kyber.Point, n)  // trustee public keys
+	for i := 0; i < n; i++ {
+		x[i] = suite.Scalar().Pick(suite.RandomStream())
+		X[i] = suite.Point().Mul(x[i], nil)
+	}
+
+	secret := suite.Scalar().Pick(suite.RandomStream())
+	encShares, pubPoly, err := EncSharesTranscript(suite, tr, H, X, secret, t)
+	require.NoError(test, err)
+
+	sH := make([]kyber.Point, n)
+	for i := 0; i < n; i++ {
+		sH[i] = pubPoly.Eval(encShares[i].S.I).V
+	}
+
+	// The shares only verify against the same transcript.
+	_, E, err := VerifyEncShareBatchTranscript(suite, tr, H, X, sH, encShares)
+	require.NoError(test, err)
+	require.Len(test, E, n)
+	other := transcript.New(suite, "test")
+	require.Equal(test, errorEncVerification, VerifyEncShareTranscript(suite, other, H, X[0], sH[0], encShares[0]))
+	require.Equal(test, errorEncVerification, VerifyEncShare(suite, H, X[1], sH[0], encShares[0]))
+
+	var K []kyber.Point  // good public keys
+	var D []*PubVerShare // good decrypted shares
+	E = nil
+	for i := 0; i < n; i++ {
+		ds, err := DecShareTranscript(suite, tr, H, X[i], sH[i], x[i], encShares[i])
+		require.NoError(test, err)
+		require.NoError(test, VerifyDecShareTranscript(suite, tr, G, X[i], encShares[i], ds))
+		require.Equal(test, errorDecVerification, VerifyDecShareTranscript(suite, other, G, X[i], encShares[i], ds))
+		K = append(K, X[i])
+		E = append(E, encShares[i])
+		D = append(D, ds)
+	}
+
+	recovered, err := RecoverSecretTranscript(suite, tr, G, K, E, D, t, n)
+	require.NoError(test, err)
+	require.True(test, suite.Point().Mul(secret, nil).Equal(recovered))
+
+	_, err = RecoverSecretTranscript(suite, other, G, K, E, D, t, n)
+	require.Equal(test, errorTooFewShares, err)
+}
@@ -8,3 +8,12 @@ import (
 // Set represents an explicit anonymity set
 // as a list of public keys.
 type Set []kyber.Point
+
+// InPrimeOrderSubgroup returns whether q*P is the identity, where q is the
+// order of the scalars of g. In groups with a cofactor, such as
+// edwards25519, this rejects the points with a small-order component, with
+// which a signer could present several linkage tags for the same key.
+func InPrimeOrderSubgroup(g kyber.Group, P kyber.Point) bool {
+	Q := g.Point().Mul(g.Scalar().SetInt64(-1), P)
+	return Q.Add(Q, P).Equal(g.Point().Null())
+}
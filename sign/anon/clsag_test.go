@@ -0,0 +1,185 @@
+package anon
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"go.dedis.ch/kyber/v3"
+	"go.dedis.ch/kyber/v3/group/edwards25519"
+	"go.dedis.ch/kyber/v3/sign/internal/torsion"
+	"go.dedis.ch/kyber/v3/util/random"
+)
+
+// clsagRings returns k rings of n keys, and the private keys of member mine.
+func clsagRings(g kyber.Group, n, k, mine int) ([]Set, []kyber.Scalar) {
+	rings := make([]Set, k)
+	privates := make([]kyber.Scalar, k)
+	for j := range rings {
+		rings[j] = Set(make([]kyber.Point, n))
+		for i := range rings[j] {
+			rings[j][i] = g.Point().Pick(random.New())
+		}
+		privates[j] = g.Scalar().Pick(random.New())
+		rings[j][mine] = g.Point().Mul(privates[j], nil)
+	}
+	return rings, privates
+}
+
+func TestCLSAG(t *testing.T) {
+	suite := edwards25519.NewBlakeSHA256Ed25519()
+	M := []byte("Hello World!")
+	scope := []byte("test scope")
+	for _, k := range []int{1, 2, 3} {
+		for _, n := range []int{1, 2, 7} {
+			mine := n / 2
+			rings, privates := clsagRings(suite, n, k, mine)
+			for _, linkScope := range [][]byte{nil, scope} {
+				sig, err := SignCLSAG(suite, M, rings, linkScope, mine, privates)
+				require.NoError(t, err)
+				tag, err := VerifyCLSAG(suite, M, rings, linkScope, sig)
+				require.NoError(t, err)
+				require.NotNil(t, tag)
+
+				_, err = VerifyCLSAG(suite, []byte("other"), rings, linkScope, sig)
+				require.Error(t, err)
+				_, err = VerifyCLSAG(suite, M, rings, linkScope, append(sig, 0))
+				require.Error(t, err)
+				if n > 1 {
+					swapped := append([]Set{}, rings...)
+					swapped[k-1] = append(Set{}, rings[k-1]...)
+					swapped[k-1][0], swapped[k-1][1] = swapped[k-1][1], swapped[k-1][0]
+					_, err = VerifyCLSAG(suite, M, swapped, linkScope, sig)
+					require.Error(t, err)
+				}
+				if k > 1 {
+					_, err = VerifyCLSAG(suite, M, rings[:k-1], linkScope, sig)
+					require.Error(t, err)
+				}
+			}
+		}
+	}
+}
+
+func TestCLSAGErrors(t *testing.T) {
+	suite := edwards25519.NewBlakeSHA256Ed25519()
+	M := []byte("Hello World!")
+	rings, privates := clsagRings(suite, 4, 2, 1)
+	_, err := SignCLSAG(suite, M, rings, nil, 2, privates)
+	require.Error(t, err)
+	_, err = SignCLSAG(suite, M, rings, nil, 1, privates[:1])
+	require.Error(t, err)
+	_, err = SignCLSAG(suite, M, []Set{rings[0], rings[1][:3]}, nil, 1, privates)
+	require.Error(t, err)
+	_, err = SignCLSAG(suite, M, nil, nil, 0, nil)
+	require.Error(t, err)
+}
+
+func TestCLSAGLinkage(t *testing.T) {
+	suite := edwards25519.NewBlakeSHA256Ed25519()
+	M := []byte("Hello World!")
+	scope := []byte("test scope")
+	rings, privates := clsagRings(suite, 5, 1, 3)
+
+	sig, err := SignCLSAG(suite, M, rings, scope, 3, privates)
+	require.NoError(t, err)
+	tag, err := VerifyCLSAG(suite, M, rings, scope, sig)
+	require.NoError(t, err)
+
+	// same tag as the signatures of Sign, which have the same size
+	liu := Sign(suite, []byte("other"), rings[0], scope, 3, privates[0])
+	liuTag, err := Verify(suite, []byte("other"), rings[0], scope, liu)
+	require.NoError(t, err)
+	require.Equal(t, liuTag, tag)
+	require.Equal(t, len(liu), len(sig))
+
+	// another scope gives another tag
+	sig, err = SignCLSAG(suite, M, rings, []byte("other scope"), 3, privates)
+	require.NoError(t, err)
+	other, err := VerifyCLSAG(suite, M, rings, []byte("other scope"), sig)
+	require.NoError(t, err)
+	require.NotEqual(t, tag, other)
+
+	// the signature is bound to its scope
+	_, err = VerifyCLSAG(suite, M, rings, scope, sig)
+	require.Error(t, err)
+	_, err = VerifyCLSAG(suite, M, rings, nil, sig)
+	require.Error(t, err)
+
+	// a tag with a component of order 8 is rejected
+	T8 := torsion.Edwards25519Order8()
+	sig, err = SignCLSAG(suite, M, rings, scope, 3, privates)
+	require.NoError(t, err)
+	tagged := suite.Point()
+	require.NoError(t, tagged.UnmarshalBinary(sig[len(sig)-32:]))
+	buf, err := tagged.Add(tagged, T8).MarshalBinary()
+	require.NoError(t, err)
+	_, err = VerifyCLSAG(suite, M, rings, scope, append(sig[:len(sig)-32:len(sig)-32], buf...))
+	require.EqualError(t, err, "linkage tag not in the prime-order subgroup")
+}
+
+func TestInPrimeOrderSubgroup(t *testing.T) {
+	suite := edwards25519.NewBlakeSHA256Ed25519()
+	P := suite.Point().Pick(suite.RandomStream())
+	T8 := torsion.Edwards25519Order8()
+	require.True(t, InPrimeOrderSubgroup(suite, P))
+	require.True(t, InPrimeOrderSubgroup(suite, suite.Point().Null()))
+	require.False(t, InPrimeOrderSubgroup(suite, T8))
+	require.False(t, InPrimeOrderSubgroup(suite, suite.Point().Add(P, T8)))
+}
+
+// BenchmarkCLSAG compares a signature of SignCLSAG over k rings with k
+// signatures of Sign, one per ring, which prove the same keys without
+// binding them to the same member. The sizes are reported in B/sig.
+func BenchmarkCLSAG(b *testing.B) {
+	suite := edwards25519.NewBlakeSHA256Ed25519()
+	scope := []byte("bench scope")
+	for _, k := range []int{1, 2, 3} {
+		for _, n := range []int{16, 64, 256} {
+			rings, privates := clsagRings(suite, n, k, 0)
+			sigs := make([][]byte, k)
+			size := 0
+			for j := range rings {
+				sigs[j] = Sign(suite, benchMessage, rings[j], scope, 0, privates[j])
+				size += len(sigs[j])
+			}
+			clsag, err := SignCLSAG(suite, benchMessage, rings, scope, 0, privates)
+			if err != nil {
+				b.Fatal(err)
+			}
+
+			b.Run(fmt.Sprintf("Sign/k=%d/%d", k, n), func(b *testing.B) {
+				for i := 0; i < b.N; i++ {
+					for j := range rings {
+						Sign(suite, benchMessage, rings[j], scope, 0, privates[j])
+					}
+				}
+				b.ReportMetric(float64(size), "B/sig")
+			})
+			b.Run(fmt.Sprintf("SignCLSAG/k=%d/%d", k, n), func(b *testing.B) {
+				for i := 0; i < b.N; i++ {
+					if _, err := SignCLSAG(suite, benchMessage, rings, scope, 0, privates); err != nil {
+						b.Fatal(err)
+					}
+				}
+				b.ReportMetric(float64(len(clsag)), "B/sig")
+			})
+			b.Run(fmt.Sprintf("Verify/k=%d/%d", k, n), func(b *testing.B) {
+				for i := 0; i < b.N; i++ {
+					for j := range rings {
+						if _, err := Verify(suite, benchMessage, rings[j], scope, sigs[j]); err != nil {
+							b.Fatal(err)
+						}
+					}
+				}
+			})
+			b.Run(fmt.Sprintf("VerifyCLSAG/k=%d/%d", k, n), func(b *testing.B) {
+				for i := 0; i < b.N; i++ {
+					if _, err := VerifyCLSAG(suite, benchMessage, rings, scope, clsag); err != nil {
+						b.Fatal(err)
+					}
+				}
+			})
+		}
+	}
+}
@@ -0,0 +1,149 @@
+package bdn
+
+import (
+	"errors"
+	"fmt"
+
+	"go.dedis.ch/kyber/v3"
+	"go.dedis.ch/kyber/v3/pairing"
+	"go.dedis.ch/kyber/v3/sign"
+)
+
+// Aggregator aggregates the signatures of a message by the members of a
+// roster. The coefficients of the roster are computed once, when the
+// aggregator is created, so that adding signatures, one at a time or as
+// sub-aggregates produced by other aggregators over the same roster (as in
+// the trees of Handel), costs a multiplication per new signer. An Aggregator
+// is not safe for concurrent use.
+type Aggregator struct {
+	scheme  *Scheme
+	publics []kyber.Point
+	coefs   []kyber.Scalar
+	mask    *sign.Mask
+	sig     kyber.Point
+	key     kyber.Point
+}
+
+// NewAggregator returns an empty aggregator over the roster of public keys,
+// for the package-level scheme.
+func NewAggregator(suite pairing.Suite, publics []kyber.Point) (*Aggregator, error) {
+	return NewSchemeOnG1(suite).NewAggregator(publics)
+}
+
+// NewAggregator returns an empty aggregator over the roster of public keys.
+func (s *Scheme) NewAggregator(publics []kyber.Point) (*Aggregator, error) {
+	coefs, err := hashPointToR(publics)
+	if err != nil {
+		return nil, err
+	}
+	mask, err := sign.NewMask(s.bls.Suite(), publics, nil)
+	if err != nil {
+		return nil, err
+	}
+	return &Aggregator{
+		scheme:  s,
+		publics: publics,
+		coefs:   coefs,
+		mask:    mask,
+		sig:     s.bls.SignatureGroup().Point().Null(),
+		key:     s.bls.KeyGroup().Point().Null(),
+	}, nil
+}
+
+func isSet(mask []byte, i int) bool {
+	return mask[i/8]&(byte(1)<<uint(i&7)) != 0
+}
+
+// Add adds the signature of the member of the roster at index i. It returns
+// an error if the signature of this member is already part of the
+// aggregate. The signature itself is not verified.
+func (a *Aggregator) Add(i int, sig []byte) error {
+	if i < 0 || i >= len(a.publics) {
+		return fmt.Errorf("bdn: index %d out of range", i)
+	}
+	if isSet(a.mask.Mask(), i) {
+		return fmt.Errorf("bdn: signature of member %d already aggregated", i)
+	}
+	S := a.scheme.bls.SignatureGroup().Point()
+	if err := S.UnmarshalBinary(sig); err != nil {
+		return err
+	}
+	a.sig.Add(a.sig, a.weigh(S, i))
+	a.key.Add(a.key, a.weigh(a.publics[i], i))
+	return a.mask.SetBit(i, true)
+}
+
+// weigh returns (c_i+1)*P, as c_i is in the range [0, 2^128-1] and the
+// coefficients in the range [1, 2^128].
+func (a *Aggregator) weigh(P kyber.Point, i int) kyber.Point {
+	PC := P.Clone().Mul(a.coefs[i], P)
+	return PC.Add(PC, P)
+}
+
+// Merge adds a sub-aggregate over the same roster, given by the bitmask of
+// its signers and its aggregated signature, as returned by the Mask and
+// Signature methods of another Aggregator. The signers of the sub-aggregate
+// must be disjoint from the signers already aggregated. The sub-aggregate is
+// not verified.
+func (a *Aggregator) Merge(mask []byte, sig kyber.Point) error {
+	if len(mask) != a.mask.Len() {
+		return errors.New("bdn: mismatching mask length")
+	}
+	ours := a.mask.Mask()
+	for i := range ours {
+		if ours[i]&mask[i] != 0 {
+			return errors.New("bdn: sub-aggregate overlaps the aggregate")
+		}
+	}
+	var added []int
+	for i := range a.publics {
+		if isSet(mask, i) {
+			added = append(added, i)
+		}
+	}
+	for i := len(a.publics); i < 8*len(mask); i++ {
+		if isSet(mask, i) {
+			return errors.New("bdn: mask has bits beyond the roster")
+		}
+	}
+	a.sig.Add(a.sig, sig)
+	for _, i := range added {
+		a.key.Add(a.key, a.weigh(a.publics[i], i))
+	}
+	return a.mask.Merge(mask)
+}
+
+// MergeAggregator merges the aggregate of b, over the same roster, into a.
+func (a *Aggregator) MergeAggregator(b *Aggregator) error {
+	return a.Merge(b.mask.Mask(), b.sig)
+}
+
+// Mask returns a copy of the mask of the signers of the aggregate.
+func (a *Aggregator) Mask() *sign.Mask {
+	// NewMask cannot fail without a key
+	m, _ := sign.NewMask(a.scheme.bls.Suite(), a.publics, nil)
+	_ = m.SetMask(a.mask.Mask())
+	return m
+}
+
+// Signature returns the aggregated signature, as AggregateSignatures would
+// for the same mask.
+func (a *Aggregator) Signature() kyber.Point {
+	return a.sig.Clone()
+}
+
+// AggregatePublicKey returns the aggregated public key of the signers, as
+// AggregatePublicKeys would for the same mask.
+func (a *Aggregator) AggregatePublicKey() kyber.Point {
+	return a.key.Clone()
+}
+
+// Verify checks the aggregated signature of msg against the aggregated
+// public key of the signers.
+func (a *Aggregator) Verify(msg []byte) error {
+	sig, err := a.sig.MarshalBinary()
+	if err != nil {
+		return err
+	}
+	return a.scheme.Verify(a.key, msg, sig)
+}
@@ -0,0 +1,111 @@
+package bdn
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"go.dedis.ch/kyber/v3"
+	"go.dedis.ch/kyber/v3/pairing/bn256"
+	"go.dedis.ch/kyber/v3/sign"
+	"go.dedis.ch/kyber/v3/util/random"
+)
+
+func roster(t *testing.T, s *Scheme, n int, msg []byte) ([]kyber.Point, [][]byte) {
+	publics := make([]kyber.Point, n)
+	sigs := make([][]byte, n)
+	for i := range publics {
+		var x kyber.Scalar
+		x, publics[i] = s.NewKeyPair(random.New())
+		var err error
+		sigs[i], err = s.Sign(x, msg)
+		require.NoError(t, err)
+	}
+	return publics, sigs
+}
+
+func TestAggregator(t *testing.T) {
+	msg := []byte("Hello Boneh-Lynn-Shacham")
+	suite := bn256.NewSuite()
+	for _, s := range []*Scheme{NewSchemeOnG1(suite), NewSchemeOnG2(suite)} {
+		publics, sigs := roster(t, s, 11, msg)
+		agg, err := s.NewAggregator(publics)
+		require.NoError(t, err)
+		require.NoError(t, agg.Add(7, sigs[7]))
+		require.NoError(t, agg.Add(2, sigs[2]))
+		require.NoError(t, agg.Add(9, sigs[9]))
+		require.Error(t, agg.Add(2, sigs[2]))
+		require.Error(t, agg.Add(11, sigs[2]))
+		require.Error(t, agg.Add(3, sigs[3][:5]))
+		require.NoError(t, agg.Verify(msg))
+		require.Error(t, agg.Verify([]byte("other")))
+
+		// same results as the aggregation of the whole mask
+		mask := agg.Mask()
+		require.Equal(t, 3, mask.CountEnabled())
+		sig, err := s.AggregateSignatures([][]byte{sigs[2], sigs[7], sigs[9]}, mask)
+		require.NoError(t, err)
+		require.True(t, sig.Equal(agg.Signature()))
+		key, err := s.AggregatePublicKeys(mask)
+		require.NoError(t, err)
+		require.True(t, key.Equal(agg.AggregatePublicKey()))
+
+		// the mask can be checked against the policies of the signers
+		weights := []uint64{1, 1, 5, 1, 1, 1, 1, 5, 1, 5, 1}
+		weighted, err := sign.NewWeightedPolicy(weights, 15)
+		require.NoError(t, err)
+		require.True(t, weighted.Check(mask))
+		require.False(t, sign.NewGroupPolicy([]int{0, 1, 2}, sign.NewThresholdPolicy(2)).Check(mask))
+
+		// the returned mask is a copy
+		require.NoError(t, mask.SetBit(0, true))
+		require.Equal(t, 3, agg.Mask().CountEnabled())
+	}
+}
+
+func TestAggregatorMerge(t *testing.T) {
+	msg := []byte("Hello Boneh-Lynn-Shacham")
+	suite := bn256.NewSuite()
+	s := NewSchemeOnG1(suite)
+	n := 16
+	publics, sigs := roster(t, s, n, msg)
+
+	// binary tree of aggregators, leaves aggregating a signature each
+	level := make([]*Aggregator, n)
+	for i := range level {
+		var err error
+		level[i], err = NewAggregator(suite, publics)
+		require.NoError(t, err)
+		require.NoError(t, level[i].Add(i, sigs[i]))
+	}
+	for len(level) > 1 {
+		next := make([]*Aggregator, len(level)/2)
+		for i := range next {
+			next[i] = level[2*i]
+			require.NoError(t, next[i].MergeAggregator(level[2*i+1]))
+			require.NoError(t, next[i].Verify(msg))
+		}
+		level = next
+	}
+	root := level[0]
+	require.Equal(t, n, root.Mask().CountEnabled())
+
+	full, err := NewAggregator(suite, publics)
+	require.NoError(t, err)
+	for i := range sigs {
+		require.NoError(t, full.Add(i, sigs[i]))
+	}
+	require.True(t, full.Signature().Equal(root.Signature()))
+	require.True(t, full.AggregatePublicKey().Equal(root.AggregatePublicKey()))
+
+	// overlapping sub-aggregates are rejected
+	a, err := NewAggregator(suite, publics)
+	require.NoError(t, err)
+	require.NoError(t, a.Add(0, sigs[0]))
+	require.NoError(t, a.Add(1, sigs[1]))
+	b, err := NewAggregator(suite, publics)
+	require.NoError(t, err)
+	require.NoError(t, b.Add(1, sigs[1]))
+	require.Error(t, a.MergeAggregator(b))
+	require.Error(t, a.Merge([]byte{0x4}, b.Signature()))
+	require.NoError(t, a.Verify(msg))
+}
@@ -4,6 +4,10 @@
 // signature for an aggregation of signatures. It fixes the situation by
 // adding coefficients to the aggregate.
 //
+// The package-level functions put the signatures on G1 and the public keys on
+// G2. A Scheme returned by NewSchemeOnG2 offers the same operations with
+// signatures on G2 and shorter public keys on G1.
+//
 // See the papers:
 // https://eprint.iacr.org/2018/483.pdf
 // https://crypto.stanford.edu/~dabo/pubs/papers/BLSmultisig.html
@@ -67,29 +71,45 @@ func hashPointToR(pubs []kyber.Point) ([]kyber.Scalar, error) {
 	return coefs, nil
 }
 
-// NewKeyPair creates a new BLS signing key pair. The private key x is a scalar
-// and the public key X is a point on curve G2.
-func NewKeyPair(suite pairing.Suite, random cipher.Stream) (kyber.Scalar, kyber.Point) {
-	return bls.NewKeyPair(suite, random)
+// Scheme is the BDN signature scheme on top of a bare BLS scheme, with
+// either the signatures on G1 and the public keys on G2, or the converse.
+type Scheme struct {
+	bls *bls.Scheme
+}
+
+// NewSchemeOnG1 returns the BDN scheme with signatures on G1 and public keys
+// on G2, as the package-level functions.
+func NewSchemeOnG1(suite pairing.Suite) *Scheme {
+	return &Scheme{bls: bls.NewSchemeOnG1(suite)}
+}
+
+// NewSchemeOnG2 returns the BDN scheme with signatures on G2 and public keys
+// on G1.
+func NewSchemeOnG2(suite pairing.Suite) *Scheme {
+	return &Scheme{bls: bls.NewSchemeOnG2(suite)}
+}
+
+// NewKeyPair creates a new key pair. The private key x is a scalar and the
+// public key X is a point of the key group.
+func (s *Scheme) NewKeyPair(random cipher.Stream) (kyber.Scalar, kyber.Point) {
+	return s.bls.NewKeyPair(random)
 }
 
 // Sign creates a BLS signature S = x * H(m) on a message m using the private
-// key x. The signature S is a point on curve G1.
-func Sign(suite pairing.Suite, x kyber.Scalar, msg []byte) ([]byte, error) {
-	return bls.Sign(suite, x, msg)
+// key x. The signature S is a point of the signature group.
+func (s *Scheme) Sign(x kyber.Scalar, msg []byte) ([]byte, error) {
+	return s.bls.Sign(x, msg)
 }
 
 // Verify checks the given BLS signature S on the message m using the public
-// key X by verifying that the equality e(H(m), X) == e(H(m), x*B2) ==
-// e(x*H(m), B2) == e(S, B2) holds where e is the pairing operation and B2 is
-// the base point from curve G2.
-func Verify(suite pairing.Suite, x kyber.Point, msg, sig []byte) error {
-	return bls.Verify(suite, x, msg, sig)
+// key X, which can be an aggregated key.
+func (s *Scheme) Verify(x kyber.Point, msg, sig []byte) error {
+	return s.bls.Verify(x, msg, sig)
 }
 
 // AggregateSignatures aggregates the signatures using a coefficient for each
-// one of them where c = H(pk) and H: G2 -> R with R = {1, ..., 2^128}
-func AggregateSignatures(suite pairing.Suite, sigs [][]byte, mask *sign.Mask) (kyber.Point, error) {
+// one of them where c = H(pk) and H: keyGroup -> R with R = {1, ..., 2^128}
+func (s *Scheme) AggregateSignatures(sigs [][]byte, mask *sign.Mask) (kyber.Point, error) {
 	if len(sigs) != mask.CountEnabled() {
 		return nil, errors.New("length of signatures and public keys must match")
 	}
@@ -99,7 +119,7 @@ func AggregateSignatures(suite pairing.Suite, sigs [][]byte, mask *sign.Mask) (k
 		return nil, err
 	}
 
-	agg := suite.G1().Point()
+	agg := s.bls.SignatureGroup().Point().Null()
 	for i, buf := range sigs {
 		peerIndex := mask.IndexOfNthEnabled(i)
 		if peerIndex < 0 {
@@ -108,7 +128,7 @@ func AggregateSignatures(suite pairing.Suite, sigs [][]byte, mask *sign.Mask) (k
 			return nil, errors.New("couldn't find the index")
 		}
 
-		sig := suite.G1().Point()
+		sig := s.bls.SignatureGroup().Point()
 		err = sig.UnmarshalBinary(buf)
 		if err != nil {
 			return nil, err
@@ -125,14 +145,14 @@ func AggregateSignatures(suite pairing.Suite, sigs [][]byte, mask *sign.Mask) (k
 
 // AggregatePublicKeys aggregates a set of public keys (similarly to
 // AggregateSignatures for signatures) using the hash function
-// H: G2 -> R with R = {1, ..., 2^128}.
-func AggregatePublicKeys(suite pairing.Suite, mask *sign.Mask) (kyber.Point, error) {
+// H: keyGroup -> R with R = {1, ..., 2^128}.
+func (s *Scheme) AggregatePublicKeys(mask *sign.Mask) (kyber.Point, error) {
 	coefs, err := hashPointToR(mask.Publics())
 	if err != nil {
 		return nil, err
 	}
 
-	agg := suite.G2().Point()
+	agg := s.bls.KeyGroup().Point().Null()
 	for i := 0; i < mask.CountEnabled(); i++ {
 		peerIndex := mask.IndexOfNthEnabled(i)
 		if peerIndex < 0 {
@@ -149,3 +169,36 @@ func AggregatePublicKeys(suite pairing.Suite, mask *sign.Mask) (kyber.Point, err
 
 	return agg, nil
 }
+
+// NewKeyPair creates a new BLS signing key pair. The private key x is a scalar
+// and the public key X is a point on curve G2.
+func NewKeyPair(suite pairing.Suite, random cipher.Stream) (kyber.Scalar, kyber.Point) {
+	return NewSchemeOnG1(suite).NewKeyPair(random)
+}
+
+// Sign creates a BLS signature S = x * H(m) on a message m using the private
+// key x. The signature S is a point on curve G1.
+func Sign(suite pairing.Suite, x kyber.Scalar, msg []byte) ([]byte, error) {
+	return NewSchemeOnG1(suite).Sign(x, msg)
+}
+
+// Verify checks the given BLS signature S on the message m using the public
+// key X by verifying that the equality e(H(m), X) == e(H(m), x*B2) ==
+// e(x*H(m), B2) == e(S, B2) holds where e is the pairing operation and B2 is
+// the base point from curve G2.
+func Verify(suite pairing.Suite, x kyber.Point, msg, sig []byte) error {
+	return NewSchemeOnG1(suite).Verify(x, msg, sig)
+}
+
+// AggregateSignatures aggregates the signatures using a coefficient for each
+// one of them where c = H(pk) and H: G2 -> R with R = {1, ..., 2^128}
+func AggregateSignatures(suite pairing.Suite, sigs [][]byte, mask *sign.Mask) (kyber.Point, error) {
+	return NewSchemeOnG1(suite).AggregateSignatures(sigs, mask)
+}
+
+// AggregatePublicKeys aggregates a set of public keys (similarly to
+// AggregateSignatures for signatures) using the hash function
+// H: G2 -> R with R = {1, ..., 2^128}.
+func AggregatePublicKeys(suite pairing.Suite, mask *sign.Mask) (kyber.Point, error) {
+	return NewSchemeOnG1(suite).AggregatePublicKeys(mask)
+}
@@ -136,6 +136,40 @@ func TestBDN_RogueAttack(t *testing.T) {
 	require.Error(t, Verify(suite, agg, msg, sig))
 }
 
+func TestBDN_SchemeOnG2(t *testing.T) {
+	msg := []byte("Hello Boneh-Lynn-Shacham")
+	suite := bn256.NewSuite()
+	scheme := NewSchemeOnG2(suite)
+	private1, public1 := scheme.NewKeyPair(random.New())
+	private2, public2 := scheme.NewKeyPair(random.New())
+	require.Equal(t, suite.G1().PointLen(), public1.MarshalSize())
+	sig1, err := scheme.Sign(private1, msg)
+	require.NoError(t, err)
+	sig2, err := scheme.Sign(private2, msg)
+	require.NoError(t, err)
+	require.Len(t, sig1, suite.G2().PointLen())
+
+	mask, _ := sign.NewMask(suite, []kyber.Point{public1, public2}, nil)
+	mask.SetBit(0, true)
+	mask.SetBit(1, true)
+	aggregatedSig, err := scheme.AggregateSignatures([][]byte{sig1, sig2}, mask)
+	require.NoError(t, err)
+	aggregatedKey, err := scheme.AggregatePublicKeys(mask)
+	require.NoError(t, err)
+	sig, err := aggregatedSig.MarshalBinary()
+	require.NoError(t, err)
+	require.NoError(t, scheme.Verify(aggregatedKey, msg, sig))
+
+	// rogue key attack
+	rogue := public1.Clone().Sub(public2, public1)
+	mask, _ = sign.NewMask(suite, []kyber.Point{public1, rogue}, nil)
+	mask.SetBit(0, true)
+	mask.SetBit(1, true)
+	aggregatedKey, err = scheme.AggregatePublicKeys(mask)
+	require.NoError(t, err)
+	require.Error(t, scheme.Verify(aggregatedKey, msg, sig2))
+}
+
 func Benchmark_BDN_AggregateSigs(b *testing.B) {
 	suite := bn256.NewSuite()
 	private1, public1 := NewKeyPair(suite, random.New())
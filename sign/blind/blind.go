@@ -0,0 +1,181 @@
+// Package blind implements blind signatures, where a signer signs a message
+// without learning it, nor being able to link the resulting signature to
+// the signing session. They are the basis of anonymous tokens and e-cash.
+//
+// The blind Schnorr signatures are the Clause Blind Schnorr signatures of
+// Fuchsbauer, Plouviez and Seurin, "Blind Schnorr Signatures and Signed
+// ElGamal Encryption in the Algebraic Group Model",
+// https://eprint.iacr.org/2019/877. The plain blind Schnorr signatures are
+// forgeable by a user opening many sessions concurrently, with the ROS
+// attack of Benhamouda et al., https://eprint.iacr.org/2020/945. In the
+// clause variant, the signer commits to two nonces in each session, and
+// answers the challenge of only one of them, chosen at random, which
+// defeats the attack. The unblinded signatures verify with schnorr.Verify.
+//
+// The blind BLS signatures multiply the hash of the message by a random
+// blinding factor, which is removed from the signature. The unblinded
+// signatures verify with bls.Verify.
+//
+// Both schemes run the same four steps: the user blinds the message, the
+// signer signs the blinded message, the user unblinds the signature, and
+// anyone verifies the unblinded signature of the message.
+package blind
+
+import (
+	"crypto/sha512"
+	"errors"
+
+	"go.dedis.ch/kyber/v3"
+	"go.dedis.ch/kyber/v3/sign/schnorr"
+	"go.dedis.ch/kyber/v3/util/random"
+)
+
+// SchnorrCommitment holds the two nonce commitments of a signer for a
+// session of blind Schnorr signature.
+type SchnorrCommitment struct {
+	R [2]kyber.Point
+}
+
+// SchnorrChallenge holds the blinded challenges of a user for the two
+// commitments of a session.
+type SchnorrChallenge struct {
+	C [2]kyber.Scalar
+}
+
+// SchnorrResponse holds the response of the signer to one of the two
+// challenges of a session, chosen by the signer.
+type SchnorrResponse struct {
+	Clause int
+	S      kyber.Scalar
+}
+
+// SchnorrSigner is the state of a signer for a session of blind Schnorr
+// signature. A session signs at most once.
+type SchnorrSigner struct {
+	suite   schnorr.Suite
+	private kyber.Scalar
+	k       [2]kyber.Scalar
+	commit  *SchnorrCommitment
+}
+
+// NewSchnorrSigner opens a new session for the signer holding the private
+// key. Its commitment is sent to the user.
+//
+// The two nonces are hedged: they are derived from the private key, fresh
+// randomness from crypto/rand, and randomness from suite.RandomStream(),
+// so that neither a broken nor a replayed random stream makes two sessions
+// share a nonce, which would leak the private key.
+func NewSchnorrSigner(suite schnorr.Suite, private kyber.Scalar) (*SchnorrSigner, error) {
+	x, err := private.MarshalBinary()
+	if err != nil {
+		return nil, err
+	}
+	fresh := random.Bits(256, false, random.New())
+	personalization := append([]byte("kyber blind schnorr nonce"), random.Bits(256, false, suite.RandomStream())...)
+	rand := random.NewHMACDRBGWithHash(sha512.New, x, fresh, personalization)
+	s := &SchnorrSigner{
+		suite:   suite,
+		private: private,
+		commit:  &SchnorrCommitment{},
+	}
+	for i := range s.k {
+		s.k[i] = suite.Scalar().Pick(rand)
+		s.commit.R[i] = suite.Point().Mul(s.k[i], nil)
+	}
+	return s, nil
+}
+
+// Commitment returns the commitment of the session.
+func (s *SchnorrSigner) Commitment() *SchnorrCommitment {
+	return s.commit
+}
+
+// Sign answers one of the blinded challenges of the user, chosen at random
+// from crypto/rand, as the choice must be unpredictable to the user to
+// defeat the ROS attack, and closes the session. The signer learns neither
+// the message nor the signature.
+func (s *SchnorrSigner) Sign(c *SchnorrChallenge) (*SchnorrResponse, error) {
+	if s.k[0] == nil {
+		return nil, errors.New("blind: session already used")
+	}
+	if c == nil || c.C[0] == nil || c.C[1] == nil {
+		return nil, errors.New("blind: no challenge provided")
+	}
+	var b [1]byte
+	random.New().XORKeyStream(b[:], b[:])
+	clause := int(b[0] & 1)
+	// r_b = k_b + c_b*x
+	S := s.suite.Scalar().Mul(c.C[clause], s.private)
+	S.Add(S, s.k[clause])
+	s.k = [2]kyber.Scalar{}
+	return &SchnorrResponse{Clause: clause, S: S}, nil
+}
+
+// SchnorrBlinding is the state of a user for a session of blind Schnorr
+// signature.
+type SchnorrBlinding struct {
+	suite  schnorr.Suite
+	public kyber.Point
+	commit *SchnorrCommitment
+	alpha  [2]kyber.Scalar
+	c      [2]kyber.Scalar
+	R      [2]kyber.Point // blinded commitments
+}
+
+// BlindSchnorr blinds the challenges of the message for the commitment of
+// the signer holding the public key. The challenge is sent to the signer.
+func BlindSchnorr(suite schnorr.Suite, public kyber.Point, commit *SchnorrCommitment, msg []byte) (*SchnorrBlinding, *SchnorrChallenge, error) {
+	if commit == nil || commit.R[0] == nil || commit.R[1] == nil {
+		return nil, nil, errors.New("blind: no commitment provided")
+	}
+	b := &SchnorrBlinding{suite: suite, public: public, commit: commit}
+	challenge := &SchnorrChallenge{}
+	for i := range commit.R {
+		// R'_i = R_i + alpha_i*G + beta_i*X and c_i = H(R'_i || X || m) + beta_i
+		b.alpha[i] = suite.Scalar().Pick(suite.RandomStream())
+		beta := suite.Scalar().Pick(suite.RandomStream())
+		b.R[i] = suite.Point().Mul(b.alpha[i], nil)
+		b.R[i].Add(b.R[i], commit.R[i])
+		b.R[i].Add(b.R[i], suite.Point().Mul(beta, public))
+		c, err := schnorr.Challenge(suite, public, b.R[i], msg)
+		if err != nil {
+			return nil, nil, err
+		}
+		b.c[i] = c.Add(c, beta)
+		challenge.C[i] = b.c[i]
+	}
+	return b, challenge, nil
+}
+
+// Unblind checks the response of the signer and returns the signature of
+// the message, which verifies with schnorr.Verify under the public key of
+// the signer.
+func (b *SchnorrBlinding) Unblind(resp *SchnorrResponse) ([]byte, error) {
+	if resp == nil || resp.S == nil || (resp.Clause != 0 && resp.Clause != 1) {
+		return nil, errors.New("blind: invalid response")
+	}
+	i := resp.Clause
+	// r_b*G == R_b + c_b*X
+	left := b.suite.Point().Mul(resp.S, nil)
+	right := b.suite.Point().Mul(b.c[i], b.public)
+	right.Add(right, b.commit.R[i])
+	if !left.Equal(right) {
+		return nil, errors.New("blind: invalid response")
+	}
+	// s' = r_b + alpha_b, and (R'_b, s') is a signature
+	S := b.suite.Scalar().Add(resp.S, b.alpha[i])
+	R, err := b.R[i].MarshalBinary()
+	if err != nil {
+		return nil, err
+	}
+	s, err := S.MarshalBinary()
+	if err != nil {
+		return nil, err
+	}
+	return append(R, s...), nil
+}
+
+// VerifySchnorr checks an unblinded signature of msg. It is schnorr.Verify.
+func VerifySchnorr(g kyber.Group, public kyber.Point, msg, sig []byte) error {
+	return schnorr.Verify(g, public, msg, sig)
+}
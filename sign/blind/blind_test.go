@@ -0,0 +1,113 @@
+package blind
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"go.dedis.ch/kyber/v3/group/edwards25519"
+	"go.dedis.ch/kyber/v3/group/nist"
+	"go.dedis.ch/kyber/v3/pairing/bn256"
+	"go.dedis.ch/kyber/v3/sign/bls"
+	"go.dedis.ch/kyber/v3/sign/eddsa"
+	"go.dedis.ch/kyber/v3/sign/schnorr"
+	"go.dedis.ch/kyber/v3/util/random"
+)
+
+func TestBlindSchnorr(t *testing.T) {
+	msg := []byte("Hello Blind Schnorr")
+	for _, suite := range []schnorr.Suite{edwards25519.NewBlakeSHA256Ed25519(), nist.NewBlakeSHA256P256()} {
+		x := suite.Scalar().Pick(suite.RandomStream())
+		X := suite.Point().Mul(x, nil)
+
+		signer, err := NewSchnorrSigner(suite, x)
+		require.NoError(t, err)
+		user, challenge, err := BlindSchnorr(suite, X, signer.Commitment(), msg)
+		require.NoError(t, err)
+		resp, err := signer.Sign(challenge)
+		require.NoError(t, err)
+		sig, err := user.Unblind(resp)
+		require.NoError(t, err)
+		require.NoError(t, VerifySchnorr(suite, X, msg, sig))
+		require.NoError(t, schnorr.Verify(suite, X, msg, sig))
+		require.Error(t, schnorr.Verify(suite, X, []byte("other"), sig))
+
+		// the signer does not see the signature
+		R := suite.Point()
+		require.NoError(t, R.UnmarshalBinary(sig[:suite.PointLen()]))
+		for _, Ri := range signer.Commitment().R {
+			require.False(t, R.Equal(Ri))
+		}
+
+		// a session signs once
+		_, err = signer.Sign(challenge)
+		require.Error(t, err)
+
+		// a wrong response is detected
+		resp.S = suite.Scalar().Add(resp.S, suite.Scalar().One())
+		_, err = user.Unblind(resp)
+		require.Error(t, err)
+	}
+}
+
+func TestBlindSchnorrEdDSA(t *testing.T) {
+	suite := edwards25519.NewBlakeSHA256Ed25519()
+	msg := []byte("Hello Blind Schnorr")
+	x := suite.Scalar().Pick(suite.RandomStream())
+	X := suite.Point().Mul(x, nil)
+	signer, err := NewSchnorrSigner(suite, x)
+	require.NoError(t, err)
+	user, challenge, err := BlindSchnorr(suite, X, signer.Commitment(), msg)
+	require.NoError(t, err)
+	resp, err := signer.Sign(challenge)
+	require.NoError(t, err)
+	sig, err := user.Unblind(resp)
+	require.NoError(t, err)
+	require.NoError(t, eddsa.Verify(X, msg, sig))
+}
+
+func TestBlindSchnorrReplayedStream(t *testing.T) {
+	seed := []byte("some seed of at least thirty-two bytes")
+	newSuite := func() schnorr.Suite {
+		return edwards25519.NewBlakeSHA256Ed25519WithRand(random.NewHMACDRBG(seed, nil, nil))
+	}
+	x := newSuite().Scalar().Pick(random.New())
+
+	// the nonces differ even when the random stream of the suite is replayed
+	s1, err := NewSchnorrSigner(newSuite(), x)
+	require.NoError(t, err)
+	s2, err := NewSchnorrSigner(newSuite(), x)
+	require.NoError(t, err)
+	for i := range s1.Commitment().R {
+		require.False(t, s1.Commitment().R[i].Equal(s2.Commitment().R[i]))
+	}
+}
+
+func TestBlindBLS(t *testing.T) {
+	suite := bn256.NewSuite()
+	msg := []byte("Hello Blind BLS")
+	x, X := bls.NewKeyPair(suite, suite.RandomStream())
+
+	user, blinded, err := BlindBLS(suite, msg)
+	require.NoError(t, err)
+	blindSig, err := SignBLS(suite, x, blinded)
+	require.NoError(t, err)
+	sig, err := user.Unblind(X, blindSig)
+	require.NoError(t, err)
+	require.NoError(t, VerifyBLS(suite, X, msg, sig))
+	require.NoError(t, bls.Verify(suite, X, msg, sig))
+	require.NotEqual(t, blindSig, sig)
+
+	// the blinded signature is checked against the key of the signer
+	y, Y := bls.NewKeyPair(suite, suite.RandomStream())
+	_, err = user.Unblind(Y, blindSig)
+	require.Error(t, err)
+	other, err := SignBLS(suite, y, blinded)
+	require.NoError(t, err)
+	_, err = user.Unblind(X, other)
+	require.Error(t, err)
+
+	null, err := suite.G1().Point().Null().MarshalBinary()
+	require.NoError(t, err)
+	_, err = SignBLS(suite, x, null)
+	require.Error(t, err)
+}
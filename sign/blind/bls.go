@@ -0,0 +1,68 @@
+package blind
+
+import (
+	"errors"
+
+	"go.dedis.ch/kyber/v3"
+	"go.dedis.ch/kyber/v3/pairing"
+	"go.dedis.ch/kyber/v3/sign/bls"
+)
+
+// BLSBlinding is the state of a user for a blind BLS signature.
+type BLSBlinding struct {
+	scheme *bls.Scheme
+	r      kyber.Scalar
+	M      kyber.Point // blinded hash of the message
+}
+
+// BlindBLS blinds the hash of the message, as r*H(m) for a random r, and
+// returns the blinded message to send to the signer.
+func BlindBLS(suite pairing.Suite, msg []byte) (*BLSBlinding, []byte, error) {
+	scheme := bls.NewSchemeOnG1(suite)
+	H, err := scheme.HashToPoint(msg)
+	if err != nil {
+		return nil, nil, err
+	}
+	b := &BLSBlinding{
+		scheme: scheme,
+		r:      suite.G1().Scalar().Pick(suite.RandomStream()),
+	}
+	b.M = suite.G1().Point().Mul(b.r, H)
+	buf, err := b.M.MarshalBinary()
+	if err != nil {
+		return nil, nil, err
+	}
+	return b, buf, nil
+}
+
+// SignBLS signs a blinded message with the private key.
+func SignBLS(suite pairing.Suite, private kyber.Scalar, blinded []byte) ([]byte, error) {
+	M := suite.G1().Point()
+	if err := M.UnmarshalBinary(blinded); err != nil {
+		return nil, err
+	}
+	if M.Equal(suite.G1().Point().Null()) {
+		return nil, errors.New("blind: blinded message is the identity")
+	}
+	return M.Mul(private, M).MarshalBinary()
+}
+
+// Unblind checks the blinded signature under the public key of the signer
+// and returns the signature of the message, which verifies with bls.Verify.
+func (b *BLSBlinding) Unblind(public kyber.Point, blindSig []byte) ([]byte, error) {
+	suite := b.scheme.Suite()
+	S := suite.G1().Point()
+	if err := S.UnmarshalBinary(blindSig); err != nil {
+		return nil, err
+	}
+	if !b.scheme.Pair(S, suite.G2().Point().Base()).Equal(b.scheme.Pair(b.M, public)) {
+		return nil, errors.New("blind: invalid blinded signature")
+	}
+	rInv := suite.G1().Scalar().Inv(b.r)
+	return S.Mul(rInv, S).MarshalBinary()
+}
+
+// VerifyBLS checks an unblinded signature of msg. It is bls.Verify.
+func VerifyBLS(suite pairing.Suite, public kyber.Point, msg, sig []byte) error {
+	return bls.Verify(suite, public, msg, sig)
+}
@@ -0,0 +1,228 @@
+package bls
+
+import (
+	"crypto/cipher"
+	"errors"
+	"fmt"
+	"runtime"
+	"sort"
+	"sync"
+
+	"go.dedis.ch/kyber/v3"
+	"go.dedis.ch/kyber/v3/pairing"
+	"go.dedis.ch/kyber/v3/util/random"
+)
+
+// BatchError is returned by VerifyBatch when some signatures of the batch
+// are invalid.
+type BatchError struct {
+	// Invalid holds the indices of the invalid signatures, in increasing
+	// order.
+	Invalid []int
+}
+
+func (e *BatchError) Error() string {
+	return fmt.Sprintf("bls: invalid signatures in batch at indices %v", e.Invalid)
+}
+
+// multiPairer is implemented by pairing suites providing a fast computation
+// of the sum of many pairings, such as bn256.
+type multiPairer interface {
+	MultiPair(p1s, p2s []kyber.Point) kyber.Point
+}
+
+// multiPair returns the sum of the pairings of sigs[i] and keys[i].
+func (s *Scheme) multiPair(sigs, keys []kyber.Point) kyber.Point {
+	p1s, p2s := sigs, keys
+	if !s.sigOnG1 {
+		p1s, p2s = keys, sigs
+	}
+	if mp, ok := s.suite.(multiPairer); ok {
+		return mp.MultiPair(p1s, p2s)
+	}
+	sum := s.suite.GT().Point().Null()
+	for i := range p1s {
+		sum.Add(sum, s.suite.Pair(p1s[i], p2s[i]))
+	}
+	return sum
+}
+
+// batchEntry is a decoded signature of a batch. Entries of the same message
+// share the same hash point and key.
+type batchEntry struct {
+	index int
+	X     kyber.Point
+	S     kyber.Point
+	H     kyber.Point
+	msg   string
+}
+
+// VerifyBatch checks that sigs[i] is a valid signature of msgs[i] under
+// publics[i], for every i, with the package-level bare scheme. See
+// Scheme.VerifyBatch.
+func VerifyBatch(suite pairing.Suite, publics []kyber.Point, msgs, sigs [][]byte) error {
+	return NewSchemeOnG1(suite).VerifyBatch(publics, msgs, sigs)
+}
+
+// VerifyBatch checks that sigs[i] is a valid signature of msgs[i] under
+// publics[i], for every i. The signatures are independent: unlike
+// AggregateVerify, the messages can repeat in every scheme. It returns nil
+// if all signatures are valid, and a *BatchError holding the indices of the
+// invalid ones otherwise.
+//
+// The signatures are checked together, by verifying
+//
+//	e(sum r_i*S_i, B) == prod_m e(H(m), sum_{i: msgs[i] = m} r_i*X_i)
+//
+// for random 128-bit coefficients r_i, so that a single pairing is computed
+// per distinct message. The coefficients must be unpredictable, as invalid
+// signatures whose errors cancel out for known coefficients would pass:
+// they are drawn from crypto/rand, never from the random stream of the
+// suite. The hashing of the messages and the pairings are split across
+// GOMAXPROCS goroutines, and the pairings are computed with a multi-pairing
+// when the suite provides one, as bn256 does. If the batch fails, it is
+// split in halves which are checked recursively, to locate the invalid
+// signatures.
+func (s *Scheme) VerifyBatch(publics []kyber.Point, msgs, sigs [][]byte) error {
+	if len(publics) != len(msgs) || len(msgs) != len(sigs) {
+		return errors.New("bls: mismatched number of public keys, messages and signatures")
+	}
+	if _, ok := s.sigGroup.Point().(hashablePoint); !ok {
+		return errors.New("bls: point needs to implement hashablePoint")
+	}
+
+	var invalid []int
+	entries := make([]*batchEntry, 0, len(sigs))
+	for i := range sigs {
+		S := s.sigGroup.Point()
+		if err := S.UnmarshalBinary(sigs[i]); err != nil {
+			invalid = append(invalid, i)
+			continue
+		}
+		if err := s.validateKey(publics[i]); err != nil {
+			invalid = append(invalid, i)
+			continue
+		}
+		msg, err := s.message(publics[i], msgs[i])
+		if err != nil {
+			invalid = append(invalid, i)
+			continue
+		}
+		entries = append(entries, &batchEntry{index: i, X: publics[i], S: S, msg: string(msg)})
+	}
+	s.hashEntries(entries)
+
+	rand := random.New()
+	if !s.checkBatch(entries, rand) {
+		invalid = append(invalid, s.locateInvalid(entries, rand)...)
+	}
+	if len(invalid) == 0 {
+		return nil
+	}
+	sort.Ints(invalid)
+	return &BatchError{Invalid: invalid}
+}
+
+// parallel calls f(i) for every i in [0, n) over GOMAXPROCS goroutines.
+func parallel(n int, f func(i int)) {
+	workers := runtime.GOMAXPROCS(0)
+	if workers > n {
+		workers = n
+	}
+	var wg sync.WaitGroup
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func(w int) {
+			defer wg.Done()
+			for i := w; i < n; i += workers {
+				f(i)
+			}
+		}(w)
+	}
+	wg.Wait()
+}
+
+// hashEntries hashes each distinct message of the entries once.
+func (s *Scheme) hashEntries(entries []*batchEntry) {
+	groups := make(map[string][]*batchEntry)
+	var msgs []string
+	for _, e := range entries {
+		if _, ok := groups[e.msg]; !ok {
+			msgs = append(msgs, e.msg)
+		}
+		groups[e.msg] = append(groups[e.msg], e)
+	}
+	parallel(len(msgs), func(i int) {
+		// the suite supports hashing, as checked by VerifyBatch
+		H, _ := hashToPoint(s.sigGroup, s.dst, []byte(msgs[i]))
+		for _, e := range groups[msgs[i]] {
+			e.H = H
+		}
+	})
+}
+
+// checkBatch returns whether the entries satisfy the batch equation.
+func (s *Scheme) checkBatch(entries []*batchEntry, rand cipher.Stream) bool {
+	if len(entries) == 0 {
+		return true
+	}
+	var groups [][]*batchEntry
+	byMsg := make(map[string]int)
+	r := make(map[*batchEntry]kyber.Scalar, len(entries))
+	for _, e := range entries {
+		i, ok := byMsg[e.msg]
+		if !ok {
+			i = len(groups)
+			byMsg[e.msg] = i
+			groups = append(groups, nil)
+		}
+		groups[i] = append(groups[i], e)
+		r[e] = s.keyGroup.Scalar().SetBytes(random.Bits(128, false, rand))
+	}
+
+	workers := runtime.GOMAXPROCS(0)
+	if workers > len(groups) {
+		workers = len(groups)
+	}
+	lefts := make([]kyber.Point, workers)
+	sums := make([]kyber.Point, workers)
+	parallel(workers, func(w int) {
+		var hs, keys []kyber.Point
+		sum := s.sigGroup.Point().Null()
+		for g := w; g < len(groups); g += workers {
+			key := s.keyGroup.Point().Null()
+			for _, e := range groups[g] {
+				key.Add(key, s.keyGroup.Point().Mul(r[e], e.X))
+				sum.Add(sum, s.sigGroup.Point().Mul(r[e], e.S))
+			}
+			hs = append(hs, groups[g][0].H)
+			keys = append(keys, key)
+		}
+		lefts[w] = s.multiPair(hs, keys)
+		sums[w] = sum
+	})
+
+	left := s.suite.GT().Point().Null()
+	sum := s.sigGroup.Point().Null()
+	for w := range lefts {
+		left.Add(left, lefts[w])
+		sum.Add(sum, sums[w])
+	}
+	right := s.Pair(sum, s.keyGroup.Point().Base())
+	return left.Equal(right)
+}
+
+// locateInvalid returns the indices of the invalid entries of a batch
+// which failed, by bisection.
+func (s *Scheme) locateInvalid(entries []*batchEntry, rand cipher.Stream) []int {
+	if len(entries) == 1 {
+		return []int{entries[0].index}
+	}
+	var invalid []int
+	for _, half := range [][]*batchEntry{entries[:len(entries)/2], entries[len(entries)/2:]} {
+		if !s.checkBatch(half, rand) {
+			invalid = append(invalid, s.locateInvalid(half, rand)...)
+		}
+	}
+	return invalid
+}
@@ -0,0 +1,115 @@
+package bls
+
+import (
+	"bytes"
+	"crypto/cipher"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"go.dedis.ch/kyber/v3"
+	"go.dedis.ch/kyber/v3/pairing"
+	"go.dedis.ch/kyber/v3/pairing/bn256"
+	"go.dedis.ch/kyber/v3/util/random"
+)
+
+// batch returns n signatures under n keys, of nmsgs distinct messages.
+func batch(t testing.TB, s *Scheme, n, nmsgs int) ([]kyber.Point, [][]byte, [][]byte) {
+	publics := make([]kyber.Point, n)
+	msgs := make([][]byte, n)
+	sigs := make([][]byte, n)
+	for i := range sigs {
+		var x kyber.Scalar
+		x, publics[i] = s.NewKeyPair(random.New())
+		msgs[i] = []byte(fmt.Sprintf("message %d", i%nmsgs))
+		var err error
+		sigs[i], err = s.Sign(x, msgs[i])
+		require.NoError(t, err)
+	}
+	return publics, msgs, sigs
+}
+
+func TestVerifyBatch(t *testing.T) {
+	suite := bn256.NewSuite()
+	for _, s := range []*Scheme{NewSchemeOnG1(suite), NewSchemeOnG2(suite), NewAugScheme(suite)} {
+		// repeated messages are fine
+		publics, msgs, sigs := batch(t, s, 12, 5)
+		require.NoError(t, s.VerifyBatch(publics, msgs, sigs))
+		require.NoError(t, s.VerifyBatch(nil, nil, nil))
+
+		// swapping the signatures of the same message is detected
+		sigs[0], sigs[5] = sigs[5], sigs[0]
+		err := s.VerifyBatch(publics, msgs, sigs)
+		require.Equal(t, &BatchError{Invalid: []int{0, 5}}, err)
+		sigs[0], sigs[5] = sigs[5], sigs[0]
+
+		sigs[3] = sigs[3][:10]
+		msgs[7] = []byte("other")
+		publics[11] = s.KeyGroup().Point().Pick(random.New())
+		err = s.VerifyBatch(publics, msgs, sigs)
+		require.Equal(t, &BatchError{Invalid: []int{3, 7, 11}}, err)
+	}
+
+	publics, msgs, sigs := batch(t, NewSchemeOnG1(suite), 3, 1)
+	require.NoError(t, VerifyBatch(suite, publics, msgs, sigs))
+	require.Error(t, VerifyBatch(suite, publics[:2], msgs, sigs))
+}
+
+// fixedSuite is a pairing suite whose random stream is predictable.
+type fixedSuite struct {
+	pairing.Suite
+}
+
+func (fixedSuite) RandomStream() cipher.Stream {
+	return random.FromReader(bytes.NewReader(bytes.Repeat([]byte{1}, 1<<16)))
+}
+
+func TestVerifyBatchOffsettingSignatures(t *testing.T) {
+	s := NewSchemeOnG1(fixedSuite{bn256.NewSuite()})
+	publics, msgs, sigs := batch(t, s, 2, 1)
+
+	// the errors of the signatures cancel out for equal coefficients
+	D := s.SignatureGroup().Point().Pick(random.New())
+	for i, sig := range sigs {
+		S := s.SignatureGroup().Point()
+		require.NoError(t, S.UnmarshalBinary(sig))
+		if i == 0 {
+			S.Add(S, D)
+		} else {
+			S.Sub(S, D)
+		}
+		var err error
+		sigs[i], err = S.MarshalBinary()
+		require.NoError(t, err)
+	}
+	aggregated, err := s.AggregateSignatures(sigs...)
+	require.NoError(t, err)
+	require.NoError(t, s.Verify(s.AggregatePublicKeys(publics...), msgs[0], aggregated))
+
+	err = s.VerifyBatch(publics, msgs, sigs)
+	require.Equal(t, &BatchError{Invalid: []int{0, 1}}, err)
+}
+
+func BenchmarkVerifyBatch(b *testing.B) {
+	suite := bn256.NewSuite()
+	s := NewSchemeOnG1(suite)
+	for _, n := range []int{16, 128} {
+		publics, msgs, sigs := batch(b, s, n, n/4)
+		b.Run(fmt.Sprintf("Verify/%d", n), func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				for j := range sigs {
+					if err := s.Verify(publics[j], msgs[j], sigs[j]); err != nil {
+						b.Fatal(err)
+					}
+				}
+			}
+		})
+		b.Run(fmt.Sprintf("VerifyBatch/%d", n), func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				if err := s.VerifyBatch(publics, msgs, sigs); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,369 @@
+package bls
+
+import (
+	"crypto/cipher"
+	"errors"
+	"fmt"
+
+	"go.dedis.ch/kyber/v3"
+	"go.dedis.ch/kyber/v3/pairing"
+)
+
+// The three signature schemes of the CFRG draft "BLS Signatures",
+// https://datatracker.ietf.org/doc/draft-irtf-cfrg-bls-signature/, which
+// differ in how they prevent rogue public-key attacks on aggregation. They
+// are implemented after the design of the draft, but are not compatible
+// with it: the draft's ciphersuites are defined over BLS12-381 with the
+// hash-to-curve of RFC 9380, while a Scheme uses the groups and the hashing
+// to the curve of the given pairing suite, and its own tags.
+const (
+	// Basic requires the messages of an aggregate signature to be
+	// distinct.
+	Basic = "NUL"
+	// MessageAugmentation prepends the public key of the signer to each
+	// message, which makes the signed messages distinct.
+	MessageAugmentation = "AUG"
+	// ProofOfPossession requires every public key to be registered with a
+	// proof of possession of its private key, checked with PopVerify. It
+	// allows aggregate signatures on a single message to be verified
+	// against the aggregated public key with FastAggregateVerify.
+	ProofOfPossession = "POP"
+)
+
+// Scheme is a BLS signature scheme over a pairing suite. Either the
+// signatures are points of G1 and the public keys points of G2, which gives
+// short signatures, or the signatures are points of G2 and the public keys
+// points of G1, which gives short public keys.
+//
+// Each scheme hashes messages with its own domain separation tag (DST),
+// "BLS_SIG_<group>_<scheme>_" where group is the name of the group of the
+// signatures, so that a signature of a scheme is never valid in another
+// one. These tags are specific to kyber and differ from the ciphersuite IDs
+// of the draft. Proofs of possession use the tag
+// "BLS_POP_<group>_POP_". The bare schemes returned by NewSchemeOnG1 and
+// NewSchemeOnG2 hash messages without a tag.
+type Scheme struct {
+	suite    pairing.Suite
+	sigGroup kyber.Group
+	keyGroup kyber.Group
+	sigOnG1  bool
+	kind     string
+	dst      []byte
+	popDST   []byte
+}
+
+func newScheme(suite pairing.Suite, sigOnG1 bool, kind string, tagged bool) *Scheme {
+	s := &Scheme{suite: suite, sigOnG1: sigOnG1, kind: kind}
+	if sigOnG1 {
+		s.sigGroup, s.keyGroup = suite.G1(), suite.G2()
+	} else {
+		s.sigGroup, s.keyGroup = suite.G2(), suite.G1()
+	}
+	if tagged {
+		name := s.sigGroup.String()
+		s.dst = []byte("BLS_SIG_" + name + "_" + kind + "_")
+		s.popDST = []byte("BLS_POP_" + name + "_POP_")
+	}
+	return s
+}
+
+// NewSchemeOnG1 returns the bare BLS scheme of the package-level functions,
+// with signatures on G1. Its messages are hashed without domain separation
+// tag and, as in the basic scheme, aggregate signatures require distinct
+// messages.
+func NewSchemeOnG1(suite pairing.Suite) *Scheme {
+	return newScheme(suite, true, Basic, false)
+}
+
+// NewSchemeOnG2 is like NewSchemeOnG1, with signatures on G2.
+func NewSchemeOnG2(suite pairing.Suite) *Scheme {
+	return newScheme(suite, false, Basic, false)
+}
+
+// NewBasicScheme returns the basic scheme over the suite, with signatures
+// on G1.
+func NewBasicScheme(suite pairing.Suite) *Scheme {
+	return newScheme(suite, true, Basic, true)
+}
+
+// NewAugScheme returns the message augmentation scheme over the suite,
+// with signatures on G1.
+func NewAugScheme(suite pairing.Suite) *Scheme {
+	return newScheme(suite, true, MessageAugmentation, true)
+}
+
+// NewPopScheme returns the proof of possession scheme over the suite, with
+// signatures on G1.
+func NewPopScheme(suite pairing.Suite) *Scheme {
+	return newScheme(suite, true, ProofOfPossession, true)
+}
+
+// NewBasicSchemeOnG2 returns the basic scheme over the suite, with
+// signatures on G2.
+func NewBasicSchemeOnG2(suite pairing.Suite) *Scheme {
+	return newScheme(suite, false, Basic, true)
+}
+
+// NewAugSchemeOnG2 returns the message augmentation scheme over the suite,
+// with signatures on G2.
+func NewAugSchemeOnG2(suite pairing.Suite) *Scheme {
+	return newScheme(suite, false, MessageAugmentation, true)
+}
+
+// NewPopSchemeOnG2 returns the proof of possession scheme over the suite,
+// with signatures on G2.
+func NewPopSchemeOnG2(suite pairing.Suite) *Scheme {
+	return newScheme(suite, false, ProofOfPossession, true)
+}
+
+// Kind returns Basic, MessageAugmentation or ProofOfPossession.
+func (s *Scheme) Kind() string {
+	return s.kind
+}
+
+// Suite returns the pairing suite of the scheme.
+func (s *Scheme) Suite() pairing.Suite {
+	return s.suite
+}
+
+// SignatureGroup returns the group of the signatures.
+func (s *Scheme) SignatureGroup() kyber.Group {
+	return s.sigGroup
+}
+
+// KeyGroup returns the group of the public keys.
+func (s *Scheme) KeyGroup() kyber.Group {
+	return s.keyGroup
+}
+
+// Pair returns the pairing of a point of the signature group with a point
+// of the key group, whatever the order of the groups in the suite.
+func (s *Scheme) Pair(sig, key kyber.Point) kyber.Point {
+	if s.sigOnG1 {
+		return s.suite.Pair(sig, key)
+	}
+	return s.suite.Pair(key, sig)
+}
+
+// HashToPoint hashes msg to a point of the signature group, with the tag of
+// the signatures of the scheme.
+func (s *Scheme) HashToPoint(msg []byte) (kyber.Point, error) {
+	return hashToPoint(s.sigGroup, s.dst, msg)
+}
+
+// hashToPoint hashes msg to a point of g, prefixed with the length of the
+// domain separation tag and the tag itself if there is one.
+func hashToPoint(g kyber.Group, dst, msg []byte) (kyber.Point, error) {
+	hashable, ok := g.Point().(hashablePoint)
+	if !ok {
+		return nil, errors.New("bls: point needs to implement hashablePoint")
+	}
+	if dst == nil {
+		return hashable.Hash(msg), nil
+	}
+	buf := make([]byte, 0, 1+len(dst)+len(msg))
+	buf = append(buf, byte(len(dst)))
+	buf = append(buf, dst...)
+	buf = append(buf, msg...)
+	return hashable.Hash(buf), nil
+}
+
+// NewKeyPair creates a new key pair. The private key x is a scalar and the
+// public key X is a point of the key group.
+func (s *Scheme) NewKeyPair(random cipher.Stream) (kyber.Scalar, kyber.Point) {
+	x := s.keyGroup.Scalar().Pick(random)
+	X := s.keyGroup.Point().Mul(x, nil)
+	return x, X
+}
+
+// validateKey checks, as KeyValidate of the draft does, that the public key
+// is not the identity and lies in the prime-order subgroup: the points of
+// the twist of bn256 decode without a subgroup check. The bare schemes do
+// not check the keys.
+func (s *Scheme) validateKey(X kyber.Point) error {
+	if s.dst == nil {
+		return nil
+	}
+	null := s.keyGroup.Point().Null()
+	if X == nil || X.Equal(null) {
+		return errors.New("bls: invalid public key")
+	}
+	// q*X, with q the order of the group, is (q-1)*X + X
+	Q := s.keyGroup.Point().Mul(s.keyGroup.Scalar().SetInt64(-1), X)
+	if !Q.Add(Q, X).Equal(null) {
+		return errors.New("bls: public key not in the prime-order subgroup")
+	}
+	return nil
+}
+
+// message returns the message actually signed by the owner of the public
+// key X for msg: the encoding of X followed by msg in the message
+// augmentation scheme, msg otherwise.
+func (s *Scheme) message(X kyber.Point, msg []byte) ([]byte, error) {
+	if s.kind != MessageAugmentation {
+		return msg, nil
+	}
+	buf, err := X.MarshalBinary()
+	if err != nil {
+		return nil, err
+	}
+	return append(buf, msg...), nil
+}
+
+// coreSign returns x * H(msg) with the given tag.
+func (s *Scheme) coreSign(dst []byte, x kyber.Scalar, msg []byte) ([]byte, error) {
+	HM, err := hashToPoint(s.sigGroup, dst, msg)
+	if err != nil {
+		return nil, err
+	}
+	return HM.Mul(x, HM).MarshalBinary()
+}
+
+// coreAggregateVerify checks that e(sig, B) == prod e(H(msgs[i]), publics[i])
+// where B is the base point of the key group.
+func (s *Scheme) coreAggregateVerify(dst []byte, publics []kyber.Point, msgs [][]byte, sig []byte) error {
+	if len(publics) == 0 || len(publics) != len(msgs) {
+		return errors.New("bls: invalid number of public keys or messages")
+	}
+	S := s.sigGroup.Point()
+	if err := S.UnmarshalBinary(sig); err != nil {
+		return err
+	}
+	left := s.suite.GT().Point().Null()
+	for i, X := range publics {
+		if err := s.validateKey(X); err != nil {
+			return err
+		}
+		HM, err := hashToPoint(s.sigGroup, dst, msgs[i])
+		if err != nil {
+			return err
+		}
+		left.Add(left, s.Pair(HM, X))
+	}
+	right := s.Pair(S, s.keyGroup.Point().Base())
+	if !left.Equal(right) {
+		return errors.New("bls: invalid signature")
+	}
+	return nil
+}
+
+// Sign creates a signature of msg with the private key x.
+func (s *Scheme) Sign(x kyber.Scalar, msg []byte) ([]byte, error) {
+	msg, err := s.message(s.keyGroup.Point().Mul(x, nil), msg)
+	if err != nil {
+		return nil, err
+	}
+	return s.coreSign(s.dst, x, msg)
+}
+
+// Verify checks the signature of msg under the public key X.
+func (s *Scheme) Verify(X kyber.Point, msg, sig []byte) error {
+	if err := s.validateKey(X); err != nil {
+		return err
+	}
+	msg, err := s.message(X, msg)
+	if err != nil {
+		return err
+	}
+	return s.coreAggregateVerify(s.dst, []kyber.Point{X}, [][]byte{msg}, sig)
+}
+
+// AggregateSignatures combines signatures, possibly of different messages.
+func (s *Scheme) AggregateSignatures(sigs ...[]byte) ([]byte, error) {
+	sig := s.sigGroup.Point().Null()
+	for _, sigBytes := range sigs {
+		sigToAdd := s.sigGroup.Point()
+		if err := sigToAdd.UnmarshalBinary(sigBytes); err != nil {
+			return nil, err
+		}
+		sig.Add(sig, sigToAdd)
+	}
+	return sig.MarshalBinary()
+}
+
+// AggregatePublicKeys returns the sum of the public keys. An aggregate
+// signature of a single message can be verified against it, which is only
+// secure in the proof of possession scheme.
+func (s *Scheme) AggregatePublicKeys(Xs ...kyber.Point) kyber.Point {
+	aggregated := s.keyGroup.Point().Null()
+	for _, X := range Xs {
+		aggregated.Add(aggregated, X)
+	}
+	return aggregated
+}
+
+// AggregateVerify checks an aggregate signature where publics[i] signed
+// msgs[i]. In the basic scheme, the messages must be distinct.
+func (s *Scheme) AggregateVerify(publics []kyber.Point, msgs [][]byte, sig []byte) error {
+	if len(publics) != len(msgs) {
+		return errors.New("bls: invalid number of public keys or messages")
+	}
+	if s.kind == Basic && !distinct(msgs) {
+		return errors.New("bls: messages must be distinct")
+	}
+	augmented := make([][]byte, len(msgs))
+	for i, msg := range msgs {
+		m, err := s.message(publics[i], msg)
+		if err != nil {
+			return err
+		}
+		augmented[i] = m
+	}
+	return s.coreAggregateVerify(s.dst, publics, augmented, sig)
+}
+
+func (s *Scheme) checkPop() error {
+	if s.kind != ProofOfPossession {
+		return fmt.Errorf("bls: proofs of possession are not part of the %s scheme", s.kind)
+	}
+	return nil
+}
+
+// PopProve returns a proof of possession of the private key x, which is
+// a signature of the public key with the tag of proofs of possession.
+func (s *Scheme) PopProve(x kyber.Scalar) ([]byte, error) {
+	if err := s.checkPop(); err != nil {
+		return nil, err
+	}
+	X, err := s.keyGroup.Point().Mul(x, nil).MarshalBinary()
+	if err != nil {
+		return nil, err
+	}
+	return s.coreSign(s.popDST, x, X)
+}
+
+// PopVerify checks the proof of possession of the private key of X.
+func (s *Scheme) PopVerify(X kyber.Point, proof []byte) error {
+	if err := s.checkPop(); err != nil {
+		return err
+	}
+	if err := s.validateKey(X); err != nil {
+		return err
+	}
+	buf, err := X.MarshalBinary()
+	if err != nil {
+		return err
+	}
+	if err := s.coreAggregateVerify(s.popDST, []kyber.Point{X}, [][]byte{buf}, proof); err != nil {
+		return errors.New("bls: invalid proof of possession")
+	}
+	return nil
+}
+
+// FastAggregateVerify checks an aggregate signature of the same message by
+// all the given public keys, against the sum of the keys. It is only secure
+// if the proof of possession of every key has been checked with PopVerify.
+func (s *Scheme) FastAggregateVerify(publics []kyber.Point, msg, sig []byte) error {
+	if err := s.checkPop(); err != nil {
+		return err
+	}
+	if len(publics) == 0 {
+		return errors.New("bls: no public keys")
+	}
+	for _, X := range publics {
+		if err := s.validateKey(X); err != nil {
+			return err
+		}
+	}
+	return s.Verify(s.AggregatePublicKeys(publics...), msg, sig)
+}
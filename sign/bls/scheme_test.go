@@ -0,0 +1,212 @@
+package bls
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"go.dedis.ch/kyber/v3"
+	"go.dedis.ch/kyber/v3/pairing/bn256"
+	"go.dedis.ch/kyber/v3/util/random"
+)
+
+func allSchemes(suite *bn256.Suite) []*Scheme {
+	return []*Scheme{
+		NewBasicScheme(suite), NewAugScheme(suite), NewPopScheme(suite),
+		NewBasicSchemeOnG2(suite), NewAugSchemeOnG2(suite), NewPopSchemeOnG2(suite),
+	}
+}
+
+func TestScheme(t *testing.T) {
+	suite := bn256.NewSuite()
+	msg := []byte("Hello Boneh-Lynn-Shacham")
+	schemes := allSchemes(suite)
+	for _, s := range schemes {
+		x, X := s.NewKeyPair(random.New())
+		sig, err := s.Sign(x, msg)
+		require.NoError(t, err)
+		require.NoError(t, s.Verify(X, msg, sig), s.Kind())
+		require.Error(t, s.Verify(X, []byte("other"), sig), s.Kind())
+		_, Y := s.NewKeyPair(random.New())
+		require.Error(t, s.Verify(Y, msg, sig), s.Kind())
+		require.Error(t, s.Verify(s.KeyGroup().Point().Null(), msg, sig), s.Kind())
+
+		// a signature is only valid in its own scheme
+		for _, other := range schemes {
+			if other != s && other.SignatureGroup().String() == s.SignatureGroup().String() {
+				require.Error(t, other.Verify(X, msg, sig), s.Kind()+" in "+other.Kind())
+			}
+		}
+	}
+}
+
+// twistPoint returns a point of the twist of bn256 outside G2, found as the
+// first point with a small real abscissa x on y^2 = x^3 + b.
+func twistPoint(t *testing.T, suite *bn256.Suite) kyber.Point {
+	p, _ := new(big.Int).SetString("65000549695646603732796438742359905742825358107623003571877145026864184071783", 10)
+	mod := func(v *big.Int) *big.Int { return v.Mod(v, p) }
+	// b = b1*i + b0
+	b0, _ := new(big.Int).SetString("64984e1f1aa5abfb90e7f281111033b15a0cdfc596e598bb7774124bdb6c6949", 16)
+	b1, _ := new(big.Int).SetString("0e5ee696baa9f3ff5dd7fe127026e2d0316f8dae83455ef635a2de0ad6340f0a", 16)
+	half := new(big.Int).ModInverse(big.NewInt(2), p)
+	for x := int64(1); ; x++ {
+		// the square root of a0 + a1*i, with a0 = x^3 + b0 and a1 = b1
+		a0 := mod(new(big.Int).Add(big.NewInt(x*x*x), b0))
+		norm := mod(new(big.Int).Add(new(big.Int).Mul(a0, a0), new(big.Int).Mul(b1, b1)))
+		n := new(big.Int).ModSqrt(norm, p)
+		if n == nil {
+			continue
+		}
+		y0 := new(big.Int).ModSqrt(mod(new(big.Int).Mul(new(big.Int).Add(a0, n), half)), p)
+		if y0 == nil {
+			y0 = new(big.Int).ModSqrt(mod(new(big.Int).Mul(new(big.Int).Sub(a0, n), half)), p)
+		}
+		if y0 == nil || y0.Sign() == 0 {
+			continue
+		}
+		y1 := mod(new(big.Int).Mul(b1, new(big.Int).ModInverse(new(big.Int).Lsh(y0, 1), p)))
+
+		// encoded as the imaginary and real parts of x, then of y
+		buf := make([]byte, 128)
+		for i, v := range []*big.Int{big.NewInt(x), y1, y0} {
+			b := v.Bytes()
+			copy(buf[32*(i+2)-len(b):], b)
+		}
+		P := suite.G2().Point()
+		require.NoError(t, P.UnmarshalBinary(buf))
+		return P
+	}
+}
+
+func TestSchemeKeyOutsideSubgroup(t *testing.T) {
+	suite := bn256.NewSuite()
+	msg := []byte("Hello Boneh-Lynn-Shacham")
+	X := twistPoint(t, suite)
+	order := suite.G2().Point().Mul(suite.G2().Scalar().SetInt64(-1), X)
+	require.False(t, order.Add(order, X).Equal(suite.G2().Point().Null()))
+
+	// G2 keys of the schemes with signatures on G1
+	s := NewPopScheme(suite)
+	x, Y := s.NewKeyPair(random.New())
+	sig, err := s.Sign(x, msg)
+	require.NoError(t, err)
+	require.EqualError(t, s.Verify(X, msg, sig), "bls: public key not in the prime-order subgroup")
+	require.Error(t, s.AggregateVerify([]kyber.Point{Y, X}, [][]byte{msg, []byte("other")}, sig))
+	require.Error(t, s.PopVerify(X, sig))
+	require.Error(t, s.FastAggregateVerify([]kyber.Point{Y, X}, msg, sig))
+	err = s.VerifyBatch([]kyber.Point{Y, X}, [][]byte{msg, msg}, [][]byte{sig, sig})
+	require.Equal(t, &BatchError{Invalid: []int{1}}, err)
+}
+
+func TestSchemeAggregateVerify(t *testing.T) {
+	suite := bn256.NewSuite()
+	msgs := [][]byte{[]byte("first"), []byte("second"), []byte("first")}
+	for _, s := range allSchemes(suite) {
+		publics := make([]kyber.Point, len(msgs))
+		sigs := make([][]byte, len(msgs))
+		for i, msg := range msgs {
+			var x kyber.Scalar
+			x, publics[i] = s.NewKeyPair(random.New())
+			var err error
+			sigs[i], err = s.Sign(x, msg)
+			require.NoError(t, err)
+		}
+		sig, err := s.AggregateSignatures(sigs...)
+		require.NoError(t, err)
+
+		err = s.AggregateVerify(publics, msgs, sig)
+		if s.Kind() == Basic {
+			require.Error(t, err, "repeated messages")
+		} else {
+			require.NoError(t, err, s.Kind())
+		}
+		sig, err = s.AggregateSignatures(sigs[:2]...)
+		require.NoError(t, err)
+		require.NoError(t, s.AggregateVerify(publics[:2], msgs[:2], sig), s.Kind())
+		require.Error(t, s.AggregateVerify(publics[:2], [][]byte{msgs[1], msgs[0]}, sig), s.Kind())
+	}
+}
+
+func TestSchemePop(t *testing.T) {
+	suite := bn256.NewSuite()
+	testSchemePop(t, suite, NewPopScheme(suite))
+	testSchemePop(t, suite, NewPopSchemeOnG2(suite))
+}
+
+func testSchemePop(t *testing.T, suite *bn256.Suite, s *Scheme) {
+	msg := []byte("Hello Boneh-Lynn-Shacham")
+
+	n := 3
+	publics := make([]kyber.Point, n)
+	sigs := make([][]byte, n)
+	for i := range publics {
+		var x kyber.Scalar
+		x, publics[i] = s.NewKeyPair(random.New())
+		proof, err := s.PopProve(x)
+		require.NoError(t, err)
+		require.NoError(t, s.PopVerify(publics[i], proof))
+		if i > 0 {
+			require.Error(t, s.PopVerify(publics[i-1], proof))
+		}
+		sigs[i], err = s.Sign(x, msg)
+		require.NoError(t, err)
+	}
+	sig, err := s.AggregateSignatures(sigs...)
+	require.NoError(t, err)
+	require.NoError(t, s.FastAggregateVerify(publics, msg, sig))
+	require.Error(t, s.FastAggregateVerify(publics[:2], msg, sig))
+	require.Error(t, s.FastAggregateVerify(publics, []byte("other"), sig))
+
+	// a signature is not a proof of possession
+	x, X := s.NewKeyPair(random.New())
+	buf, err := X.MarshalBinary()
+	require.NoError(t, err)
+	sig, err = s.Sign(x, buf)
+	require.NoError(t, err)
+	require.Error(t, s.PopVerify(X, sig))
+
+	// a rogue key, which cancels the other keys, has no valid proof
+	rogue := s.KeyGroup().Point().Mul(x, nil)
+	for _, X := range publics {
+		rogue.Sub(rogue, X)
+	}
+	forged, err := s.Sign(x, msg)
+	require.NoError(t, err)
+	require.NoError(t, s.FastAggregateVerify(append(publics, rogue), msg, forged),
+		"FastAggregateVerify relies on proofs of possession")
+	proof, err := s.PopProve(x)
+	require.NoError(t, err)
+	require.Error(t, s.PopVerify(rogue, proof))
+
+	_, err = NewBasicScheme(suite).PopProve(x)
+	require.Error(t, err)
+	require.Error(t, NewAugScheme(suite).FastAggregateVerify(publics, msg, sig))
+}
+
+func TestSchemeOnG2(t *testing.T) {
+	suite := bn256.NewSuite()
+	msg := []byte("Hello Boneh-Lynn-Shacham")
+	s := NewSchemeOnG2(suite)
+	x, X := s.NewKeyPair(random.New())
+	require.Equal(t, suite.G1().PointLen(), X.MarshalSize())
+	sig, err := s.Sign(x, msg)
+	require.NoError(t, err)
+	require.Len(t, sig, suite.G2().PointLen())
+	require.NoError(t, s.Verify(X, msg, sig))
+	require.Error(t, s.Verify(X, []byte("other"), sig))
+	// the bare G1 scheme cannot read the signature
+	require.Error(t, Verify(suite, X, msg, sig))
+
+	y, Y := s.NewKeyPair(random.New())
+	sig2, err := s.Sign(y, []byte("other"))
+	require.NoError(t, err)
+	agg, err := s.AggregateSignatures(sig, sig2)
+	require.NoError(t, err)
+	require.NoError(t, s.AggregateVerify([]kyber.Point{X, Y}, [][]byte{msg, []byte("other")}, agg))
+
+	sig2, err = s.Sign(y, msg)
+	require.NoError(t, err)
+	agg, err = s.AggregateSignatures(sig, sig2)
+	require.NoError(t, err)
+	require.NoError(t, s.Verify(s.AggregatePublicKeys(X, Y), msg, agg))
+}
@@ -2,9 +2,10 @@
 Package cosi implements the collective signing (CoSi) algorithm as presented in
 the paper "Keeping Authorities 'Honest or Bust' with Decentralized Witness
 Cosigning" by Ewa Syta et al. See https://arxiv.org/abs/1503.08768. This
-package only provides the functionality for the cryptographic operations of
-CoSi. All network-related operations have to be handled elsewhere. Below we
-describe a high-level overview of the CoSi protocol (using a star communication
+package provides the functionality for the cryptographic operations of CoSi,
+and a Node running the protocol over a spanning tree of the participants,
+with exclusion of offline subtrees and per-phase timeouts, on top of a
+pluggable Transport. Below we describe a high-level overview of the CoSi protocol (using a star communication
 topology). We refer to the research paper for further details on communication
 over trees, exception mechanisms and signature verification policies.
 
@@ -36,6 +37,11 @@ response r_i = v_i + c*a_i and sends it back to the leader.
 P' or a timer has run out. If he has not enough replies he aborts. Finally,
 the leader computes the aggregate response r = \sum{j ∈ P'}(r_j) and publishes
 (V,r,Z) as the signature for the message M.
+
+The aggregate public key A above lets a participant who chooses its key after
+seeing the others sign alone for all of them. In the strict mode, each public
+key is weighted by a coefficient bound to the list of participants, as in
+MuSig: see NewStrictMask, StrictResponse and VerifyStrict.
 */
 package cosi
 
@@ -44,6 +50,7 @@ import (
 	"fmt"
 
 	"go.dedis.ch/kyber/v3"
+	"go.dedis.ch/kyber/v3/sign"
 )
 
 // Commit returns a random scalar v, generated from the given suite,
@@ -156,6 +163,10 @@ func Sign(suite Suite, commitment kyber.Point, response kyber.Scalar, mask *Mask
 // Verify checks the given cosignature on the provided message using the list
 // of public keys and cosigning policy.
 func Verify(suite Suite, publics []kyber.Point, message, sig []byte, policy Policy) error {
+	return verify(suite, publics, message, sig, policy, false)
+}
+
+func verify(suite Suite, publics []kyber.Point, message, sig []byte, policy Policy, strict bool) error {
 	if publics == nil {
 		return errors.New("no public keys provided")
 	}
@@ -188,7 +199,11 @@ func Verify(suite Suite, publics []kyber.Point, message, sig []byte, policy Poli
 	r := suite.Scalar().SetBytes(rBuff)
 
 	// Unpack the participation mask and get the aggregate public key
-	mask, err := NewMask(suite, publics, nil)
+	newMask := NewMask
+	if strict {
+		newMask = NewStrictMask
+	}
+	mask, err := newMask(suite, publics, nil)
 	if err != nil {
 		return err
 	}
@@ -226,18 +241,14 @@ func Verify(suite Suite, publics []kyber.Point, message, sig []byte, policy Poli
 }
 
 // ParticipationMask is an interface to get the total number of candidates
-// and the number of participants.
-type ParticipationMask interface {
-	// CountEnabled returns the number of participants
-	CountEnabled() int
-	// CountTotal returns the number of candidates
-	CountTotal() int
-}
+// and the number of participants. It is the same as sign.ParticipationMask.
+type ParticipationMask = sign.ParticipationMask
 
 // Mask represents a cosigning participation bitmask.
 type Mask struct {
 	mask            []byte
 	publics         []kyber.Point
+	weighted        []kyber.Point // a_i*X_i in strict masks
 	AggregatePublic kyber.Point
 }
 
@@ -246,8 +257,13 @@ type Mask struct {
 // it is present in the list of keys and sets the corresponding index in the
 // bitmask to 1 (enabled).
 func NewMask(suite Suite, publics []kyber.Point, myKey kyber.Point) (*Mask, error) {
+	return newMask(suite, publics, nil, myKey)
+}
+
+func newMask(suite Suite, publics, weighted []kyber.Point, myKey kyber.Point) (*Mask, error) {
 	m := &Mask{
-		publics: publics,
+		publics:  publics,
+		weighted: weighted,
 	}
 	m.mask = make([]byte, m.Len())
 	m.AggregatePublic = suite.Point().Null()
@@ -274,6 +290,14 @@ func (m *Mask) Mask() []byte {
 	return clone
 }
 
+// public returns the contribution of cosigner i to the aggregate public key.
+func (m *Mask) public(i int) kyber.Point {
+	if m.weighted != nil {
+		return m.weighted[i]
+	}
+	return m.publics[i]
+}
+
 // Len returns the mask length in bytes.
 func (m *Mask) Len() int {
 	return (len(m.publics) + 7) >> 3
@@ -291,11 +315,11 @@ func (m *Mask) SetMask(mask []byte) error {
 		msk := byte(1) << uint(i&7)
 		if ((m.mask[byt] & msk) == 0) && ((mask[byt] & msk) != 0) {
 			m.mask[byt] ^= msk // flip bit in mask from 0 to 1
-			m.AggregatePublic.Add(m.AggregatePublic, m.publics[i])
+			m.AggregatePublic.Add(m.AggregatePublic, m.public(i))
 		}
 		if ((m.mask[byt] & msk) != 0) && ((mask[byt] & msk) == 0) {
 			m.mask[byt] ^= msk // flip bit in mask from 1 to 0
-			m.AggregatePublic.Sub(m.AggregatePublic, m.publics[i])
+			m.AggregatePublic.Sub(m.AggregatePublic, m.public(i))
 		}
 	}
 	return nil
@@ -311,11 +335,11 @@ func (m *Mask) SetBit(i int, enable bool) error {
 	msk := byte(1) << uint(i&7)
 	if ((m.mask[byt] & msk) == 0) && enable {
 		m.mask[byt] ^= msk // flip bit in mask from 0 to 1
-		m.AggregatePublic.Add(m.AggregatePublic, m.publics[i])
+		m.AggregatePublic.Add(m.AggregatePublic, m.public(i))
 	}
 	if ((m.mask[byt] & msk) != 0) && !enable {
 		m.mask[byt] ^= msk // flip bit in mask from 1 to 0
-		m.AggregatePublic.Sub(m.AggregatePublic, m.publics[i])
+		m.AggregatePublic.Sub(m.AggregatePublic, m.public(i))
 	}
 	return nil
 }
@@ -381,10 +405,11 @@ func AggregateMasks(a, b []byte) ([]byte, error) {
 // the operation relying on the collective signature is) in determining whether
 // the collective signature was produced by an acceptable set of cosigners.
 //
+// It is the same as sign.Policy, so that Verify accepts the weighted and
+// composite policies of the package kyber/sign.
+//
 // Deprecated: the policies have moved to the package kyber/sign
-type Policy interface {
-	Check(m ParticipationMask) bool
-}
+type Policy = sign.Policy
 
 // CompletePolicy is the default policy requiring that all participants have
 // cosigned to make a collective signature valid.
@@ -9,6 +9,7 @@ import (
 
 	"go.dedis.ch/kyber/v3"
 	"go.dedis.ch/kyber/v3/group/edwards25519"
+	"go.dedis.ch/kyber/v3/sign"
 	"go.dedis.ch/kyber/v3/sign/eddsa"
 	"go.dedis.ch/kyber/v3/util/key"
 	"go.dedis.ch/kyber/v3/xof/blake2xb"
@@ -188,3 +189,31 @@ func TestMask(t *testing.T) {
 		}
 	}
 }
+
+func TestVerifyPolicy(t *testing.T) {
+	message := []byte("Hello World Cosi")
+	n := 6
+	var privates []kyber.Scalar
+	var publics []kyber.Point
+	for i := 0; i < n; i++ {
+		kp := key.NewKeyPair(testSuite)
+		privates = append(privates, kp.Private)
+		publics = append(publics, kp.Public)
+	}
+	sig := cosign(t, publics, privates, []int{0, 1, 4}, message, false)
+
+	weighted, err := sign.NewWeightedPolicy([]uint64{5, 5, 1, 1, 3, 1}, 13)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := Verify(testSuite, publics, message, sig, weighted); err != nil {
+		t.Fatal(err)
+	}
+	orgs := sign.NewAndPolicy(
+		sign.NewGroupPolicy([]int{0, 1, 2}, sign.NewThresholdPolicy(2)),
+		sign.NewGroupPolicy([]int{3, 4, 5}, sign.NewThresholdPolicy(2)),
+	)
+	if err := Verify(testSuite, publics, message, sig, orgs); err == nil {
+		t.Fatal("expected error on unfulfilled policy")
+	}
+}
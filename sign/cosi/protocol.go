@@ -0,0 +1,360 @@
+package cosi
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"go.dedis.ch/kyber/v3"
+	"go.dedis.ch/kyber/v3/util/random"
+)
+
+// sessionLen is the length in bytes of the identifier of a round.
+const sessionLen = 16
+
+// Timeouts holds the time a node waits for the messages of each phase of the
+// protocol. A zero timeout waits until the context is done. The commitment
+// and response timeouts are the time a node waits for children which are
+// leaves: a node waits for them times the height of its subtree, so that the
+// nodes closer to the root wait for the deeper nodes.
+type Timeouts struct {
+	// Announcement is the time a cosigner waits for an announcement.
+	Announcement time.Duration
+	// Commitment is the time a node waits for the commitments of its
+	// children, per level of its subtree.
+	Commitment time.Duration
+	// Challenge is the time a cosigner waits for the challenge, per level
+	// above it, once the leader has collected the commitments.
+	Challenge time.Duration
+	// Response is the time a node waits for the responses of its children,
+	// per level of its subtree.
+	Response time.Duration
+}
+
+// Config holds the parameters of a CoSi protocol, shared by all the nodes.
+type Config struct {
+	Suite Suite
+	// Publics are the public keys of the cosigners.
+	Publics []kyber.Point
+	// Tree is the spanning tree over the cosigners, rooted at the leader.
+	Tree *Tree
+	// Policy is checked by the leader against the cosigners which committed.
+	// It defaults to CompletePolicy.
+	Policy   Policy
+	Timeouts Timeouts
+	// Strict runs the protocol in the rogue-key resistant mode, where the
+	// signatures verify with VerifyStrict instead of Verify.
+	Strict bool
+}
+
+// Node runs the CoSi protocol over a tree for one of the cosigners. Offline
+// nodes, and nodes which do not commit in time, are excluded with their
+// subtree through the participation mask. A node which committed but fails
+// to respond makes the round fail, since the challenge depends on the mask:
+// the leader can then run a new round, where the failing node is excluded if
+// it is still offline. A Node is not safe for concurrent use.
+type Node struct {
+	c         *Config
+	index     int
+	private   kyber.Scalar
+	transport Transport
+
+	coef     kyber.Scalar  // key coefficient in the strict mode
+	weighted []kyber.Point // weighted public keys in the strict mode
+
+	session []byte
+	pending *Message // announcement of a later round
+}
+
+// NewNode returns the node of the cosigner at the given index, holding the
+// private key matching its public key in the configuration.
+func NewNode(c *Config, index int, private kyber.Scalar, transport Transport) (*Node, error) {
+	if c.Tree == nil || c.Tree.Size() != len(c.Publics) {
+		return nil, errors.New("cosi: tree does not match the public keys")
+	}
+	if index < 0 || index >= len(c.Publics) {
+		return nil, errors.New("cosi: index out of range")
+	}
+	if !c.Suite.Point().Mul(private, nil).Equal(c.Publics[index]) {
+		return nil, errors.New("cosi: private key does not match the public key")
+	}
+	n := &Node{
+		c:         c,
+		index:     index,
+		private:   private,
+		transport: transport,
+	}
+	if c.Strict {
+		coefs, err := KeyCoefficients(c.Suite, c.Publics)
+		if err != nil {
+			return nil, err
+		}
+		n.coef = coefs[index]
+		n.weighted = make([]kyber.Point, len(c.Publics))
+		for i, X := range c.Publics {
+			n.weighted[i] = c.Suite.Point().Mul(coefs[i], X)
+		}
+	}
+	return n, nil
+}
+
+// newMask returns an empty mask of the mode of the protocol, with the bit of
+// myKey enabled if it is not nil.
+func (n *Node) newMask(myKey kyber.Point) (*Mask, error) {
+	return newMask(n.c.Suite, n.c.Publics, n.weighted, myKey)
+}
+
+// Sign runs a round of the protocol as the leader and returns the collective
+// signature of msg, which verifies with Verify, or VerifyStrict in the strict
+// mode, and the policy of the configuration.
+func (n *Node) Sign(ctx context.Context, msg []byte) ([]byte, error) {
+	tree := n.c.Tree
+	if n.index != tree.Root() {
+		return nil, errors.New("cosi: only the leader can start a round")
+	}
+	if msg == nil {
+		return nil, errors.New("no message provided")
+	}
+	n.session = make([]byte, sessionLen)
+	random.Bytes(n.session, n.c.Suite.RandomStream())
+	n.broadcast(tree.Children(n.index), &Message{Phase: PhaseAnnouncement, Msg: msg})
+
+	v, V, mask, included, err := n.commit(ctx)
+	if err != nil {
+		return nil, err
+	}
+	policy := n.c.Policy
+	if policy == nil {
+		policy = CompletePolicy{}
+	}
+	if !policy.Check(mask) {
+		return nil, fmt.Errorf("cosi: policy not fulfilled by the %d committed cosigners", mask.CountEnabled())
+	}
+
+	n.broadcast(included, &Message{Phase: PhaseChallenge, V: V, Mask: mask.Mask()})
+	r, err := n.respond(ctx, v, V, mask, msg, included)
+	if err != nil {
+		return nil, err
+	}
+	sig, err := Sign(n.c.Suite, V, r, mask)
+	if err != nil {
+		return nil, err
+	}
+	if err := verify(n.c.Suite, n.c.Publics, msg, sig, policy, n.c.Strict); err != nil {
+		return nil, err
+	}
+	return sig, nil
+}
+
+// Run runs a round of the protocol as a cosigner: it waits for the
+// announcement of the leader, then cosigns the announced message with its
+// subtree. It returns the signed message.
+func (n *Node) Run(ctx context.Context) ([]byte, error) {
+	tree := n.c.Tree
+	parent := tree.Parent(n.index)
+	if parent == -1 {
+		return nil, errors.New("cosi: the leader starts rounds with Sign")
+	}
+	ann, err := n.announcement(ctx, parent)
+	if err != nil {
+		return nil, err
+	}
+	msg := ann.Msg
+	n.session = ann.Session
+	n.broadcast(tree.Children(n.index), &Message{Phase: PhaseAnnouncement, Msg: msg})
+
+	v, V, mask, included, err := n.commit(ctx)
+	if err != nil {
+		return nil, err
+	}
+	err = n.transport.Send(parent, n.message(&Message{Phase: PhaseCommitment, V: V, Mask: mask.Mask()}))
+	if err != nil {
+		return nil, err
+	}
+
+	t := n.c.Timeouts
+	wait := t.Commitment*time.Duration(tree.height(tree.Root())) + t.Challenge*time.Duration(tree.depth(n.index))
+	if t.Commitment == 0 || t.Challenge == 0 {
+		wait = 0
+	}
+	msgs, err := n.receive(ctx, PhaseChallenge, []int{parent}, wait)
+	if err != nil {
+		return nil, err
+	}
+	ch, ok := msgs[parent]
+	if !ok {
+		return nil, errors.New("cosi: no challenge received")
+	}
+	mask, err = n.newMask(nil)
+	if err != nil {
+		return nil, err
+	}
+	if err := mask.SetMask(ch.Mask); err != nil {
+		return nil, err
+	}
+	if enabled, _ := mask.IndexEnabled(n.index); !enabled {
+		return nil, errors.New("cosi: excluded from the challenge")
+	}
+	n.broadcast(included, &Message{Phase: PhaseChallenge, V: ch.V, Mask: ch.Mask})
+
+	r, err := n.respond(ctx, v, ch.V, mask, msg, included)
+	if err != nil {
+		return nil, err
+	}
+	err = n.transport.Send(parent, n.message(&Message{Phase: PhaseResponse, R: r}))
+	if err != nil {
+		return nil, err
+	}
+	return msg, nil
+}
+
+// commit returns the secret of the node, and the aggregate commitment and
+// mask of its subtree with the children which committed in time.
+func (n *Node) commit(ctx context.Context) (kyber.Scalar, kyber.Point, *Mask, []int, error) {
+	suite, tree := n.c.Suite, n.c.Tree
+	v, V := Commit(suite)
+	mask, err := n.newMask(n.c.Publics[n.index])
+	if err != nil {
+		return nil, nil, nil, nil, err
+	}
+	wait := n.c.Timeouts.Commitment * time.Duration(tree.height(n.index))
+	msgs, err := n.receive(ctx, PhaseCommitment, tree.Children(n.index), wait)
+	if err != nil {
+		return nil, nil, nil, nil, err
+	}
+	var included []int
+	for _, child := range tree.Children(n.index) {
+		m, ok := msgs[child]
+		if !ok || !n.validCommitment(m) {
+			continue
+		}
+		V = suite.Point().Add(V, m.V)
+		agg, _ := AggregateMasks(mask.Mask(), m.Mask)
+		if err := mask.SetMask(agg); err != nil {
+			return nil, nil, nil, nil, err
+		}
+		included = append(included, child)
+	}
+	return v, V, mask, included, nil
+}
+
+// validCommitment returns whether the commitment of a child only covers
+// nodes of its subtree, and the child itself.
+func (n *Node) validCommitment(m *Message) bool {
+	if m.V == nil || len(m.Mask) != (len(n.c.Publics)+7)>>3 {
+		return false
+	}
+	for i := 0; i < 8*len(m.Mask); i++ {
+		if m.Mask[i>>3]&(byte(1)<<uint(i&7)) == 0 {
+			continue
+		}
+		if i >= len(n.c.Publics) || !n.c.Tree.inSubtree(i, m.From) {
+			return false
+		}
+	}
+	return m.Mask[m.From>>3]&(byte(1)<<uint(m.From&7)) != 0
+}
+
+// respond returns the aggregate response of the node and its included
+// children to the challenge.
+func (n *Node) respond(ctx context.Context, v kyber.Scalar, V kyber.Point, mask *Mask, msg []byte, included []int) (kyber.Scalar, error) {
+	suite := n.c.Suite
+	c, err := Challenge(suite, V, mask.AggregatePublic, msg)
+	if err != nil {
+		return nil, err
+	}
+	var r kyber.Scalar
+	if n.c.Strict {
+		r, err = StrictResponse(suite, n.private, v, c, n.coef)
+	} else {
+		r, err = Response(suite, n.private, v, c)
+	}
+	if err != nil {
+		return nil, err
+	}
+	wait := n.c.Timeouts.Response * time.Duration(n.c.Tree.height(n.index))
+	msgs, err := n.receive(ctx, PhaseResponse, included, wait)
+	if err != nil {
+		return nil, err
+	}
+	for _, child := range included {
+		m, ok := msgs[child]
+		if !ok || m.R == nil {
+			return nil, fmt.Errorf("cosi: no response from node %d", child)
+		}
+		r = suite.Scalar().Add(r, m.R)
+	}
+	return r, nil
+}
+
+// announcement waits for the announcement of a new round from the parent.
+func (n *Node) announcement(ctx context.Context, parent int) (*Message, error) {
+	if m := n.pending; m != nil {
+		n.pending = nil
+		return m, nil
+	}
+	n.session = nil
+	msgs, err := n.receive(ctx, PhaseAnnouncement, []int{parent}, n.c.Timeouts.Announcement)
+	if err != nil {
+		return nil, err
+	}
+	m, ok := msgs[parent]
+	if !ok {
+		return nil, errors.New("cosi: no announcement received")
+	}
+	return m, nil
+}
+
+// receive returns the messages of the phase of the current round received
+// from the given nodes, until all of them have sent one, the timeout
+// expires or the context is done. Without a current round, it accepts the
+// messages of any round.
+func (n *Node) receive(ctx context.Context, phase Phase, from []int, timeout time.Duration) (map[int]*Message, error) {
+	expected := make(map[int]bool, len(from))
+	for _, i := range from {
+		expected[i] = true
+	}
+	var expired <-chan time.Time
+	if timeout > 0 {
+		timer := time.NewTimer(timeout)
+		defer timer.Stop()
+		expired = timer.C
+	}
+	msgs := make(map[int]*Message, len(from))
+	for len(msgs) < len(from) {
+		select {
+		case m := <-n.transport.Receive():
+			_, dup := msgs[m.From]
+			current := n.session == nil || bytes.Equal(m.Session, n.session)
+			switch {
+			case m.Phase == phase && expected[m.From] && current && !dup:
+				msgs[m.From] = m
+			case m.Phase == PhaseAnnouncement && m.From == n.c.Tree.Parent(n.index) && !current:
+				n.pending = m
+			}
+		case <-expired:
+			return msgs, nil
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+	}
+	return msgs, nil
+}
+
+// message fills the sender and round of a message.
+func (n *Node) message(m *Message) *Message {
+	m.From = n.index
+	m.Session = n.session
+	return m
+}
+
+// broadcast sends a message to the given nodes. Failures are handled as
+// missing messages by the receivers.
+func (n *Node) broadcast(to []int, m *Message) {
+	m = n.message(m)
+	for _, i := range to {
+		_ = n.transport.Send(i, m)
+	}
+}
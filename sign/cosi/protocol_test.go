@@ -0,0 +1,177 @@
+package cosi
+
+import (
+	"context"
+	"crypto/cipher"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"go.dedis.ch/kyber/v3"
+	"go.dedis.ch/kyber/v3/util/key"
+	"go.dedis.ch/kyber/v3/util/random"
+)
+
+// concurrentSuite draws fresh randomness for every node, as the nodes run
+// concurrently.
+type concurrentSuite struct {
+	*cosiSuite
+}
+
+func (concurrentSuite) RandomStream() cipher.Stream { return random.New() }
+
+var protocolSuite = concurrentSuite{testSuite}
+
+var testTimeouts = Timeouts{
+	Commitment: 100 * time.Millisecond,
+	Challenge:  100 * time.Millisecond,
+	Response:   100 * time.Millisecond,
+}
+
+// setupNodes returns the nodes of the cosigners of the tree, connected
+// through the transports, with the policy and mode of the configuration.
+func setupNodes(t *testing.T, tree *Tree, c *Config, transport func(i int) Transport) ([]*Node, []kyber.Point) {
+	n := tree.Size()
+	var kps []*key.Pair
+	var publics []kyber.Point
+	for i := 0; i < n; i++ {
+		kp := key.NewKeyPair(protocolSuite)
+		kps = append(kps, kp)
+		publics = append(publics, kp.Public)
+	}
+	c.Suite = protocolSuite
+	c.Publics = publics
+	c.Tree = tree
+	c.Timeouts = testTimeouts
+	nodes := make([]*Node, n)
+	for i := range nodes {
+		var err error
+		nodes[i], err = NewNode(c, i, kps[i].Private, transport(i))
+		require.NoError(t, err)
+	}
+	return nodes, publics
+}
+
+// runCosigners runs the non-leader nodes until the context is done.
+func runCosigners(ctx context.Context, nodes []*Node) {
+	for _, node := range nodes {
+		if node.index == node.c.Tree.Root() {
+			continue
+		}
+		go func(node *Node) {
+			for ctx.Err() == nil {
+				node.Run(ctx)
+			}
+		}(node)
+	}
+}
+
+func TestProtocol(t *testing.T) {
+	msg := []byte("Hello World Cosi")
+	for _, branching := range []int{1, 2, 3, 12} {
+		tree, err := NewNaryTree(13, branching)
+		require.NoError(t, err)
+		network := NewMemoryNetwork(tree.Size())
+		nodes, publics := setupNodes(t, tree, &Config{}, network.Transport)
+
+		ctx, cancel := context.WithCancel(context.Background())
+		runCosigners(ctx, nodes)
+		sig, err := nodes[0].Sign(ctx, msg)
+		require.NoError(t, err)
+		require.NoError(t, Verify(protocolSuite, publics, msg, sig, nil))
+
+		// the nodes are ready for the next round
+		sig, err = nodes[0].Sign(ctx, []byte("second round"))
+		require.NoError(t, err)
+		require.NoError(t, Verify(protocolSuite, publics, []byte("second round"), sig, nil))
+		cancel()
+	}
+}
+
+func TestProtocolOfflineSubtree(t *testing.T) {
+	msg := []byte("Hello World Cosi")
+	// node 1 has children 4, 5 and 6, which are excluded with it
+	tree, err := NewNaryTree(13, 3)
+	require.NoError(t, err)
+	network := NewMemoryNetwork(tree.Size())
+	nodes, publics := setupNodes(t, tree, &Config{Policy: NewThresholdPolicy(8)}, network.Transport)
+	network.SetOnline(1, false)
+	network.SetOnline(11, false)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	runCosigners(ctx, nodes)
+	sig, err := nodes[0].Sign(ctx, msg)
+	require.NoError(t, err)
+	require.NoError(t, Verify(protocolSuite, publics, msg, sig, NewThresholdPolicy(8)))
+	require.Error(t, Verify(protocolSuite, publics, msg, sig, nil))
+
+	mask, err := NewMask(testSuite, publics, nil)
+	require.NoError(t, err)
+	require.NoError(t, mask.SetMask(sig[testSuite.PointLen()+testSuite.ScalarLen():]))
+	require.Equal(t, 8, mask.CountEnabled())
+	for _, i := range []int{1, 4, 5, 6, 11} {
+		enabled, err := mask.IndexEnabled(i)
+		require.NoError(t, err)
+		require.False(t, enabled)
+	}
+
+	// the policy is checked before the challenge
+	network.SetOnline(2, false)
+	_, err = nodes[0].Sign(ctx, msg)
+	require.Error(t, err)
+}
+
+// dropTransport drops the messages of a phase.
+type dropTransport struct {
+	Transport
+	phase Phase
+}
+
+func (d *dropTransport) Send(to int, m *Message) error {
+	if m.Phase == d.phase {
+		return nil
+	}
+	return d.Transport.Send(to, m)
+}
+
+func TestProtocolMissingResponse(t *testing.T) {
+	tree, err := NewNaryTree(7, 2)
+	require.NoError(t, err)
+	network := NewMemoryNetwork(tree.Size())
+	nodes, _ := setupNodes(t, tree, &Config{Policy: NewThresholdPolicy(4)}, func(i int) Transport {
+		if i == 5 {
+			return &dropTransport{network.Transport(i), PhaseResponse}
+		}
+		return network.Transport(i)
+	})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	runCosigners(ctx, nodes)
+	_, err = nodes[0].Sign(ctx, []byte("Hello World Cosi"))
+	require.Error(t, err)
+
+	_, err = nodes[1].Sign(ctx, []byte("Hello World Cosi"))
+	require.Error(t, err)
+}
+
+func TestTree(t *testing.T) {
+	tree, err := NewTree([]int{2, 2, -1, 1})
+	require.NoError(t, err)
+	require.Equal(t, 2, tree.Root())
+	require.Equal(t, []int{0, 1}, tree.Children(2))
+	require.Equal(t, 2, tree.height(2))
+	require.Equal(t, 2, tree.depth(3))
+	require.True(t, tree.inSubtree(3, 1))
+	require.False(t, tree.inSubtree(3, 0))
+
+	_, err = NewTree([]int{-1, 2, 1})
+	require.Error(t, err)
+	_, err = NewTree([]int{-1, -1})
+	require.Error(t, err)
+	_, err = NewTree([]int{1, 0})
+	require.Error(t, err)
+	_, err = NewTree([]int{-1, 3})
+	require.Error(t, err)
+}
@@ -0,0 +1,85 @@
+package cosi
+
+import (
+	"errors"
+
+	"go.dedis.ch/kyber/v3"
+)
+
+// keyAggTag separates the hashes of the key coefficients from the
+// challenges.
+const keyAggTag = "CoSi/KeyAgg"
+
+// In the naive mode, the aggregate public key is the sum of the public keys of
+// the cosigners, so that a cosigner choosing its key X_r = [x]G - sum(X_i)
+// after seeing the others can sign alone for the whole roster. In the strict
+// mode, as in MuSig, the public key of cosigner i is weighted by a coefficient
+// a_i = H(L || X_i) bound to the roster L, the aggregate public key is
+// A = sum(a_i*X_i) and the responses are r_i = v_i + c*a_i*x_i. A cosigner can
+// no longer cancel the keys of the others, and signatures produced in one
+// mode do not verify in the other.
+
+// KeyCoefficients returns the coefficients a_i = H(L || X_i) of the public
+// keys of the roster in the strict mode, where L is the hash of all the
+// public keys.
+func KeyCoefficients(suite Suite, publics []kyber.Point) ([]kyber.Scalar, error) {
+	if len(publics) == 0 {
+		return nil, errors.New("no public keys provided")
+	}
+	hash := suite.Hash()
+	hash.Write([]byte(keyAggTag))
+	for _, X := range publics {
+		if _, err := X.MarshalTo(hash); err != nil {
+			return nil, err
+		}
+	}
+	L := hash.Sum(nil)
+
+	coefs := make([]kyber.Scalar, len(publics))
+	for i, X := range publics {
+		hash := suite.Hash()
+		hash.Write([]byte(keyAggTag))
+		hash.Write(L)
+		if _, err := X.MarshalTo(hash); err != nil {
+			return nil, err
+		}
+		coefs[i] = suite.Scalar().SetBytes(hash.Sum(nil))
+	}
+	return coefs, nil
+}
+
+// NewStrictMask returns a new participation bitmask, as NewMask does, whose
+// aggregate public key is the sum of the public keys of the enabled
+// cosigners weighted by their coefficients.
+func NewStrictMask(suite Suite, publics []kyber.Point, myKey kyber.Point) (*Mask, error) {
+	coefs, err := KeyCoefficients(suite, publics)
+	if err != nil {
+		return nil, err
+	}
+	weighted := make([]kyber.Point, len(publics))
+	for i, X := range publics {
+		weighted[i] = suite.Point().Mul(coefs[i], X)
+	}
+	return newMask(suite, publics, weighted, myKey)
+}
+
+// StrictResponse creates the response in the strict mode from the given
+// random scalar v, (collective) challenge c, private key a and key
+// coefficient of the cosigner, i.e., it returns r = v + c*coef*a. The
+// challenge is computed over the aggregate public key of a strict mask.
+func StrictResponse(suite Suite, private, random, challenge, coefficient kyber.Scalar) (kyber.Scalar, error) {
+	if coefficient == nil {
+		return nil, errors.New("no key coefficient provided")
+	}
+	if private == nil {
+		return nil, errors.New("no private key provided")
+	}
+	return Response(suite, suite.Scalar().Mul(coefficient, private), random, challenge)
+}
+
+// VerifyStrict checks the given cosignature, produced in the strict mode, on
+// the provided message using the list of public keys and cosigning policy.
+// It rejects the signatures produced in the naive mode.
+func VerifyStrict(suite Suite, publics []kyber.Point, message, sig []byte, policy Policy) error {
+	return verify(suite, publics, message, sig, policy, true)
+}
@@ -0,0 +1,135 @@
+package cosi
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"go.dedis.ch/kyber/v3"
+	"go.dedis.ch/kyber/v3/sign/eddsa"
+	"go.dedis.ch/kyber/v3/util/key"
+)
+
+// cosign returns the signature of msg by the cosigners holding the private
+// keys, in the strict or naive mode.
+func cosign(t *testing.T, publics []kyber.Point, privates []kyber.Scalar, signers []int, msg []byte, strict bool) []byte {
+	newMask := NewMask
+	if strict {
+		newMask = NewStrictMask
+	}
+	coefs, err := KeyCoefficients(testSuite, publics)
+	require.NoError(t, err)
+
+	var v []kyber.Scalar
+	var V []kyber.Point
+	var masks [][]byte
+	for _, i := range signers {
+		m, err := newMask(testSuite, publics, publics[i])
+		require.NoError(t, err)
+		masks = append(masks, m.Mask())
+		x, X := Commit(testSuite)
+		v = append(v, x)
+		V = append(V, X)
+	}
+	aggV, aggMask, err := AggregateCommitments(testSuite, V, masks)
+	require.NoError(t, err)
+	mask, err := newMask(testSuite, publics, nil)
+	require.NoError(t, err)
+	require.NoError(t, mask.SetMask(aggMask))
+
+	c, err := Challenge(testSuite, aggV, mask.AggregatePublic, msg)
+	require.NoError(t, err)
+	var r []kyber.Scalar
+	for j, i := range signers {
+		var ri kyber.Scalar
+		if strict {
+			ri, err = StrictResponse(testSuite, privates[i], v[j], c, coefs[i])
+		} else {
+			ri, err = Response(testSuite, privates[i], v[j], c)
+		}
+		require.NoError(t, err)
+		r = append(r, ri)
+	}
+	aggr, err := AggregateResponses(testSuite, r)
+	require.NoError(t, err)
+	sig, err := Sign(testSuite, aggV, aggr, mask)
+	require.NoError(t, err)
+	return sig
+}
+
+func TestStrict(t *testing.T) {
+	msg := []byte("Hello World Cosi")
+	n := 5
+	var privates []kyber.Scalar
+	var publics []kyber.Point
+	for i := 0; i < n; i++ {
+		kp := key.NewKeyPair(testSuite)
+		privates = append(privates, kp.Private)
+		publics = append(publics, kp.Public)
+	}
+
+	sig := cosign(t, publics, privates, []int{0, 2, 3}, msg, true)
+	require.NoError(t, VerifyStrict(testSuite, publics, msg, sig, NewThresholdPolicy(3)))
+	require.Error(t, VerifyStrict(testSuite, publics, msg, sig, nil))
+	require.Error(t, VerifyStrict(testSuite, publics, []byte("other"), sig, NewThresholdPolicy(3)))
+	require.Error(t, Verify(testSuite, publics, msg, sig, NewThresholdPolicy(3)))
+
+	// the signature is still an EdDSA signature under the aggregate key
+	mask, err := NewStrictMask(testSuite, publics, nil)
+	require.NoError(t, err)
+	lenSig := testSuite.PointLen() + testSuite.ScalarLen()
+	require.NoError(t, mask.SetMask(sig[lenSig:]))
+	require.NoError(t, eddsa.Verify(mask.AggregatePublic, msg, sig[:lenSig]))
+
+	// naive signatures are rejected in the strict mode
+	sig = cosign(t, publics, privates, []int{0, 1, 2, 3, 4}, msg, false)
+	require.NoError(t, Verify(testSuite, publics, msg, sig, nil))
+	require.Error(t, VerifyStrict(testSuite, publics, msg, sig, nil))
+}
+
+func TestStrictRogueKey(t *testing.T) {
+	msg := []byte("Hello World Cosi")
+	var publics []kyber.Point
+	for i := 0; i < 3; i++ {
+		publics = append(publics, key.NewKeyPair(testSuite).Public)
+	}
+
+	// the rogue key cancels the honest keys in the naive aggregate key
+	x := testSuite.Scalar().Pick(testSuite.RandomStream())
+	rogue := testSuite.Point().Mul(x, nil)
+	for _, X := range publics {
+		rogue.Sub(rogue, X)
+	}
+	publics = append(publics, rogue)
+
+	mask, err := NewMask(testSuite, publics, nil)
+	require.NoError(t, err)
+	require.NoError(t, mask.SetMask([]byte{0xf}))
+	v, V := Commit(testSuite)
+	c, err := Challenge(testSuite, V, mask.AggregatePublic, msg)
+	require.NoError(t, err)
+	r, err := Response(testSuite, x, v, c)
+	require.NoError(t, err)
+	forged, err := Sign(testSuite, V, r, mask)
+	require.NoError(t, err)
+
+	require.NoError(t, Verify(testSuite, publics, msg, forged, nil))
+	require.Error(t, VerifyStrict(testSuite, publics, msg, forged, nil))
+}
+
+func TestProtocolStrict(t *testing.T) {
+	msg := []byte("Hello World Cosi")
+	tree, err := NewNaryTree(7, 2)
+	require.NoError(t, err)
+	network := NewMemoryNetwork(tree.Size())
+	nodes, publics := setupNodes(t, tree, &Config{Policy: NewThresholdPolicy(6), Strict: true}, network.Transport)
+	network.SetOnline(6, false)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	runCosigners(ctx, nodes)
+	sig, err := nodes[0].Sign(ctx, msg)
+	require.NoError(t, err)
+	require.NoError(t, VerifyStrict(protocolSuite, publics, msg, sig, NewThresholdPolicy(6)))
+	require.Error(t, Verify(protocolSuite, publics, msg, sig, NewThresholdPolicy(6)))
+}
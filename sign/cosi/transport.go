@@ -0,0 +1,121 @@
+package cosi
+
+import (
+	"errors"
+	"sync"
+
+	"go.dedis.ch/kyber/v3"
+)
+
+// Phase is a phase of the CoSi protocol.
+type Phase int
+
+const (
+	// PhaseAnnouncement is the phase where the leader announces the message
+	// to sign down the tree.
+	PhaseAnnouncement Phase = iota
+	// PhaseCommitment is the phase where the commitments are aggregated up
+	// the tree.
+	PhaseCommitment
+	// PhaseChallenge is the phase where the leader sends the aggregate
+	// commitment and participation mask down the tree.
+	PhaseChallenge
+	// PhaseResponse is the phase where the responses are aggregated up the
+	// tree.
+	PhaseResponse
+)
+
+// Message is a message of the CoSi protocol between a node and its parent or
+// one of its children.
+type Message struct {
+	Phase Phase
+	// From is the index of the sender.
+	From int
+	// Session identifies the round of the protocol.
+	Session []byte
+	// Msg is the message to sign, in the announcement.
+	Msg []byte
+	// V is the aggregate commitment of the subtree of the sender, in the
+	// commitment, or of all the cosigners, in the challenge.
+	V kyber.Point
+	// Mask is the participation mask matching V.
+	Mask []byte
+	// R is the aggregate response of the subtree of the sender, in the
+	// response.
+	R kyber.Scalar
+}
+
+// Transport sends and receives the messages of a node. Messages to an
+// unreachable node may be dropped silently: the protocol handles missing
+// messages with timeouts.
+type Transport interface {
+	// Send sends a message to the node at index to.
+	Send(to int, m *Message) error
+	// Receive returns the channel of the messages sent to the node.
+	Receive() <-chan *Message
+}
+
+// MemoryNetwork connects nodes through in-memory queues, for tests and
+// simulations. Nodes can be taken offline to simulate failures.
+type MemoryNetwork struct {
+	sync.Mutex
+	queues  []chan *Message
+	offline []bool
+}
+
+// memoryQueueSize is the number of messages a node can hold before the
+// messages to it are dropped.
+const memoryQueueSize = 256
+
+// NewMemoryNetwork returns a network of n nodes, all online.
+func NewMemoryNetwork(n int) *MemoryNetwork {
+	m := &MemoryNetwork{
+		queues:  make([]chan *Message, n),
+		offline: make([]bool, n),
+	}
+	for i := range m.queues {
+		m.queues[i] = make(chan *Message, memoryQueueSize)
+	}
+	return m
+}
+
+// SetOnline takes node i online or offline. The messages sent to or from an
+// offline node are dropped.
+func (m *MemoryNetwork) SetOnline(i int, online bool) {
+	m.Lock()
+	defer m.Unlock()
+	m.offline[i] = !online
+}
+
+// Transport returns the transport of node i.
+func (m *MemoryNetwork) Transport(i int) Transport {
+	return &memoryTransport{network: m, index: i}
+}
+
+type memoryTransport struct {
+	network *MemoryNetwork
+	index   int
+}
+
+func (t *memoryTransport) Send(to int, msg *Message) error {
+	m := t.network
+	if to < 0 || to >= len(m.queues) {
+		return errors.New("cosi: unknown node")
+	}
+	m.Lock()
+	drop := m.offline[t.index] || m.offline[to]
+	m.Unlock()
+	if drop {
+		return nil
+	}
+	select {
+	case m.queues[to] <- msg:
+	default:
+		// the queue is full, as if the node was unreachable
+	}
+	return nil
+}
+
+func (t *memoryTransport) Receive() <-chan *Message {
+	return t.network.queues[t.index]
+}
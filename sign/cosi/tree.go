@@ -0,0 +1,114 @@
+package cosi
+
+import "errors"
+
+// Tree is a spanning tree over the cosigners, identified by their index in
+// the list of public keys, along which the messages of the CoSi protocol are
+// sent. The leader of the protocol is the root of the tree.
+type Tree struct {
+	parents  []int
+	children [][]int
+	root     int
+}
+
+// NewTree returns the tree where parents[i] is the index of the parent of
+// node i, and -1 for the root.
+func NewTree(parents []int) (*Tree, error) {
+	t := &Tree{
+		parents:  parents,
+		children: make([][]int, len(parents)),
+		root:     -1,
+	}
+	for i, p := range parents {
+		switch {
+		case p == -1 && t.root != -1:
+			return nil, errors.New("cosi: tree has more than one root")
+		case p == -1:
+			t.root = i
+		case p < 0 || p >= len(parents) || p == i:
+			return nil, errors.New("cosi: invalid parent in tree")
+		default:
+			t.children[p] = append(t.children[p], i)
+		}
+	}
+	if t.root == -1 {
+		return nil, errors.New("cosi: tree has no root")
+	}
+	// every node must be reachable from the root, otherwise there is a cycle
+	reached := 0
+	queue := []int{t.root}
+	for len(queue) > 0 {
+		reached++
+		queue = append(queue[1:], t.children[queue[0]]...)
+	}
+	if reached != len(parents) {
+		return nil, errors.New("cosi: tree has a cycle")
+	}
+	return t, nil
+}
+
+// NewNaryTree returns the complete tree over n nodes rooted at node 0, where
+// the children of node i are the nodes i*branching+1 to i*branching+branching.
+// A star, where the leader talks to every other node, is the tree with a
+// branching factor of n-1.
+func NewNaryTree(n, branching int) (*Tree, error) {
+	if n < 1 || branching < 1 {
+		return nil, errors.New("cosi: invalid tree size or branching factor")
+	}
+	parents := make([]int, n)
+	parents[0] = -1
+	for i := 1; i < n; i++ {
+		parents[i] = (i - 1) / branching
+	}
+	return NewTree(parents)
+}
+
+// Size returns the number of nodes of the tree.
+func (t *Tree) Size() int {
+	return len(t.parents)
+}
+
+// Root returns the index of the root of the tree.
+func (t *Tree) Root() int {
+	return t.root
+}
+
+// Parent returns the index of the parent of node i, or -1 for the root.
+func (t *Tree) Parent(i int) int {
+	return t.parents[i]
+}
+
+// Children returns the indices of the children of node i.
+func (t *Tree) Children(i int) []int {
+	return t.children[i]
+}
+
+// height returns the length of the longest path from node i to a leaf.
+func (t *Tree) height(i int) int {
+	h := 0
+	for _, c := range t.children[i] {
+		if ch := t.height(c) + 1; ch > h {
+			h = ch
+		}
+	}
+	return h
+}
+
+// depth returns the length of the path from the root to node i.
+func (t *Tree) depth(i int) int {
+	d := 0
+	for ; t.parents[i] != -1; i = t.parents[i] {
+		d++
+	}
+	return d
+}
+
+// inSubtree returns whether node j is in the subtree rooted at node i.
+func (t *Tree) inSubtree(j, i int) bool {
+	for ; j != -1; j = t.parents[j] {
+		if j == i {
+			return true
+		}
+	}
+	return false
+}
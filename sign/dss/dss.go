@@ -12,6 +12,9 @@
 // the `Signature` method.
 // The resulting signature is compatible with the EdDSA verification function.
 // against the longterm distributed key.
+// The random secrets of many signatures can be generated at once with a
+// BatchDKG and kept in a NoncePool, from which `NewDSSFromPool` draws them,
+// so that signing only requires the exchange of partial signatures.
 package dss
 
 import (
@@ -20,6 +23,7 @@ import (
 	"errors"
 
 	"go.dedis.ch/kyber/v3"
+	"go.dedis.ch/kyber/v3/proof/transcript"
 	"go.dedis.ch/kyber/v3/share"
 	"go.dedis.ch/kyber/v3/sign/eddsa"
 	"go.dedis.ch/kyber/v3/sign/schnorr"
@@ -58,6 +62,7 @@ type DSS struct {
 	partialsIdx  map[int]bool
 	signed       bool
 	sessionID    []byte
+	transcript   *transcript.Transcript
 }
 
 // PartialSig is partial representation of the final distributed signature. It
@@ -105,6 +110,21 @@ func NewDSS(suite Suite, secret kyber.Scalar, participants []kyber.Point,
 	}, nil
 }
 
+// NewDSSTranscript is like NewDSS, but the challenge of the distributed
+// signature is derived from the transcript t as done by
+// schnorr.SignTranscript, instead of the EdDSA hash. The resulting signature
+// must be verified with VerifyTranscript on a transcript in the same state
+// as t. t itself is never modified.
+func NewDSSTranscript(suite Suite, t *transcript.Transcript, secret kyber.Scalar,
+	participants []kyber.Point, long, random DistKeyShare, msg []byte, T int) (*DSS, error) {
+	d, err := NewDSS(suite, secret, participants, long, random, msg, T)
+	if err != nil {
+		return nil, err
+	}
+	d.transcript = t.Clone()
+	return d, nil
+}
+
 // PartialSig generates the partial signature related to this DSS. This
 // PartialSig can be broadcasted to every other participant or only to a
 // trusted combiner as described in the paper.
@@ -198,6 +218,10 @@ func (d *DSS) Signature() ([]byte, error) {
 }
 
 func (d *DSS) hashSig() kyber.Scalar {
+	if d.transcript != nil {
+		return schnorr.TranscriptChallenge(d.transcript.Clone(),
+			d.long.Commitments()[0], d.random.Commitments()[0], d.msg)
+	}
 	// H(R || A || msg) with
 	//  * R = distributed random "key"
 	//  * A = distributed public key
@@ -215,6 +239,13 @@ func Verify(public kyber.Point, msg, sig []byte) error {
 	return eddsa.Verify(public, msg, sig)
 }
 
+// VerifyTranscript takes a public key, a message and a signature issued by a
+// DSS created with NewDSSTranscript, and returns an error if the signature is
+// invalid. The transcript t is updated by the call.
+func VerifyTranscript(g kyber.Group, t *transcript.Transcript, public kyber.Point, msg, sig []byte) error {
+	return schnorr.VerifyTranscript(g, t, public, msg, sig)
+}
+
 // Hash returns the hash representation of this PartialSig to be used in a
 // signature.
 func (ps *PartialSig) Hash(s Suite) []byte {
@@ -8,6 +8,7 @@ import (
 	"github.com/stretchr/testify/require"
 	"go.dedis.ch/kyber/v3"
 	"go.dedis.ch/kyber/v3/group/edwards25519"
+	"go.dedis.ch/kyber/v3/proof/transcript"
 	dkg "go.dedis.ch/kyber/v3/share/dkg/rabin"
 	"go.dedis.ch/kyber/v3/sign/eddsa"
 	"go.dedis.ch/kyber/v3/sign/schnorr"
@@ -135,6 +136,33 @@ func TestDSSSignature(t *testing.T) {
 	assert.Nil(t, Verify(longterms[0].Public(), dss0.msg, buff))
 }
 
+func TestDSSSignatureTranscript(test *testing.T) {
+	tr := transcript.New(suite, "test")
+	dsss := make([]*DSS, nbParticipants)
+	pss := make([]*PartialSig, nbParticipants)
+	for i := 0; i < nbParticipants; i++ {
+		d, err := NewDSSTranscript(suite, tr, partSec[i], partPubs, longterms[i], randoms[i], []byte("hello"), t)
+		require.NoError(test, err)
+		dsss[i] = d
+		pss[i], err = d.PartialSig()
+		require.NoError(test, err)
+	}
+	for i, d := range dsss {
+		for j, ps := range pss {
+			if i == j {
+				continue
+			}
+			require.NoError(test, d.ProcessPartialSig(ps))
+		}
+	}
+	sig, err := dsss[0].Signature()
+	require.NoError(test, err)
+	require.NoError(test, VerifyTranscript(suite, tr.Clone(), longterms[0].Public(), []byte("hello"), sig))
+	require.NoError(test, schnorr.VerifyTranscript(suite, tr.Clone(), longterms[0].Public(), []byte("hello"), sig))
+	require.Error(test, VerifyTranscript(suite, transcript.New(suite, "other"), longterms[0].Public(), []byte("hello"), sig))
+	require.Error(test, Verify(longterms[0].Public(), []byte("hello"), sig))
+}
+
 func getDSS(i int) *DSS {
 	dss, err := NewDSS(suite, partSec[i], partPubs, longterms[i], randoms[i], []byte("hello"), t)
 	if dss == nil || err != nil {
@@ -0,0 +1,507 @@
+package dss
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+
+	"go.dedis.ch/kyber/v3"
+	"go.dedis.ch/kyber/v3/share"
+	dkg "go.dedis.ch/kyber/v3/share/dkg/pedersen"
+)
+
+// BatchDKG runs many instances of the pedersen DKG at once between the same
+// participants, to generate the random distributed secrets of many
+// signatures with a single exchange of messages. Every message of a
+// BatchDKG carries one message per instance, in the order of the instances.
+type BatchDKG struct {
+	dkgs []*dkg.DistKeyGenerator
+}
+
+// BatchDeal holds the deals of all the instances for one participant.
+type BatchDeal struct {
+	Deals []*dkg.Deal
+}
+
+// BatchResponse holds the responses of a participant to the deals of all the
+// instances of a dealer.
+type BatchResponse struct {
+	Responses []*dkg.Response
+}
+
+// BatchJustification holds the justifications of a dealer for all the
+// instances. The justification of an instance is nil if none was needed.
+type BatchJustification struct {
+	Justifications []*dkg.Justification
+}
+
+// NewBatchDKG returns a BatchDKG running n instances of the DKG with the
+// longterm secret of this node, the list of participants and the threshold
+// t, as for dkg.NewDistKeyGenerator.
+func NewBatchDKG(suite dkg.Suite, longterm kyber.Scalar, participants []kyber.Point, t, n int) (*BatchDKG, error) {
+	if n <= 0 {
+		return nil, errors.New("dss: invalid number of instances")
+	}
+	b := &BatchDKG{dkgs: make([]*dkg.DistKeyGenerator, n)}
+	for i := range b.dkgs {
+		d, err := dkg.NewDistKeyGenerator(suite, longterm, participants, t)
+		if err != nil {
+			return nil, err
+		}
+		b.dkgs[i] = d
+	}
+	return b, nil
+}
+
+// Deals returns the batched deals to send to each other participant,
+// indexed by its index in the list of participants.
+func (b *BatchDKG) Deals() (map[int]*BatchDeal, error) {
+	deals := make(map[int]*BatchDeal)
+	for _, d := range b.dkgs {
+		ds, err := d.Deals()
+		if err != nil {
+			return nil, err
+		}
+		for i, deal := range ds {
+			if deals[i] == nil {
+				deals[i] = &BatchDeal{}
+			}
+			deals[i].Deals = append(deals[i].Deals, deal)
+		}
+	}
+	return deals, nil
+}
+
+// ProcessDeal processes the batched deals of a dealer and returns the
+// responses to broadcast to every other participant.
+func (b *BatchDKG) ProcessDeal(bd *BatchDeal) (*BatchResponse, error) {
+	if len(bd.Deals) != len(b.dkgs) {
+		return nil, errors.New("dss: wrong number of deals in batch")
+	}
+	br := &BatchResponse{Responses: make([]*dkg.Response, len(b.dkgs))}
+	for i, d := range b.dkgs {
+		resp, err := d.ProcessDeal(bd.Deals[i])
+		if err != nil {
+			return nil, fmt.Errorf("dss: instance %d: %v", i, err)
+		}
+		br.Responses[i] = resp
+	}
+	return br, nil
+}
+
+// ProcessResponse processes the batched responses of a participant. It
+// returns a BatchJustification to broadcast if this node is the dealer
+// concerned by a complaint, or nil otherwise.
+func (b *BatchDKG) ProcessResponse(br *BatchResponse) (*BatchJustification, error) {
+	if len(br.Responses) != len(b.dkgs) {
+		return nil, errors.New("dss: wrong number of responses in batch")
+	}
+	var bj *BatchJustification
+	for i, d := range b.dkgs {
+		j, err := d.ProcessResponse(br.Responses[i])
+		if err != nil {
+			return nil, fmt.Errorf("dss: instance %d: %v", i, err)
+		}
+		if j != nil {
+			if bj == nil {
+				bj = &BatchJustification{Justifications: make([]*dkg.Justification, len(b.dkgs))}
+			}
+			bj.Justifications[i] = j
+		}
+	}
+	return bj, nil
+}
+
+// ProcessJustification processes the batched justifications of a dealer.
+func (b *BatchDKG) ProcessJustification(bj *BatchJustification) error {
+	if len(bj.Justifications) != len(b.dkgs) {
+		return errors.New("dss: wrong number of justifications in batch")
+	}
+	for i, d := range b.dkgs {
+		if bj.Justifications[i] == nil {
+			continue
+		}
+		if err := d.ProcessJustification(bj.Justifications[i]); err != nil {
+			return fmt.Errorf("dss: instance %d: %v", i, err)
+		}
+	}
+	return nil
+}
+
+// Certified returns true if every instance is certified.
+func (b *BatchDKG) Certified() bool {
+	for _, d := range b.dkgs {
+		if !d.Certified() {
+			return false
+		}
+	}
+	return true
+}
+
+// DistKeyShares returns the distributed key shares of all the instances,
+// once they are certified.
+func (b *BatchDKG) DistKeyShares() ([]*dkg.DistKeyShare, error) {
+	shares := make([]*dkg.DistKeyShare, len(b.dkgs))
+	for i, d := range b.dkgs {
+		dks, err := d.DistKeyShare()
+		if err != nil {
+			return nil, fmt.Errorf("dss: instance %d: %v", i, err)
+		}
+		shares[i] = dks
+	}
+	return shares, nil
+}
+
+// ErrNonceUnavailable is returned when a random distributed secret of a
+// NoncePool does not exist or has already been used.
+var ErrNonceUnavailable = errors.New("dss: nonce unavailable or already used")
+
+// ErrNonceExists is returned when a random distributed secret is stored
+// under an index which is already in use or has already been taken.
+var ErrNonceExists = errors.New("dss: nonce index already stored or used")
+
+// NonceStore stores the encoded random distributed secrets of a NoncePool.
+// Implementations must be safe for concurrent use.
+type NonceStore interface {
+	// Put stores the data under the given index. It returns ErrNonceExists,
+	// and leaves the store unchanged, if data is stored under the index or
+	// if the index has ever been taken, even before a restart.
+	Put(index int, data []byte) error
+	// Take removes the data stored under the given index and returns it,
+	// or returns ErrNonceUnavailable. Once Take has returned the data,
+	// the index must never be available again, even after a restart.
+	Take(index int) ([]byte, error)
+	// Indices returns the indices currently stored, in increasing order.
+	Indices() ([]int, error)
+}
+
+// MemoryNonceStore is a NonceStore that keeps the data in memory. It does
+// not survive restarts, and is mostly useful for tests.
+type MemoryNonceStore struct {
+	mu    sync.Mutex
+	data  map[int][]byte
+	taken map[int]bool
+}
+
+// NewMemoryNonceStore returns an empty MemoryNonceStore.
+func NewMemoryNonceStore() *MemoryNonceStore {
+	return &MemoryNonceStore{data: make(map[int][]byte), taken: make(map[int]bool)}
+}
+
+// Put implements NonceStore.
+func (m *MemoryNonceStore) Put(index int, data []byte) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if _, ok := m.data[index]; ok || m.taken[index] {
+		return ErrNonceExists
+	}
+	m.data[index] = data
+	return nil
+}
+
+// Take implements NonceStore.
+func (m *MemoryNonceStore) Take(index int) ([]byte, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	data, ok := m.data[index]
+	if !ok {
+		return nil, ErrNonceUnavailable
+	}
+	delete(m.data, index)
+	m.taken[index] = true
+	return data, nil
+}
+
+// Indices implements NonceStore.
+func (m *MemoryNonceStore) Indices() ([]int, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	indices := make([]int, 0, len(m.data))
+	for i := range m.data {
+		indices = append(indices, i)
+	}
+	sort.Ints(indices)
+	return indices, nil
+}
+
+// FileNonceStore is a NonceStore keeping each entry in its own file of a
+// directory. An entry is deleted from the disk, and the deletion synced,
+// before Take returns it, so that a crash can lose an entry but never
+// hand it out twice. An empty file is left in its place to record that the
+// index has been taken.
+type FileNonceStore struct {
+	mu  sync.Mutex
+	dir string
+}
+
+const (
+	nonceFileSuffix = ".nonce"
+	takenFileSuffix = ".taken"
+)
+
+// NewFileNonceStore returns a FileNonceStore storing its entries in dir,
+// which is created if needed.
+func NewFileNonceStore(dir string) (*FileNonceStore, error) {
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		return nil, err
+	}
+	return &FileNonceStore{dir: dir}, nil
+}
+
+func (f *FileNonceStore) path(index int) string {
+	return filepath.Join(f.dir, strconv.Itoa(index)+nonceFileSuffix)
+}
+
+func (f *FileNonceStore) takenPath(index int) string {
+	return filepath.Join(f.dir, strconv.Itoa(index)+takenFileSuffix)
+}
+
+// syncDir makes the creation or removal of files in the directory durable.
+func (f *FileNonceStore) syncDir() error {
+	d, err := os.Open(f.dir)
+	if err != nil {
+		return err
+	}
+	defer d.Close()
+	return d.Sync()
+}
+
+// Put implements NonceStore. The data is written to a temporary file which
+// is linked to the entry once synced, which fails if the entry exists.
+func (f *FileNonceStore) Put(index int, data []byte) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if _, err := os.Stat(f.takenPath(index)); err == nil {
+		return ErrNonceExists
+	} else if !os.IsNotExist(err) {
+		return err
+	}
+	tmp, err := ioutil.TempFile(f.dir, "tmp")
+	if err != nil {
+		return err
+	}
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		os.Remove(tmp.Name())
+		return err
+	}
+	if err := tmp.Sync(); err != nil {
+		tmp.Close()
+		os.Remove(tmp.Name())
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		os.Remove(tmp.Name())
+		return err
+	}
+	err = os.Link(tmp.Name(), f.path(index))
+	os.Remove(tmp.Name())
+	if os.IsExist(err) {
+		return ErrNonceExists
+	} else if err != nil {
+		return err
+	}
+	return f.syncDir()
+}
+
+// Take implements NonceStore.
+func (f *FileNonceStore) Take(index int) ([]byte, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	data, err := ioutil.ReadFile(f.path(index))
+	if os.IsNotExist(err) {
+		return nil, ErrNonceUnavailable
+	} else if err != nil {
+		return nil, err
+	}
+	taken, err := os.OpenFile(f.takenPath(index), os.O_WRONLY|os.O_CREATE, 0600)
+	if err != nil {
+		return nil, err
+	}
+	if err := taken.Close(); err != nil {
+		return nil, err
+	}
+	if err := os.Remove(f.path(index)); err != nil {
+		return nil, err
+	}
+	if err := f.syncDir(); err != nil {
+		return nil, err
+	}
+	return data, nil
+}
+
+// Indices implements NonceStore.
+func (f *FileNonceStore) Indices() ([]int, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	files, err := ioutil.ReadDir(f.dir)
+	if err != nil {
+		return nil, err
+	}
+	var indices []int
+	for _, file := range files {
+		name := file.Name()
+		if !strings.HasSuffix(name, nonceFileSuffix) {
+			continue
+		}
+		i, err := strconv.Atoi(strings.TrimSuffix(name, nonceFileSuffix))
+		if err != nil {
+			continue
+		}
+		indices = append(indices, i)
+	}
+	sort.Ints(indices)
+	return indices, nil
+}
+
+// NoncePool holds random distributed secrets, generated in advance with a
+// BatchDKG, to be used by DSS instances. Each secret is stored under an
+// index, which must be the same for all participants: the participants of
+// a signature agree on the index of the secret to use, for example by
+// using the indices in increasing order. A secret is removed from the store
+// when it is drawn, so that it is used at most once.
+type NoncePool struct {
+	mu    sync.Mutex
+	group kyber.Group
+	store NonceStore
+}
+
+// NewNoncePool returns a NoncePool over the given store.
+func NewNoncePool(group kyber.Group, store NonceStore) *NoncePool {
+	return &NoncePool{group: group, store: store}
+}
+
+// AddError is returned by Add when a share cannot be stored. Add is not
+// atomic: the shares of the indices below Index have been stored, and the
+// remaining ones can be added again from Index once the cause is fixed.
+type AddError struct {
+	Index int
+	Err   error
+}
+
+func (e *AddError) Error() string {
+	return fmt.Sprintf("dss: storing nonce %d: %v", e.Index, e.Err)
+}
+
+// Add stores the given distributed key shares under the indices first,
+// first+1, etc. The shares of the instances of a BatchDKG must be added in
+// the order returned by DistKeyShares, with the same first index by all
+// the participants. Only the share and the public commitments are stored.
+// An index which is in the pool or has already been taken cannot be added
+// again.
+//
+// Nothing is stored if one of the indices is already in the pool. Otherwise
+// the shares are stored one by one, and if one fails, an *AddError holding
+// its index is returned, while the shares before it stay in the pool.
+func (p *NoncePool) Add(first int, shares []*dkg.DistKeyShare) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	indices, err := p.store.Indices()
+	if err != nil {
+		return err
+	}
+	stored := make(map[int]bool, len(indices))
+	for _, i := range indices {
+		stored[i] = true
+	}
+	for j := range shares {
+		if stored[first+j] {
+			return fmt.Errorf("dss: nonce %d already in the pool", first+j)
+		}
+	}
+	for j, dks := range shares {
+		data, err := marshalDistKeyShare(dks)
+		if err == nil {
+			err = p.store.Put(first+j, data)
+		}
+		if err != nil {
+			return &AddError{Index: first + j, Err: err}
+		}
+	}
+	return nil
+}
+
+// Available returns the indices of the secrets not used yet, in increasing
+// order.
+func (p *NoncePool) Available() ([]int, error) {
+	return p.store.Indices()
+}
+
+// Take removes the secret stored under the given index from the pool and
+// returns it. It returns ErrNonceUnavailable if there is no such secret,
+// in particular if it has already been taken.
+func (p *NoncePool) Take(index int) (DistKeyShare, error) {
+	data, err := p.store.Take(index)
+	if err != nil {
+		return nil, err
+	}
+	return unmarshalDistKeyShare(p.group, data)
+}
+
+// NewDSSFromPool is like NewDSS, but draws the random distributed secret
+// of the given index from the pool. The secret is removed from the pool
+// even if an error is returned afterwards.
+func NewDSSFromPool(suite Suite, secret kyber.Scalar, participants []kyber.Point,
+	long DistKeyShare, pool *NoncePool, index int, msg []byte, T int) (*DSS, error) {
+	random, err := pool.Take(index)
+	if err != nil {
+		return nil, err
+	}
+	return NewDSS(suite, secret, participants, long, random, msg, T)
+}
+
+// marshalDistKeyShare encodes the index of the share, the number of
+// commitments, the commitments and the share.
+func marshalDistKeyShare(dks *dkg.DistKeyShare) ([]byte, error) {
+	var b bytes.Buffer
+	_ = binary.Write(&b, binary.LittleEndian, uint32(dks.Share.I))
+	_ = binary.Write(&b, binary.LittleEndian, uint32(len(dks.Commits)))
+	for _, c := range dks.Commits {
+		if _, err := c.MarshalTo(&b); err != nil {
+			return nil, err
+		}
+	}
+	if _, err := dks.Share.V.MarshalTo(&b); err != nil {
+		return nil, err
+	}
+	return b.Bytes(), nil
+}
+
+func unmarshalDistKeyShare(g kyber.Group, data []byte) (*dkg.DistKeyShare, error) {
+	r := bytes.NewReader(data)
+	var index, n uint32
+	if err := binary.Read(r, binary.LittleEndian, &index); err != nil {
+		return nil, err
+	}
+	if err := binary.Read(r, binary.LittleEndian, &n); err != nil {
+		return nil, err
+	}
+	if int(n) > r.Len()/g.PointLen() {
+		return nil, errors.New("dss: invalid encoded nonce")
+	}
+	dks := &dkg.DistKeyShare{
+		Commits: make([]kyber.Point, n),
+		Share:   &share.PriShare{I: int(index), V: g.Scalar()},
+	}
+	for i := range dks.Commits {
+		dks.Commits[i] = g.Point()
+		if _, err := dks.Commits[i].UnmarshalFrom(r); err != nil {
+			return nil, err
+		}
+	}
+	if _, err := dks.Share.V.UnmarshalFrom(r); err != nil {
+		return nil, err
+	}
+	if r.Len() != 0 {
+		return nil, errors.New("dss: invalid encoded nonce")
+	}
+	return dks, nil
+}
@@ -0,0 +1,206 @@
+package dss
+
+import (
+	"errors"
+	"io/ioutil"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	pedersen "go.dedis.ch/kyber/v3/share/dkg/pedersen"
+)
+
+// genBatch runs a BatchDKG of n instances between all the participants and
+// returns the distributed key shares of each participant.
+func genBatch(tt *testing.T, n int) [][]*pedersen.DistKeyShare {
+	batches := make([]*BatchDKG, nbParticipants)
+	for i := range batches {
+		b, err := NewBatchDKG(suite, partSec[i], partPubs, t, n)
+		require.NoError(tt, err)
+		batches[i] = b
+	}
+	var resps []*BatchResponse
+	for _, b := range batches {
+		deals, err := b.Deals()
+		require.NoError(tt, err)
+		for i, d := range deals {
+			resp, err := batches[i].ProcessDeal(d)
+			require.NoError(tt, err)
+			resps = append(resps, resp)
+		}
+	}
+	for _, resp := range resps {
+		for i, b := range batches {
+			if resp.Responses[0].Response.Index == uint32(i) {
+				continue
+			}
+			j, err := b.ProcessResponse(resp)
+			require.NoError(tt, err)
+			require.Nil(tt, j)
+		}
+	}
+	shares := make([][]*pedersen.DistKeyShare, nbParticipants)
+	for i, b := range batches {
+		require.True(tt, b.Certified())
+		var err error
+		shares[i], err = b.DistKeyShares()
+		require.NoError(tt, err)
+		require.Len(tt, shares[i], n)
+	}
+	return shares
+}
+
+func TestNoncePool(tt *testing.T) {
+	shares := genBatch(tt, 3)
+	dir, err := ioutil.TempDir("", "dss-pool")
+	require.NoError(tt, err)
+	defer os.RemoveAll(dir)
+
+	pools := make([]*NoncePool, nbParticipants)
+	for i := range pools {
+		var store NonceStore = NewMemoryNonceStore()
+		if i == 0 {
+			store, err = NewFileNonceStore(dir)
+			require.NoError(tt, err)
+		}
+		pools[i] = NewNoncePool(suite, store)
+		require.NoError(tt, pools[i].Add(10, shares[i]))
+	}
+	require.Error(tt, pools[0].Add(12, shares[0]), "indices already in the pool")
+	available, err := pools[0].Available()
+	require.NoError(tt, err)
+	require.Equal(tt, []int{10, 11, 12}, available)
+
+	msg := []byte("hello pool")
+	for _, index := range []int{11, 10, 12} {
+		dsss := make([]*DSS, nbParticipants)
+		for i := range dsss {
+			dsss[i], err = NewDSSFromPool(suite, partSec[i], partPubs, longterms[i], pools[i], index, msg, t)
+			require.NoError(tt, err)
+		}
+		for i := range dsss {
+			ps, err := dsss[i].PartialSig()
+			require.NoError(tt, err)
+			for j := range dsss {
+				if i != j {
+					require.NoError(tt, dsss[j].ProcessPartialSig(ps))
+				}
+			}
+		}
+		sig, err := dsss[0].Signature()
+		require.NoError(tt, err)
+		require.NoError(tt, Verify(longterms[0].Public(), msg, sig))
+	}
+
+	// every nonce is used once, even after a restart
+	for i := range pools {
+		_, err := NewDSSFromPool(suite, partSec[i], partPubs, longterms[i], pools[i], 10, msg, t)
+		require.Equal(tt, ErrNonceUnavailable, err)
+	}
+	store, err := NewFileNonceStore(dir)
+	require.NoError(tt, err)
+	restarted := NewNoncePool(suite, store)
+	available, err = restarted.Available()
+	require.NoError(tt, err)
+	require.Empty(tt, available)
+	_, err = restarted.Take(12)
+	require.Equal(tt, ErrNonceUnavailable, err)
+
+	// a used nonce cannot be added again, even after a restart
+	require.Equal(tt, &AddError{Index: 12, Err: ErrNonceExists}, restarted.Add(12, shares[0][2:]))
+	require.Equal(tt, &AddError{Index: 12, Err: ErrNonceExists}, pools[1].Add(12, shares[1][2:]))
+}
+
+// failingStore is a NonceStore whose Put fails once under a given index.
+type failingStore struct {
+	NonceStore
+	fail int
+}
+
+func (f *failingStore) Put(index int, data []byte) error {
+	if index == f.fail {
+		f.fail = -1
+		return errors.New("disk full")
+	}
+	return f.NonceStore.Put(index, data)
+}
+
+func TestNoncePoolAddError(tt *testing.T) {
+	shares := genBatch(tt, 3)
+	pool := NewNoncePool(suite, &failingStore{NonceStore: NewMemoryNonceStore(), fail: 6})
+	err := pool.Add(5, shares[0])
+	require.IsType(tt, &AddError{}, err)
+	require.Equal(tt, 6, err.(*AddError).Index)
+	available, err := pool.Available()
+	require.NoError(tt, err)
+	require.Equal(tt, []int{5}, available)
+
+	// the batch is completed from the index which failed
+	require.NoError(tt, pool.Add(6, shares[0][1:]))
+	available, err = pool.Available()
+	require.NoError(tt, err)
+	require.Equal(tt, []int{5, 6, 7}, available)
+}
+
+func TestNonceStorePut(tt *testing.T) {
+	dir, err := ioutil.TempDir("", "dss-pool")
+	require.NoError(tt, err)
+	defer os.RemoveAll(dir)
+	fileStore, err := NewFileNonceStore(dir)
+	require.NoError(tt, err)
+
+	for _, store := range []NonceStore{NewMemoryNonceStore(), fileStore} {
+		// concurrent puts under the same index: exactly one succeeds
+		const n = 10
+		errs := make(chan error, n)
+		for i := 0; i < n; i++ {
+			go func(i int) {
+				errs <- store.Put(1, []byte{byte(i)})
+			}(i)
+		}
+		succeeded := 0
+		for i := 0; i < n; i++ {
+			if err := <-errs; err == nil {
+				succeeded++
+			} else {
+				require.Equal(tt, ErrNonceExists, err)
+			}
+		}
+		require.Equal(tt, 1, succeeded)
+		data, err := store.Take(1)
+		require.NoError(tt, err)
+		require.Len(tt, data, 1)
+		require.True(tt, data[0] < n)
+
+		require.Equal(tt, ErrNonceExists, store.Put(1, []byte{0}))
+		indices, err := store.Indices()
+		require.NoError(tt, err)
+		require.Empty(tt, indices)
+	}
+}
+
+func TestNoncePoolPersistence(tt *testing.T) {
+	shares := genBatch(tt, 2)
+	dir, err := ioutil.TempDir("", "dss-pool")
+	require.NoError(tt, err)
+	defer os.RemoveAll(dir)
+
+	store, err := NewFileNonceStore(dir)
+	require.NoError(tt, err)
+	require.NoError(tt, NewNoncePool(suite, store).Add(0, shares[0]))
+
+	store, err = NewFileNonceStore(dir)
+	require.NoError(tt, err)
+	pool := NewNoncePool(suite, store)
+	dks, err := pool.Take(1)
+	require.NoError(tt, err)
+	require.Equal(tt, shares[0][1].Share.I, dks.PriShare().I)
+	require.True(tt, shares[0][1].Share.V.Equal(dks.PriShare().V))
+	require.Len(tt, dks.Commitments(), len(shares[0][1].Commits))
+	for i, c := range dks.Commitments() {
+		require.True(tt, c.Equal(shares[0][1].Commits[i]))
+	}
+	available, err := pool.Available()
+	require.NoError(tt, err)
+	require.Equal(tt, []int{0}, available)
+}
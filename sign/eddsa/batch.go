@@ -0,0 +1,129 @@
+package eddsa
+
+import (
+	"crypto/cipher"
+	"errors"
+	"fmt"
+	"sort"
+
+	"go.dedis.ch/kyber/v3"
+	"go.dedis.ch/kyber/v3/util/random"
+)
+
+// BatchError is returned by BatchVerify when some signatures of the batch
+// are invalid.
+type BatchError struct {
+	// Invalid holds the indices of the invalid signatures, in increasing
+	// order.
+	Invalid []int
+}
+
+func (e *BatchError) Error() string {
+	return fmt.Sprintf("eddsa: invalid signatures in batch at indices %v", e.Invalid)
+}
+
+// batchEntry is a decoded signature of a batch.
+type batchEntry struct {
+	index int
+	R, A  kyber.Point
+	s, h  kyber.Scalar
+}
+
+// BatchVerify checks that sigs[i] is a valid signature of msgs[i] under
+// publics[i], for every i. It returns nil if all signatures are valid, and
+// a *BatchError holding the indices of the invalid ones otherwise.
+//
+// The signatures are checked together, by verifying a random linear
+// combination of their verification equations with a single multi-scalar
+// multiplication, which is several times faster than verifying them one
+// after the other. If the batch fails, it is split in halves which are
+// checked recursively, to locate the invalid signatures.
+//
+// BatchVerify uses the cofactored verification equation, as a batch check
+// cannot be made consistent with the cofactorless one. It accepts every
+// signature accepted by Verify, but also the signatures whose R or public
+// key have a small-order component that only invalidates them for Verify;
+// such signatures are never produced by Sign.
+func BatchVerify(publics []kyber.Point, msgs, sigs [][]byte) error {
+	if len(publics) != len(msgs) || len(msgs) != len(sigs) {
+		return errors.New("eddsa: mismatched number of public keys, messages and signatures")
+	}
+	encoded := make([][]byte, len(publics))
+	for i, p := range publics {
+		buf, err := p.MarshalBinary()
+		if err != nil {
+			return err
+		}
+		encoded[i] = buf
+	}
+	return batchVerify(legacyRules, encoded, msgs, sigs)
+}
+
+// batchVerify checks a batch of signatures with the rules r, which must use
+// the cofactored equation.
+func batchVerify(r rules, publics, msgs, sigs [][]byte) error {
+	var invalid []int
+	entries := make([]*batchEntry, 0, len(sigs))
+	for i := range sigs {
+		e, err := decodeEntry(r, i, publics[i], msgs[i], sigs[i])
+		if err != nil {
+			invalid = append(invalid, i)
+			continue
+		}
+		entries = append(entries, e)
+	}
+
+	rand := random.New()
+	if !checkBatch(entries, rand) {
+		invalid = append(invalid, locateInvalid(entries, rand)...)
+	}
+	if len(invalid) == 0 {
+		return nil
+	}
+	sort.Ints(invalid)
+	return &BatchError{Invalid: invalid}
+}
+
+// checkBatch returns whether the entries satisfy
+//
+//	8 * (sum z_i*s_i*B - sum z_i*R_i - sum z_i*h_i*A_i) == 0
+//
+// for random 128-bit coefficients z_i.
+func checkBatch(entries []*batchEntry, rand cipher.Stream) bool {
+	if len(entries) == 0 {
+		return true
+	}
+	scalars := make([]kyber.Scalar, 0, 2*len(entries)+1)
+	points := make([]kyber.Point, 0, 2*len(entries)+1)
+	sB := group.Scalar().Zero()
+	for _, e := range entries {
+		z := group.Scalar().SetBytes(random.Bits(128, false, rand))
+		sB.Add(sB, group.Scalar().Mul(z, e.s))
+		scalars = append(scalars, group.Scalar().Neg(z),
+			group.Scalar().Neg(group.Scalar().Mul(z, e.h)))
+		points = append(points, e.R, e.A)
+	}
+	scalars = append(scalars, sB)
+	points = append(points, group.Point().Base())
+
+	sum := group.MultiScalarMulVarTime(scalars, points)
+	for i := 0; i < 3; i++ {
+		sum.Add(sum, sum)
+	}
+	return sum.Equal(group.Point().Null())
+}
+
+// locateInvalid returns the indices of the invalid entries of a batch
+// which failed, by bisection.
+func locateInvalid(entries []*batchEntry, rand cipher.Stream) []int {
+	if len(entries) == 1 {
+		return []int{entries[0].index}
+	}
+	var invalid []int
+	for _, half := range [][]*batchEntry{entries[:len(entries)/2], entries[len(entries)/2:]} {
+		if !checkBatch(half, rand) {
+			invalid = append(invalid, locateInvalid(half, rand)...)
+		}
+	}
+	return invalid
+}
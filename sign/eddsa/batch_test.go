@@ -0,0 +1,73 @@
+package eddsa
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"go.dedis.ch/kyber/v3"
+	"go.dedis.ch/kyber/v3/group/edwards25519"
+)
+
+func batch(n int) ([]kyber.Point, [][]byte, [][]byte) {
+	suite := edwards25519.NewBlakeSHA256Ed25519()
+	publics := make([]kyber.Point, n)
+	msgs := make([][]byte, n)
+	sigs := make([][]byte, n)
+	for i := range sigs {
+		ed := NewEdDSA(suite.RandomStream())
+		publics[i] = ed.Public
+		msgs[i] = []byte(fmt.Sprintf("message %d", i))
+		sig, err := ed.Sign(msgs[i])
+		if err != nil {
+			panic(err)
+		}
+		sigs[i] = sig
+	}
+	return publics, msgs, sigs
+}
+
+func TestBatchVerify(t *testing.T) {
+	for _, n := range []int{0, 1, 2, 33, 200} {
+		publics, msgs, sigs := batch(n)
+		require.NoError(t, BatchVerify(publics, msgs, sigs), "n = %d", n)
+	}
+
+	publics, msgs, sigs := batch(64)
+	sigs[3] = append([]byte{}, sigs[3]...)
+	sigs[3][40] ^= 1
+	msgs[17] = []byte("forged")
+	publics[41] = publics[40]
+	sigs[63] = sigs[63][:32]
+	err := BatchVerify(publics, msgs, sigs)
+	require.IsType(t, &BatchError{}, err)
+	require.Equal(t, []int{3, 17, 41, 63}, err.(*BatchError).Invalid)
+	for _, i := range err.(*BatchError).Invalid {
+		require.Error(t, Verify(publics[i], msgs[i], sigs[i]))
+	}
+
+	require.Error(t, BatchVerify(publics, msgs[1:], sigs))
+}
+
+func benchmarkVerify(b *testing.B, n int, batched bool) {
+	publics, msgs, sigs := batch(n)
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if batched {
+			if err := BatchVerify(publics, msgs, sigs); err != nil {
+				b.Fatal(err)
+			}
+			continue
+		}
+		for j := range sigs {
+			if err := Verify(publics[j], msgs[j], sigs[j]); err != nil {
+				b.Fatal(err)
+			}
+		}
+	}
+}
+
+func BenchmarkVerifySequential64(b *testing.B)   { benchmarkVerify(b, 64, false) }
+func BenchmarkBatchVerify64(b *testing.B)        { benchmarkVerify(b, 64, true) }
+func BenchmarkVerifySequential1024(b *testing.B) { benchmarkVerify(b, 1024, false) }
+func BenchmarkBatchVerify1024(b *testing.B)      { benchmarkVerify(b, 1024, true) }
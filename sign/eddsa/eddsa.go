@@ -7,6 +7,7 @@ import (
 	"crypto/sha512"
 	"errors"
 	"fmt"
+	"hash"
 
 	"go.dedis.ch/kyber/v3"
 	"go.dedis.ch/kyber/v3/group/edwards25519"
@@ -75,7 +76,79 @@ func (e *EdDSA) UnmarshalBinary(buff []byte) error {
 
 // Sign will return a EdDSA signature of the message msg using Ed25519.
 func (e *EdDSA) Sign(msg []byte) ([]byte, error) {
+	return e.sign(nil, msg)
+}
+
+// SignCtx returns an Ed25519ctx signature of the message msg under the
+// context ctx, as defined in RFC 8032, section 5.1. The context separates
+// the signatures of different protocols or uses of the same key; it must
+// hold between 1 and 255 bytes.
+func (e *EdDSA) SignCtx(msg, ctx []byte) ([]byte, error) {
+	if len(ctx) == 0 {
+		return nil, errors.New("eddsa: Ed25519ctx requires a non-empty context")
+	}
+	dom, err := dom2(0, ctx)
+	if err != nil {
+		return nil, err
+	}
+	return e.sign(dom, msg)
+}
+
+// SignPh returns an Ed25519ph signature of the message msg under the
+// optional context ctx, as defined in RFC 8032, section 5.1. The message is
+// hashed with SHA-512 before being signed; to sign a message too large to
+// be held in memory, use SignPhHash.
+func (e *EdDSA) SignPh(msg, ctx []byte) ([]byte, error) {
+	h := NewPrehash()
+	_, _ = h.Write(msg)
+	return e.SignPhHash(h, ctx)
+}
+
+// SignPhHash returns an Ed25519ph signature of the message written to h,
+// which must have been created by NewPrehash, under the optional context
+// ctx. It lets a message be signed in a streaming fashion. The state of h
+// is not modified.
+func (e *EdDSA) SignPhHash(h hash.Hash, ctx []byte) ([]byte, error) {
+	dom, err := dom2(1, ctx)
+	if err != nil {
+		return nil, err
+	}
+	ph, err := prehash(h)
+	if err != nil {
+		return nil, err
+	}
+	return e.sign(dom, ph)
+}
+
+// NewPrehash returns the hash function to which the message of an
+// Ed25519ph signature must be written before calling SignPhHash or
+// VerifyPhHash, that is SHA-512.
+func NewPrehash() hash.Hash {
+	return sha512.New()
+}
+
+func prehash(h hash.Hash) ([]byte, error) {
+	if h.Size() != sha512.Size {
+		return nil, errors.New("eddsa: Ed25519ph requires a SHA-512 prehash")
+	}
+	return h.Sum(nil), nil
+}
+
+// dom2 returns the prefix of RFC 8032, section 5.1, that separates the
+// hashes of Ed25519ctx and Ed25519ph from those of Ed25519.
+func dom2(phflag byte, ctx []byte) ([]byte, error) {
+	if len(ctx) > 255 {
+		return nil, errors.New("eddsa: context longer than 255 bytes")
+	}
+	dom := []byte("SigEd25519 no Ed25519 collisions")
+	dom = append(dom, phflag, byte(len(ctx)))
+	return append(dom, ctx...), nil
+}
+
+// sign returns the signature of msg, whose hashes are prefixed by dom.
+func (e *EdDSA) sign(dom, msg []byte) ([]byte, error) {
 	hash := sha512.New()
+	_, _ = hash.Write(dom)
 	_, _ = hash.Write(e.prefix)
 	_, _ = hash.Write(msg)
 
@@ -84,7 +157,7 @@ func (e *EdDSA) Sign(msg []byte) ([]byte, error) {
 	R := group.Point().Mul(r, nil)
 
 	// challenge
-	// H( R || Public || Msg)
+	// H( dom || R || Public || Msg)
 	hash.Reset()
 	Rbuff, err := R.MarshalBinary()
 	if err != nil {
@@ -95,6 +168,7 @@ func (e *EdDSA) Sign(msg []byte) ([]byte, error) {
 		return nil, err
 	}
 
+	_, _ = hash.Write(dom)
 	_, _ = hash.Write(Rbuff)
 	_, _ = hash.Write(Abuff)
 	_, _ = hash.Write(msg)
@@ -121,7 +195,52 @@ func (e *EdDSA) Sign(msg []byte) ([]byte, error) {
 
 // Verify uses a public key, a message and a signature. It will return nil if
 // sig is a valid signature for msg created by key public, or an error otherwise.
+// It uses the cofactorless verification equation and does not check the
+// encodings of the signature; use VerifyWithSemantics to pin the rules
+// applied to edge-case signatures.
 func Verify(public kyber.Point, msg, sig []byte) error {
+	return verify(public, nil, msg, sig)
+}
+
+// VerifyCtx checks an Ed25519ctx signature, created by SignCtx, of msg
+// under the context ctx. It returns nil if sig is valid, or an error
+// otherwise.
+func VerifyCtx(public kyber.Point, msg, ctx, sig []byte) error {
+	if len(ctx) == 0 {
+		return errors.New("eddsa: Ed25519ctx requires a non-empty context")
+	}
+	dom, err := dom2(0, ctx)
+	if err != nil {
+		return err
+	}
+	return verify(public, dom, msg, sig)
+}
+
+// VerifyPh checks an Ed25519ph signature, created by SignPh or SignPhHash,
+// of msg under the context ctx. It returns nil if sig is valid, or an error
+// otherwise.
+func VerifyPh(public kyber.Point, msg, ctx, sig []byte) error {
+	h := NewPrehash()
+	_, _ = h.Write(msg)
+	return VerifyPhHash(public, h, ctx, sig)
+}
+
+// VerifyPhHash is like VerifyPh, but takes the message written to h, which
+// must have been created by NewPrehash. The state of h is not modified.
+func VerifyPhHash(public kyber.Point, h hash.Hash, ctx, sig []byte) error {
+	dom, err := dom2(1, ctx)
+	if err != nil {
+		return err
+	}
+	ph, err := prehash(h)
+	if err != nil {
+		return err
+	}
+	return verify(public, dom, ph, sig)
+}
+
+// verify checks the signature of msg, whose hash is prefixed by dom.
+func verify(public kyber.Point, dom, msg, sig []byte) error {
 	if len(sig) != 64 {
 		return fmt.Errorf("signature length invalid, expect 64 but got %v", len(sig))
 	}
@@ -136,12 +255,13 @@ func Verify(public kyber.Point, msg, sig []byte) error {
 		return fmt.Errorf("schnorr: s invalid scalar %s", err)
 	}
 
-	// reconstruct h = H(R || Public || Msg)
+	// reconstruct h = H(dom || R || Public || Msg)
 	Pbuff, err := public.MarshalBinary()
 	if err != nil {
 		return err
 	}
 	hash := sha512.New()
+	_, _ = hash.Write(dom)
 	_, _ = hash.Write(sig[:32])
 	_, _ = hash.Write(Pbuff)
 	_, _ = hash.Write(msg)
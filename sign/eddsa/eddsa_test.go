@@ -5,6 +5,7 @@ import (
 	"bytes"
 	"compress/gzip"
 	"crypto/cipher"
+	"crypto/sha256"
 	"encoding/hex"
 	"math/rand"
 	"os"
@@ -204,3 +205,97 @@ func TestGolden(t *testing.T) {
 		t.Fatalf("error reading test data: %s", err)
 	}
 }
+
+// Ed25519ctx and Ed25519ph test vectors from RFC 8032, sections 7.2 and 7.3.
+var EdDSAVariantTestVectors = []struct {
+	private   string
+	public    string
+	message   string
+	context   string
+	prehash   bool
+	signature string
+}{
+	{"0305334e381af78f141cb666f6199f57bc3495335a256a95bd2a55bf546663f6",
+		"dfc9425e4f968f7f0c29f0259cf5f9aed6851c2bb4ad8bfb860cfee0ab248292",
+		"f726936d19c800494e3fdaff20b276a8", "666f6f", false,
+		"55a4cc2f70a54e04288c5f4cd1e45a7bb520b36292911876cada7323198dd87a8b36950b95130022907a7fb7c4e9b2d5f6cca685a587b4b21f4b888e4e7edb0d"},
+	{"0305334e381af78f141cb666f6199f57bc3495335a256a95bd2a55bf546663f6",
+		"dfc9425e4f968f7f0c29f0259cf5f9aed6851c2bb4ad8bfb860cfee0ab248292",
+		"f726936d19c800494e3fdaff20b276a8", "626172", false,
+		"fc60d5872fc46b3aa69f8b5b4351d5808f92bcc044606db097abab6dbcb1aee3216c48e8b3b66431b5b186d1d28f8ee15a5ca2df6668346291c2043d4eb3e90d"},
+	{"0305334e381af78f141cb666f6199f57bc3495335a256a95bd2a55bf546663f6",
+		"dfc9425e4f968f7f0c29f0259cf5f9aed6851c2bb4ad8bfb860cfee0ab248292",
+		"508e9e6882b979fea900f62adceaca35", "666f6f", false,
+		"8b70c1cc8310e1de20ac53ce28ae6e7207f33c3295e03bb5c0732a1d20dc64908922a8b052cf99b7c4fe107a5abb5b2c4085ae75890d02df26269d8945f84b0b"},
+	{"ab9c2853ce297ddab85c993b3ae14bcad39b2c682beabc27d6d4eb20711d6560",
+		"0f1d1274943b91415889152e893d80e93275a1fc0b65fd71b4b0dda10ad7d772",
+		"f726936d19c800494e3fdaff20b276a8", "666f6f", false,
+		"21655b5f1aa965996b3f97b3c849eafba922a0a62992f73b3d1b73106a84ad85e9b86a7b6005ea868337ff2d20a7f5fbd4cd10b0be49a68da2b2e0dc0ad8960f"},
+	{"833fe62409237b9d62ec77587520911e9a759cec1d19755b7da901b96dca3d42",
+		"ec172b93ad5e563bf4932c70e1245034c35467ef2efd4d64ebf819683467e2bf",
+		"616263", "", true,
+		"98a70222f0b8121aa9d30f813d683f809e462b469c7ff87639499bb94e6dae4131f85042463c2a355a2003d062adf5aaa10b8c61e636062aaad11c2a26083406"},
+}
+
+func TestEdDSAVariants(t *testing.T) {
+	for i, vec := range EdDSAVariantTestVectors {
+		seed, _ := hex.DecodeString(vec.private)
+		msg, _ := hex.DecodeString(vec.message)
+		ctx, _ := hex.DecodeString(vec.context)
+
+		ed := NewEdDSA(ConstantStream(seed))
+		data, _ := ed.Public.MarshalBinary()
+		assert.Equal(t, vec.public, hex.EncodeToString(data), "test %d", i)
+
+		var sig []byte
+		var err error
+		verify := VerifyCtx
+		if vec.prehash {
+			sig, err = ed.SignPh(msg, ctx)
+			verify = VerifyPh
+		} else {
+			sig, err = ed.SignCtx(msg, ctx)
+		}
+		assert.NoError(t, err)
+		assert.Equal(t, vec.signature, hex.EncodeToString(sig), "test %d", i)
+		assert.NoError(t, verify(ed.Public, msg, ctx, sig))
+
+		// The variants do not accept each other's signatures, nor pure
+		// Ed25519 ones, and they bind the context.
+		assert.Error(t, Verify(ed.Public, msg, sig))
+		assert.Error(t, verify(ed.Public, msg, []byte("baz"), sig))
+		if vec.prehash {
+			assert.Error(t, VerifyCtx(ed.Public, msg, ctx, sig))
+		} else {
+			assert.Error(t, VerifyPh(ed.Public, msg, ctx, sig))
+		}
+	}
+}
+
+func TestEdDSAPrehashStreaming(t *testing.T) {
+	ed := NewEdDSA(edwards25519.NewBlakeSHA256Ed25519().RandomStream())
+	msg := make([]byte, 1<<20)
+	_, err := rand.Read(msg)
+	assert.NoError(t, err)
+	ctx := []byte("firmware")
+
+	h := NewPrehash()
+	for i := 0; i < len(msg); i += 4096 {
+		_, _ = h.Write(msg[i : i+4096])
+	}
+	sig, err := ed.SignPhHash(h, ctx)
+	assert.NoError(t, err)
+	assert.NoError(t, VerifyPh(ed.Public, msg, ctx, sig))
+	assert.NoError(t, VerifyPhHash(ed.Public, h, ctx, sig))
+
+	sig2, err := ed.SignPh(msg, ctx)
+	assert.NoError(t, err)
+	assert.Equal(t, sig, sig2)
+
+	_, err = ed.SignPhHash(sha256.New(), ctx)
+	assert.Error(t, err)
+	_, err = ed.SignCtx(msg, nil)
+	assert.Error(t, err)
+	_, err = ed.SignCtx(msg, make([]byte, 256))
+	assert.Error(t, err)
+}
@@ -0,0 +1,232 @@
+package eddsa
+
+import (
+	"crypto/sha512"
+	"errors"
+	"fmt"
+
+	"go.dedis.ch/kyber/v3"
+)
+
+// Semantics selects the rules deciding which edge-case Ed25519 signatures
+// are valid. Implementations of Ed25519 disagree on signatures with
+// non-canonical encodings, small-order components or that only satisfy the
+// cofactored verification equation, so that protocols requiring all their
+// participants to agree on the validity of a signature, such as consensus
+// protocols, must pin the rules they use. See "Taming the many EdDSAs", by
+// Chalkias, Garillot and Nikolaenko, for a survey.
+//
+// All semantics reject a signature whose S is not smaller than the order
+// of the base point.
+type Semantics int
+
+const (
+	// Strict enforces all the checks of RFC 8032, section 5.1.7, on the
+	// encodings of R and of the public key, rejects small-order R and
+	// public keys, and uses the cofactorless verification equation. A
+	// signature accepted with Strict is valid under every reading of RFC
+	// 8032.
+	Strict Semantics = iota
+	// Cofactored follows RFC 8032 and FIPS 186-5: the encodings of R and
+	// of the public key must be canonical, small-order points are
+	// accepted, and the cofactored verification equation is used.
+	Cofactored
+	// ZIP215 follows the rules of ZIP-215, used in Zcash: non-canonical
+	// encodings of R and of the public key are accepted, and hashed as
+	// given, and the cofactored verification equation is used. These rules
+	// are compatible with batch verification.
+	ZIP215
+)
+
+func (s Semantics) String() string {
+	switch s {
+	case Strict:
+		return "strict"
+	case Cofactored:
+		return "cofactored"
+	case ZIP215:
+		return "ZIP-215"
+	}
+	return fmt.Sprintf("Semantics(%d)", int(s))
+}
+
+// verification rules derived from a Semantics
+type rules struct {
+	canonicalPoints bool // reject non-canonical encodings of R and A
+	canonicalS      bool // reject S >= L
+	smallOrder      bool // reject small-order R and A
+	cofactored      bool // use the cofactored equation
+}
+
+// legacyRules are the rules applied by Verify and BatchVerify.
+var legacyRules = rules{}
+
+func (s Semantics) rules() (rules, error) {
+	switch s {
+	case Strict:
+		return rules{canonicalPoints: true, canonicalS: true, smallOrder: true}, nil
+	case Cofactored:
+		return rules{canonicalPoints: true, canonicalS: true, cofactored: true}, nil
+	case ZIP215:
+		return rules{canonicalS: true, cofactored: true}, nil
+	}
+	return rules{}, errors.New("eddsa: unknown verification semantics")
+}
+
+// VerifyWithSemantics checks that sig is a valid signature of msg under
+// the encoded public key public, according to the given semantics. It
+// returns nil if the signature is valid, or an error otherwise.
+func VerifyWithSemantics(public, msg, sig []byte, sem Semantics) error {
+	r, err := sem.rules()
+	if err != nil {
+		return err
+	}
+	e, err := decodeEntry(r, 0, public, msg, sig)
+	if err != nil {
+		return err
+	}
+	// S*B == R + h*A, or 8*(S*B - R - h*A) == 0 if cofactored
+	S := group.Point().Mul(e.s, nil)
+	RhA := group.Point().Add(e.R, group.Point().Mul(e.h, e.A))
+	if r.cofactored {
+		if !hasSmallOrder(S.Sub(S, RhA)) {
+			return errors.New("eddsa: cofactored verification equation does not hold")
+		}
+		return nil
+	}
+	if !RhA.Equal(S) {
+		return errors.New("reconstructed S is not equal to signature")
+	}
+	return nil
+}
+
+// BatchVerifyWithSemantics is like BatchVerify, but takes encoded public
+// keys and applies the given semantics. As the cofactorless equation of
+// Strict cannot be checked in a batch, the signatures are verified one by
+// one with Strict.
+func BatchVerifyWithSemantics(publics, msgs, sigs [][]byte, sem Semantics) error {
+	if len(publics) != len(msgs) || len(msgs) != len(sigs) {
+		return errors.New("eddsa: mismatched number of public keys, messages and signatures")
+	}
+	r, err := sem.rules()
+	if err != nil {
+		return err
+	}
+	if !r.cofactored {
+		var invalid []int
+		for i := range sigs {
+			if VerifyWithSemantics(publics[i], msgs[i], sigs[i], sem) != nil {
+				invalid = append(invalid, i)
+			}
+		}
+		if len(invalid) > 0 {
+			return &BatchError{Invalid: invalid}
+		}
+		return nil
+	}
+	return batchVerify(r, publics, msgs, sigs)
+}
+
+// decodeEntry decodes a signature and its public key according to the
+// rules r, and computes its challenge.
+func decodeEntry(r rules, index int, public, msg, sig []byte) (*batchEntry, error) {
+	if len(sig) != 64 {
+		return nil, fmt.Errorf("signature length invalid, expect 64 but got %v", len(sig))
+	}
+	if len(public) != 32 {
+		return nil, fmt.Errorf("public key length invalid, expect 32 but got %v", len(public))
+	}
+	if r.canonicalPoints && !isCanonicalPoint(sig[:32]) {
+		return nil, errors.New("eddsa: non-canonical encoding of R")
+	}
+	if r.canonicalPoints && !isCanonicalPoint(public) {
+		return nil, errors.New("eddsa: non-canonical encoding of the public key")
+	}
+	if r.canonicalS && !isCanonicalScalar(sig[32:]) {
+		return nil, errors.New("eddsa: non-canonical S")
+	}
+
+	R := group.Point()
+	if err := R.UnmarshalBinary(sig[:32]); err != nil {
+		return nil, fmt.Errorf("got R invalid point: %s", err)
+	}
+	A := group.Point()
+	if err := A.UnmarshalBinary(public); err != nil {
+		return nil, fmt.Errorf("invalid public key: %s", err)
+	}
+	if r.smallOrder && (hasSmallOrder(R) || hasSmallOrder(A)) {
+		return nil, errors.New("eddsa: small-order R or public key")
+	}
+	s := group.Scalar()
+	if err := s.UnmarshalBinary(sig[32:]); err != nil {
+		return nil, fmt.Errorf("schnorr: s invalid scalar %s", err)
+	}
+
+	// h = H(R || Public || Msg), over the encodings as given
+	hash := sha512.New()
+	_, _ = hash.Write(sig[:32])
+	_, _ = hash.Write(public)
+	_, _ = hash.Write(msg)
+	h := group.Scalar().SetBytes(hash.Sum(nil))
+	return &batchEntry{index: index, R: R, A: A, s: s, h: h}, nil
+}
+
+// primeBytes is the little-endian encoding of p = 2^255 - 19.
+var primeBytes = [32]byte{
+	0xed, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff,
+	0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff,
+	0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff,
+	0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0x7f,
+}
+
+// orderBytes is the little-endian encoding of the order of the base point,
+// L = 2^252 + 27742317777372353535851937790883648493.
+var orderBytes = [32]byte{
+	0xed, 0xd3, 0xf5, 0x5c, 0x1a, 0x63, 0x12, 0x58,
+	0xd6, 0x9c, 0xf7, 0xa2, 0xde, 0xf9, 0xde, 0x14,
+	0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00,
+	0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x10,
+}
+
+// lessThan returns whether the little-endian integer a is smaller than b.
+func lessThan(a []byte, b *[32]byte) bool {
+	for i := 31; i >= 0; i-- {
+		if a[i] != b[i] {
+			return a[i] < b[i]
+		}
+	}
+	return false
+}
+
+// isCanonicalScalar returns whether b encodes an integer smaller than L.
+func isCanonicalScalar(b []byte) bool {
+	return lessThan(b, &orderBytes)
+}
+
+// isCanonicalPoint returns whether b passes the checks of the decoding of
+// RFC 8032, section 5.1.3, that is the coordinate y is smaller than p, and
+// the sign bit is not set if x is zero, which happens if y is 1 or p-1.
+func isCanonicalPoint(b []byte) bool {
+	var y [32]byte
+	copy(y[:], b)
+	y[31] &= 0x7f
+	if !lessThan(y[:], &primeBytes) {
+		return false
+	}
+	if b[31]&0x80 == 0 {
+		return true
+	}
+	var one, minusOne [32]byte
+	one[0] = 1
+	copy(minusOne[:], primeBytes[:])
+	minusOne[0]--
+	return y != one && y != minusOne
+}
+
+// hasSmallOrder returns whether P belongs to the torsion subgroup of order 8.
+func hasSmallOrder(P kyber.Point) bool {
+	Q := group.Point().Add(P, P)
+	Q.Add(Q, Q)
+	Q.Add(Q, Q)
+	return Q.Equal(group.Point().Null())
+}
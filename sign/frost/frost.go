@@ -0,0 +1,339 @@
+// Package frost implements the FROST threshold Schnorr signature protocol
+// of RFC 9591, "The Flexible Round-Optimized Schnorr Threshold (FROST)
+// Protocol for Two-Round Schnorr Signatures".
+//
+// The signers first share a long-term secret with a DKG, for example the
+// share/dkg/pedersen package. Unlike the dss package, no distributed key
+// generation is needed per signature: in a preprocessing round, each signer
+// creates a pair of nonces with Commit and publishes the resulting
+// Commitment, possibly long before the message is known. To sign, a set of
+// at least threshold signers agree on the message and on one commitment of
+// each of them, start a Session, and each returns a signature share with
+// Session.Sign. The shares can be checked with Session.VerifyShare and are
+// combined with Session.Aggregate.
+//
+// The resulting signature is a Schnorr signature R || z of the message
+// under the distributed public key, which can be verified with
+// schnorr.Verify, or with eddsa.Verify on edwards25519. On edwards25519 the
+// protocol is the FROST(Ed25519, SHA-512) ciphersuite of the RFC. On other
+// groups, the same SHA-512 based hash functions are used, with the name of
+// the group in the context string; the challenge is always the one of
+// schnorr.Verify.
+package frost
+
+import (
+	"bytes"
+	"crypto/sha512"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+
+	"go.dedis.ch/kyber/v3"
+	"go.dedis.ch/kyber/v3/share"
+)
+
+// Suite represents the set of functionalities needed by the package frost.
+type Suite interface {
+	kyber.Group
+	kyber.Random
+}
+
+// DistKeyShare is an abstraction to allow one to use distributed key share
+// from different schemes easily into this threshold signature protocol.
+type DistKeyShare interface {
+	PriShare() *share.PriShare
+	Commitments() []kyber.Point
+}
+
+// contextString returns the context string of the ciphersuite of the
+// group, "FROST-ED25519-SHA512-v1" on edwards25519.
+func contextString(g kyber.Group) string {
+	return "FROST-" + strings.ToUpper(g.String()) + "-SHA512-v1"
+}
+
+// hash returns the SHA-512 digest of the context string, the tag and the
+// data, as the functions H1, H3, H4 and H5 of the RFC.
+func hash(g kyber.Group, tag string, data ...[]byte) []byte {
+	h := sha512.New()
+	_, _ = h.Write([]byte(contextString(g)))
+	_, _ = h.Write([]byte(tag))
+	for _, d := range data {
+		_, _ = h.Write(d)
+	}
+	return h.Sum(nil)
+}
+
+func hashToScalar(g kyber.Group, tag string, data ...[]byte) kyber.Scalar {
+	return g.Scalar().SetBytes(hash(g, tag, data...))
+}
+
+// identifier returns the identifier of the participant holding the share of
+// index i, that is the point at which the secret polynomial is evaluated.
+func identifier(g kyber.Group, i int) kyber.Scalar {
+	return g.Scalar().SetInt64(int64(i) + 1)
+}
+
+// Commitment is the public commitment of a signer to a pair of nonces. It
+// must be sent to the other signers, or to the coordinator of the
+// signature, before signing.
+type Commitment struct {
+	// Index is the index of the share of the signer.
+	Index   int
+	Hiding  kyber.Point
+	Binding kyber.Point
+}
+
+// Nonce holds the secret nonces of a signer. It can be used for a single
+// signature: reusing it would reveal the share of the signer, so Sign
+// refuses to use it twice.
+type Nonce struct {
+	hiding, binding kyber.Scalar
+	commitment      *Commitment
+	used            bool
+}
+
+// Commit creates a pair of nonces for the signer holding the given share.
+// Each nonce is derived from 32 bytes of s.RandomStream() and the share, as
+// specified by the RFC, so that a weak random stream alone does not make
+// nonces repeat.
+func Commit(s Suite, private *share.PriShare) (*Nonce, error) {
+	hiding := make([]byte, 32)
+	binding := make([]byte, 32)
+	rand := s.RandomStream()
+	rand.XORKeyStream(hiding, hiding)
+	rand.XORKeyStream(binding, binding)
+	return commit(s, private, hiding, binding)
+}
+
+// commit derives the nonces from the given random bytes.
+func commit(g kyber.Group, private *share.PriShare, hiding, binding []byte) (*Nonce, error) {
+	secret, err := private.V.MarshalBinary()
+	if err != nil {
+		return nil, err
+	}
+	n := &Nonce{
+		hiding:  hashToScalar(g, "nonce", hiding, secret),
+		binding: hashToScalar(g, "nonce", binding, secret),
+	}
+	n.commitment = &Commitment{
+		Index:   private.I,
+		Hiding:  g.Point().Mul(n.hiding, nil),
+		Binding: g.Point().Mul(n.binding, nil),
+	}
+	return n, nil
+}
+
+// Commitment returns the public commitment to the nonces.
+func (n *Nonce) Commitment() *Commitment {
+	return n.commitment
+}
+
+// Session holds the state shared by the signers, and the coordinator, to
+// sign a message.
+type Session struct {
+	group       kyber.Group
+	poly        *share.PubPoly
+	public      []byte // encoding of the distributed public key
+	msg         []byte
+	commitments []*Commitment
+	rho         map[int]kyber.Scalar // binding factors
+	lambda      map[int]kyber.Scalar // Lagrange coefficients
+	c           kyber.Scalar         // Schnorr challenge
+	R           kyber.Point          // group commitment
+}
+
+// NewSession starts the signature of msg by the participants whose
+// commitments are given, under the distributed key whose public polynomial
+// has the given commitments, as returned by DistKeyShare.Commitments. The
+// commitments are sorted by index; there must be at least as many as the
+// threshold of the distributed key, and at most one per participant.
+func NewSession(g kyber.Group, commits []kyber.Point, commitments []*Commitment, msg []byte) (*Session, error) {
+	if len(commitments) == 0 {
+		return nil, errors.New("frost: no commitments")
+	}
+	if len(commitments) < len(commits) {
+		return nil, fmt.Errorf("frost: %d commitments for a threshold of %d",
+			len(commitments), len(commits))
+	}
+	sorted := make([]*Commitment, len(commitments))
+	copy(sorted, commitments)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i].Index < sorted[j].Index })
+	null := g.Point().Null()
+	for i, c := range sorted {
+		if c.Index < 0 {
+			return nil, fmt.Errorf("frost: invalid index %d", c.Index)
+		}
+		if i > 0 && sorted[i-1].Index == c.Index {
+			return nil, fmt.Errorf("frost: several commitments of participant %d", c.Index)
+		}
+		if c.Hiding == nil || c.Binding == nil || c.Hiding.Equal(null) || c.Binding.Equal(null) {
+			return nil, fmt.Errorf("frost: invalid commitment of participant %d", c.Index)
+		}
+	}
+
+	poly := share.NewPubPoly(g, nil, commits)
+	public, err := poly.Commit().MarshalBinary()
+	if err != nil {
+		return nil, err
+	}
+	s := &Session{
+		group:       g,
+		poly:        poly,
+		public:      public,
+		msg:         msg,
+		commitments: sorted,
+		rho:         make(map[int]kyber.Scalar, len(sorted)),
+		lambda:      make(map[int]kyber.Scalar, len(sorted)),
+	}
+
+	// binding factors
+	var encoded bytes.Buffer
+	for _, c := range sorted {
+		if _, err := identifier(g, c.Index).MarshalTo(&encoded); err != nil {
+			return nil, err
+		}
+		if _, err := c.Hiding.MarshalTo(&encoded); err != nil {
+			return nil, err
+		}
+		if _, err := c.Binding.MarshalTo(&encoded); err != nil {
+			return nil, err
+		}
+	}
+	prefix := append(append(append([]byte{}, public...),
+		hash(g, "msg", msg)...), hash(g, "com", encoded.Bytes())...)
+	for _, c := range sorted {
+		id, err := identifier(g, c.Index).MarshalBinary()
+		if err != nil {
+			return nil, err
+		}
+		s.rho[c.Index] = hashToScalar(g, "rho", prefix, id)
+	}
+
+	// group commitment R = sum(D_i + rho_i*E_i)
+	s.R = g.Point().Null()
+	for _, c := range sorted {
+		s.R.Add(s.R, c.Hiding)
+		s.R.Add(s.R, g.Point().Mul(s.rho[c.Index], c.Binding))
+	}
+
+	// the challenge of schnorr.Verify, hash(R || public || msg)
+	h := sha512.New()
+	if _, err := s.R.MarshalTo(h); err != nil {
+		return nil, err
+	}
+	_, _ = h.Write(public)
+	_, _ = h.Write(msg)
+	s.c = g.Scalar().SetBytes(h.Sum(nil))
+
+	// Lagrange coefficients at 0 of the participants
+	for _, ci := range sorted {
+		xi := identifier(g, ci.Index)
+		num := g.Scalar().One()
+		den := g.Scalar().One()
+		for _, cj := range sorted {
+			if cj.Index == ci.Index {
+				continue
+			}
+			xj := identifier(g, cj.Index)
+			num.Mul(num, xj)
+			den.Mul(den, g.Scalar().Sub(xj, xi))
+		}
+		s.lambda[ci.Index] = num.Div(num, den)
+	}
+	return s, nil
+}
+
+// commitment returns the commitment of the participant with index i in the
+// session, or nil.
+func (s *Session) commitment(i int) *Commitment {
+	for _, c := range s.commitments {
+		if c.Index == i {
+			return c
+		}
+	}
+	return nil
+}
+
+// Sign returns the signature share of the participant holding the given
+// share of the distributed key, using its nonce, whose commitment must be
+// part of the session. The nonce cannot be used again afterwards.
+func (s *Session) Sign(private *share.PriShare, nonce *Nonce) (*share.PriShare, error) {
+	g := s.group
+	if nonce.used {
+		return nil, errors.New("frost: nonce already used")
+	}
+	c := s.commitment(private.I)
+	if c == nil {
+		return nil, fmt.Errorf("frost: participant %d is not part of the session", private.I)
+	}
+	if c.Index != nonce.commitment.Index || !c.Hiding.Equal(nonce.commitment.Hiding) ||
+		!c.Binding.Equal(nonce.commitment.Binding) {
+		return nil, errors.New("frost: nonce does not match the commitment of the session")
+	}
+	if !g.Point().Mul(private.V, nil).Equal(s.poly.Eval(private.I).V) {
+		return nil, errors.New("frost: share does not match the distributed key")
+	}
+	nonce.used = true
+
+	// z_i = d_i + e_i*rho_i + lambda_i*s_i*c
+	z := g.Scalar().Mul(nonce.binding, s.rho[private.I])
+	z.Add(z, nonce.hiding)
+	lsc := g.Scalar().Mul(s.lambda[private.I], private.V)
+	lsc.Mul(lsc, s.c)
+	z.Add(z, lsc)
+
+	nonce.hiding.Zero()
+	nonce.binding.Zero()
+	return &share.PriShare{I: private.I, V: z}, nil
+}
+
+// VerifyShare checks a signature share against the commitment and the
+// public share of its signer. It returns nil if the share is valid.
+func (s *Session) VerifyShare(sig *share.PriShare) error {
+	g := s.group
+	c := s.commitment(sig.I)
+	if c == nil {
+		return fmt.Errorf("frost: participant %d is not part of the session", sig.I)
+	}
+	// z_i*B == D_i + rho_i*E_i + c*lambda_i*Y_i
+	left := g.Point().Mul(sig.V, nil)
+	right := g.Point().Add(c.Hiding, g.Point().Mul(s.rho[sig.I], c.Binding))
+	cl := g.Scalar().Mul(s.c, s.lambda[sig.I])
+	right.Add(right, g.Point().Mul(cl, s.poly.Eval(sig.I).V))
+	if !left.Equal(right) {
+		return fmt.Errorf("frost: invalid signature share of participant %d", sig.I)
+	}
+	return nil
+}
+
+// Aggregate combines the signature shares of all the participants of the
+// session into a Schnorr signature R || z of the message under the
+// distributed key. Every share is checked with VerifyShare, so that an
+// invalid share is attributed to its signer.
+func (s *Session) Aggregate(sigs []*share.PriShare) ([]byte, error) {
+	if len(sigs) != len(s.commitments) {
+		return nil, fmt.Errorf("frost: %d signature shares for %d participants",
+			len(sigs), len(s.commitments))
+	}
+	seen := make(map[int]bool, len(sigs))
+	z := s.group.Scalar().Zero()
+	for _, sig := range sigs {
+		if seen[sig.I] {
+			return nil, fmt.Errorf("frost: several signature shares of participant %d", sig.I)
+		}
+		seen[sig.I] = true
+		if err := s.VerifyShare(sig); err != nil {
+			return nil, err
+		}
+		z.Add(z, sig.V)
+	}
+	var b bytes.Buffer
+	if _, err := s.R.MarshalTo(&b); err != nil {
+		return nil, err
+	}
+	if _, err := z.MarshalTo(&b); err != nil {
+		return nil, err
+	}
+	return b.Bytes(), nil
+}
@@ -0,0 +1,241 @@
+package frost
+
+import (
+	"encoding/hex"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"go.dedis.ch/kyber/v3"
+	"go.dedis.ch/kyber/v3/group/edwards25519"
+	"go.dedis.ch/kyber/v3/group/nist"
+	"go.dedis.ch/kyber/v3/pairing/bn256"
+	"go.dedis.ch/kyber/v3/share"
+	dkg "go.dedis.ch/kyber/v3/share/dkg/pedersen"
+	"go.dedis.ch/kyber/v3/sign/eddsa"
+	"go.dedis.ch/kyber/v3/sign/schnorr"
+	"go.dedis.ch/kyber/v3/util/key"
+)
+
+func unhex(t *testing.T, s string) []byte {
+	b, err := hex.DecodeString(s)
+	require.NoError(t, err)
+	return b
+}
+
+func scalar(t *testing.T, g kyber.Group, s string) kyber.Scalar {
+	x := g.Scalar()
+	require.NoError(t, x.UnmarshalBinary(unhex(t, s)))
+	return x
+}
+
+func point(t *testing.T, g kyber.Group, s string) kyber.Point {
+	p := g.Point()
+	require.NoError(t, p.UnmarshalBinary(unhex(t, s)))
+	return p
+}
+
+// From RFC 9591, appendix E.1, FROST(Ed25519, SHA-512).
+func TestFROSTVector(t *testing.T) {
+	g := edwards25519.NewBlakeSHA256Ed25519()
+	secret := scalar(t, g, "7b1c33d3f5291d85de664833beb1ad469f7fb6025a0ec78b3a790c6e13a98304")
+	a1 := scalar(t, g, "178199860edd8c62f5212ee91eff1295d0d670ab4ed4506866bae57e7030b204")
+	commits := []kyber.Point{g.Point().Mul(secret, nil), g.Point().Mul(a1, nil)}
+	require.True(t, commits[0].Equal(
+		point(t, g, "15d21ccd7ee42959562fc8aa63224c8851fb3ec85a3faf66040d380fb9738673")))
+	shares := []*share.PriShare{
+		{I: 0, V: scalar(t, g, "929dcc590407aae7d388761cddb0c0db6f5627aea8e217f4a033f2ec83d93509")},
+		{I: 2, V: scalar(t, g, "d3cb090a075eb154e82fdb4b3cb507f110040905468bb9c46da8bdea643a9a02")},
+	}
+	msg := unhex(t, "74657374")
+
+	n1, err := commit(g, shares[0],
+		unhex(t, "0fd2e39e111cdc266f6c0f4d0fd45c947761f1f5d3cb583dfcb9bbaf8d4c9fec"),
+		unhex(t, "69cd85f631d5f7f2721ed5e40519b1366f340a87c2f6856363dbdcda348a7501"))
+	require.NoError(t, err)
+	require.True(t, n1.Commitment().Hiding.Equal(
+		point(t, g, "b5aa8ab305882a6fc69cbee9327e5a45e54c08af61ae77cb8207be3d2ce13de3")))
+	require.True(t, n1.Commitment().Binding.Equal(
+		point(t, g, "67e98ab55aa310c3120418e5050c9cf76cf387cb20ac9e4b6fdb6f82a469f932")))
+	n3, err := commit(g, shares[1],
+		unhex(t, "86d64a260059e495d0fb4fcc17ea3da7452391baa494d4b00321098ed2a0062f"),
+		unhex(t, "13e6b25afb2eba51716a9a7d44130c0dbae0004a9ef8d7b5550c8a0e07c61775"))
+	require.NoError(t, err)
+
+	s, err := NewSession(g, commits, []*Commitment{n3.Commitment(), n1.Commitment()}, msg)
+	require.NoError(t, err)
+	require.Equal(t, "f2cb9d7dd9beff688da6fcc83fa89046b3479417f47f55600b106760eb3b5603",
+		s.rho[0].String())
+	require.Equal(t, "b087686bf35a13f3dc78e780a34b0fe8a77fef1b9938c563f5573d71d8d7890f",
+		s.rho[2].String())
+
+	z1, err := s.Sign(shares[0], n1)
+	require.NoError(t, err)
+	require.Equal(t, "001719ab5a53ee1a12095cd088fd149702c0720ce5fd2f29dbecf24b7281b603",
+		z1.V.String())
+	z3, err := s.Sign(shares[1], n3)
+	require.NoError(t, err)
+	require.Equal(t, "bd86125de990acc5e1f13781d8e32c03a9bbd4c53539bbc106058bfd14326007",
+		z3.V.String())
+
+	sig, err := s.Aggregate([]*share.PriShare{z1, z3})
+	require.NoError(t, err)
+	require.Equal(t, "36282629c383bb820a88b71cae937d41f2f2adfcc3d02e55507e2fb9e2dd3cbe"+
+		"bd9d2b0844e49ae0f3fa935161e1419aab7b47d21a37ebeae1f17d4987b3160b",
+		hex.EncodeToString(sig))
+	require.NoError(t, eddsa.Verify(commits[0], msg, sig))
+}
+
+// distKeyShares runs a pedersen DKG between n participants with threshold
+// t.
+func distKeyShares(tt *testing.T, suite dkg.Suite, n, t int) []*dkg.DistKeyShare {
+	privates := make([]kyber.Scalar, n)
+	publics := make([]kyber.Point, n)
+	for i := range privates {
+		kp := key.NewKeyPair(suite)
+		privates[i], publics[i] = kp.Private, kp.Public
+	}
+	dkgs := make([]*dkg.DistKeyGenerator, n)
+	for i := range dkgs {
+		var err error
+		dkgs[i], err = dkg.NewDistKeyGenerator(suite, privates[i], publics, t)
+		require.NoError(tt, err)
+	}
+	var resps []*dkg.Response
+	for _, d := range dkgs {
+		deals, err := d.Deals()
+		require.NoError(tt, err)
+		for i, deal := range deals {
+			resp, err := dkgs[i].ProcessDeal(deal)
+			require.NoError(tt, err)
+			resps = append(resps, resp)
+		}
+	}
+	for _, resp := range resps {
+		for i, d := range dkgs {
+			if resp.Response.Index == uint32(i) {
+				continue
+			}
+			_, err := d.ProcessResponse(resp)
+			require.NoError(tt, err)
+		}
+	}
+	shares := make([]*dkg.DistKeyShare, n)
+	for i, d := range dkgs {
+		require.True(tt, d.Certified())
+		var err error
+		shares[i], err = d.DistKeyShare()
+		require.NoError(tt, err)
+	}
+	return shares
+}
+
+// sign runs the protocol between the participants holding the given shares
+// of the distributed key with public polynomial commits.
+func sign(t *testing.T, suite Suite, commits []kyber.Point, shares []*share.PriShare, msg []byte) []byte {
+	nonces := make([]*Nonce, len(shares))
+	commitments := make([]*Commitment, len(shares))
+	for i, sh := range shares {
+		var err error
+		nonces[i], err = Commit(suite, sh)
+		require.NoError(t, err)
+		commitments[i] = nonces[i].Commitment()
+	}
+	s, err := NewSession(suite, commits, commitments, msg)
+	require.NoError(t, err)
+	sigs := make([]*share.PriShare, len(shares))
+	for i, sh := range shares {
+		sigs[i], err = s.Sign(sh, nonces[i])
+		require.NoError(t, err)
+	}
+	sig, err := s.Aggregate(sigs)
+	require.NoError(t, err)
+	return sig
+}
+
+func TestFROSTDKG(t *testing.T) {
+	suite := edwards25519.NewBlakeSHA256Ed25519()
+	dks := distKeyShares(t, suite, 5, 3)
+	public := dks[0].Public()
+	msg := []byte("hello frost")
+
+	// any subset of at least 3 participants can sign
+	for _, set := range [][]int{{0, 1, 2}, {4, 2, 0}, {1, 2, 3, 4}, {0, 1, 2, 3, 4}} {
+		shares := make([]*share.PriShare, len(set))
+		for i, j := range set {
+			shares[i] = dks[j].PriShare()
+		}
+		sig := sign(t, suite, dks[0].Commitments(), shares, msg)
+		require.NoError(t, eddsa.Verify(public, msg, sig))
+		require.NoError(t, schnorr.Verify(suite, public, msg, sig))
+		require.Error(t, eddsa.Verify(public, []byte("other"), sig))
+	}
+}
+
+func TestFROSTGroups(t *testing.T) {
+	for _, suite := range []Suite{
+		nist.NewBlakeSHA256P256(),
+		bn256.NewSuiteG1(),
+	} {
+		secret := suite.Scalar().Pick(suite.RandomStream())
+		poly := share.NewPriPoly(suite, 3, secret, suite.RandomStream())
+		_, commits := poly.Commit(nil).Info()
+		shares := poly.Shares(5)
+		msg := []byte("hello frost")
+		sig := sign(t, suite, commits, shares[1:4], msg)
+		public := suite.Point().Mul(secret, nil)
+		require.NoError(t, schnorr.Verify(suite, public, msg, sig), suite.String())
+	}
+}
+
+func TestFROSTErrors(t *testing.T) {
+	suite := edwards25519.NewBlakeSHA256Ed25519()
+	secret := suite.Scalar().Pick(suite.RandomStream())
+	poly := share.NewPriPoly(suite, 2, secret, suite.RandomStream())
+	_, commits := poly.Commit(nil).Info()
+	shares := poly.Shares(3)
+	msg := []byte("hello frost")
+
+	n0, err := Commit(suite, shares[0])
+	require.NoError(t, err)
+	n1, err := Commit(suite, shares[1])
+	require.NoError(t, err)
+	n2, err := Commit(suite, shares[2])
+	require.NoError(t, err)
+
+	// too few participants, or duplicated ones
+	_, err = NewSession(suite, commits, []*Commitment{n0.Commitment()}, msg)
+	require.Error(t, err)
+	_, err = NewSession(suite, commits, []*Commitment{n0.Commitment(), n0.Commitment()}, msg)
+	require.Error(t, err)
+
+	s, err := NewSession(suite, commits, []*Commitment{n0.Commitment(), n1.Commitment()}, msg)
+	require.NoError(t, err)
+	// not part of the session
+	_, err = s.Sign(shares[2], n2)
+	require.Error(t, err)
+	// nonce of another participant
+	_, err = s.Sign(shares[0], n1)
+	require.Error(t, err)
+	// share not matching the distributed key
+	_, err = s.Sign(&share.PriShare{I: 0, V: suite.Scalar().One()}, n0)
+	require.Error(t, err)
+
+	z0, err := s.Sign(shares[0], n0)
+	require.NoError(t, err)
+	_, err = s.Sign(shares[0], n0)
+	require.Error(t, err, "nonce reused")
+	z1, err := s.Sign(shares[1], n1)
+	require.NoError(t, err)
+
+	// an invalid share is attributed to its signer
+	bad := &share.PriShare{I: 1, V: suite.Scalar().Add(z1.V, suite.Scalar().One())}
+	require.Error(t, s.VerifyShare(bad))
+	_, err = s.Aggregate([]*share.PriShare{z0, bad})
+	require.EqualError(t, err, "frost: invalid signature share of participant 1")
+	_, err = s.Aggregate([]*share.PriShare{z0, z0})
+	require.Error(t, err)
+
+	sig, err := s.Aggregate([]*share.PriShare{z0, z1})
+	require.NoError(t, err)
+	require.NoError(t, eddsa.Verify(suite.Point().Mul(secret, nil), msg, sig))
+}
@@ -0,0 +1,24 @@
+// Package torsion provides a point of small order of edwards25519, to test
+// that the signatures of the packages under sign reject the points with a
+// small-order component.
+package torsion
+
+import (
+	"encoding/hex"
+
+	"go.dedis.ch/kyber/v3"
+	"go.dedis.ch/kyber/v3/group/edwards25519"
+)
+
+// order8 is the encoding of a point of order 8 of edwards25519.
+const order8 = "c7176a703d4dd84fba3c0b760d10670f2a2053fa2c39ccc64ec7fd7792ac037a"
+
+// Edwards25519Order8 returns a point of order 8 of edwards25519.
+func Edwards25519Order8() kyber.Point {
+	buf, _ := hex.DecodeString(order8)
+	P := new(edwards25519.Curve).Point()
+	if err := P.UnmarshalBinary(buf); err != nil {
+		panic(err)
+	}
+	return P
+}
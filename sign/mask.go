@@ -75,6 +75,15 @@ func (m *Mask) SetBit(i int, enable bool) error {
 	return nil
 }
 
+// IndexEnabled returns whether the bit at the given index is set.
+func (m *Mask) IndexEnabled(i int) (bool, error) {
+	if i >= len(m.publics) || i < 0 {
+		return false, errors.New("index out of range")
+	}
+
+	return m.mask[i/8]&(byte(1)<<uint(i&7)) != 0, nil
+}
+
 // forEachBitEnabled is a helper to iterate over the bits set to 1 in the mask
 // and to return the result of the callback only if it is positive.
 func (m *Mask) forEachBitEnabled(f func(i, j, n int) int) int {
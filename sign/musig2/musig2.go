@@ -0,0 +1,266 @@
+// Package musig2 implements the MuSig2 two-round multi-signature scheme of
+// Nick, Ruffing and Seurin, "MuSig2: Simple Two-Round Schnorr
+// Multi-Signatures", https://eprint.iacr.org/2020/1261, over any kyber
+// group.
+//
+// The signers first aggregate their public keys with KeyAgg, which weighs
+// each key by a coefficient so that no signer can choose its key as a
+// function of the others' to forge signatures (rogue-key attacks). Each
+// signer pregenerates nonces with NewNonce, before the message is known,
+// and sends the public part to the others. Once the message is known, a
+// single round suffices: every signer creates a Session from the
+// aggregated nonce and returns a partial signature with Session.Sign, which
+// can be checked with Session.VerifyPartial and combined with
+// Session.Aggregate.
+//
+// The resulting signature is a Schnorr signature of the message under the
+// aggregated key, which can be verified with schnorr.Verify, or with
+// eddsa.Verify on edwards25519.
+package musig2
+
+import (
+	"bytes"
+	"crypto/sha512"
+	"encoding/binary"
+	"errors"
+	"fmt"
+
+	"go.dedis.ch/kyber/v3"
+	"go.dedis.ch/kyber/v3/util/random"
+)
+
+// Suite represents the set of functionalities needed by the package
+// musig2.
+type Suite interface {
+	kyber.Group
+	kyber.Random
+}
+
+// hashToScalar hashes the tag and the data with SHA-512, framing each of
+// them with its length, and maps the digest to a scalar.
+func hashToScalar(g kyber.Group, tag string, data ...[]byte) kyber.Scalar {
+	h := sha512.New()
+	var l [8]byte
+	for _, d := range append([][]byte{[]byte(tag)}, data...) {
+		binary.LittleEndian.PutUint64(l[:], uint64(len(d)))
+		_, _ = h.Write(l[:])
+		_, _ = h.Write(d)
+	}
+	return g.Scalar().SetBytes(h.Sum(nil))
+}
+
+// KeyAggContext holds the result of the aggregation of the public keys of
+// the signers.
+type KeyAggContext struct {
+	group   kyber.Group
+	publics []kyber.Point
+	coeffs  []kyber.Scalar
+	key     kyber.Point
+}
+
+// KeyAgg aggregates the public keys of the signers into the key under which
+// their multi-signatures are verified. Each key is multiplied by the
+// coefficient H(L, X_i), where L commits to the list of all the keys. The
+// order of the keys matters, and every signer must use the same.
+func KeyAgg(g kyber.Group, publics []kyber.Point) (*KeyAggContext, error) {
+	if len(publics) == 0 {
+		return nil, errors.New("musig2: no public keys")
+	}
+	encoded := make([][]byte, len(publics))
+	for i, p := range publics {
+		buf, err := p.MarshalBinary()
+		if err != nil {
+			return nil, err
+		}
+		encoded[i] = buf
+	}
+	L := bytes.Join(encoded, nil)
+
+	ctx := &KeyAggContext{
+		group:   g,
+		publics: publics,
+		coeffs:  make([]kyber.Scalar, len(publics)),
+		key:     g.Point().Null(),
+	}
+	for i, p := range publics {
+		ctx.coeffs[i] = hashToScalar(g, "musig2 keyagg coefficient", L, encoded[i])
+		ctx.key.Add(ctx.key, g.Point().Mul(ctx.coeffs[i], p))
+	}
+	return ctx, nil
+}
+
+// AggregateKey returns the aggregated public key.
+func (k *KeyAggContext) AggregateKey() kyber.Point {
+	return k.key.Clone()
+}
+
+// Coefficient returns the coefficient of the i-th public key.
+func (k *KeyAggContext) Coefficient(i int) kyber.Scalar {
+	return k.coeffs[i].Clone()
+}
+
+// PublicNonce is the public part of the nonces of a signer, which must be
+// sent to the other signers before signing.
+type PublicNonce struct {
+	R1, R2 kyber.Point
+}
+
+// SecretNonce holds the secret nonces of a signer. It can be used for a
+// single signature: reusing it would reveal the private key, so Sign
+// refuses to use it twice.
+type SecretNonce struct {
+	r1, r2 kyber.Scalar
+	public *PublicNonce
+	used   bool
+}
+
+// NewNonce pregenerates a pair of nonces. They are drawn from
+// s.RandomStream(), hedged with the private key of the signer if it is not
+// nil, so that a weak random stream alone does not make nonces repeat.
+func NewNonce(s Suite, private kyber.Scalar) (*SecretNonce, error) {
+	seed := make([]byte, 32)
+	s.RandomStream().XORKeyStream(seed, seed)
+	var x []byte
+	if private != nil {
+		var err error
+		if x, err = private.MarshalBinary(); err != nil {
+			return nil, err
+		}
+	}
+	rand := random.NewHMACDRBGWithHash(sha512.New, seed, x, []byte("kyber musig2 nonce"))
+	r1 := s.Scalar().Pick(rand)
+	r2 := s.Scalar().Pick(rand)
+	return &SecretNonce{
+		r1: r1,
+		r2: r2,
+		public: &PublicNonce{
+			R1: s.Point().Mul(r1, nil),
+			R2: s.Point().Mul(r2, nil),
+		},
+	}, nil
+}
+
+// Public returns the public part of the nonces.
+func (n *SecretNonce) Public() *PublicNonce {
+	return n.public
+}
+
+// AggregateNonces sums the public nonces of all the signers.
+func AggregateNonces(g kyber.Group, nonces []*PublicNonce) (*PublicNonce, error) {
+	if len(nonces) == 0 {
+		return nil, errors.New("musig2: no nonces")
+	}
+	agg := &PublicNonce{R1: g.Point().Null(), R2: g.Point().Null()}
+	for _, n := range nonces {
+		agg.R1.Add(agg.R1, n.R1)
+		agg.R2.Add(agg.R2, n.R2)
+	}
+	return agg, nil
+}
+
+// Session holds the state shared by the signers to sign a message.
+type Session struct {
+	ctx *KeyAggContext
+	msg []byte
+	b   kyber.Scalar // nonce coefficient
+	c   kyber.Scalar // Schnorr challenge
+	R   kyber.Point  // final nonce
+}
+
+// NewSession starts the signature of msg under the aggregated key of ctx,
+// with the aggregation of the public nonces of all the signers.
+func NewSession(ctx *KeyAggContext, aggNonce *PublicNonce, msg []byte) (*Session, error) {
+	g := ctx.group
+	key, err := ctx.key.MarshalBinary()
+	if err != nil {
+		return nil, err
+	}
+	R1, err := aggNonce.R1.MarshalBinary()
+	if err != nil {
+		return nil, err
+	}
+	R2, err := aggNonce.R2.MarshalBinary()
+	if err != nil {
+		return nil, err
+	}
+	b := hashToScalar(g, "musig2 nonce coefficient", key, R1, R2, msg)
+	R := g.Point().Add(aggNonce.R1, g.Point().Mul(b, aggNonce.R2))
+
+	// the challenge of schnorr.Verify, hash(R || public || msg)
+	h := sha512.New()
+	if _, err := R.MarshalTo(h); err != nil {
+		return nil, err
+	}
+	_, _ = h.Write(key)
+	_, _ = h.Write(msg)
+	c := g.Scalar().SetBytes(h.Sum(nil))
+
+	return &Session{ctx: ctx, msg: msg, b: b, c: c, R: R}, nil
+}
+
+// Sign returns the partial signature of the signer whose public key is the
+// i-th given to KeyAgg, using its private key and its secret nonce, which
+// cannot be used again afterwards.
+func (s *Session) Sign(i int, private kyber.Scalar, nonce *SecretNonce) (kyber.Scalar, error) {
+	g := s.ctx.group
+	if i < 0 || i >= len(s.ctx.publics) {
+		return nil, fmt.Errorf("musig2: invalid signer index %d", i)
+	}
+	if nonce.used {
+		return nil, errors.New("musig2: secret nonce already used")
+	}
+	if !g.Point().Mul(private, nil).Equal(s.ctx.publics[i]) {
+		return nil, errors.New("musig2: private key does not match the public key of the signer")
+	}
+	nonce.used = true
+
+	// s_i = r1 + b*r2 + c*a_i*x_i
+	partial := g.Scalar().Mul(s.b, nonce.r2)
+	partial.Add(partial, nonce.r1)
+	cax := g.Scalar().Mul(s.c, s.ctx.coeffs[i])
+	cax.Mul(cax, private)
+	partial.Add(partial, cax)
+
+	nonce.r1.Zero()
+	nonce.r2.Zero()
+	return partial, nil
+}
+
+// VerifyPartial checks the partial signature of the i-th signer, given its
+// public nonce. It returns nil if the partial signature is valid.
+func (s *Session) VerifyPartial(i int, partial kyber.Scalar, nonce *PublicNonce) error {
+	g := s.ctx.group
+	if i < 0 || i >= len(s.ctx.publics) {
+		return fmt.Errorf("musig2: invalid signer index %d", i)
+	}
+	// s_i*B == R1 + b*R2 + c*a_i*X_i
+	left := g.Point().Mul(partial, nil)
+	right := g.Point().Add(nonce.R1, g.Point().Mul(s.b, nonce.R2))
+	ca := g.Scalar().Mul(s.c, s.ctx.coeffs[i])
+	right.Add(right, g.Point().Mul(ca, s.ctx.publics[i]))
+	if !left.Equal(right) {
+		return fmt.Errorf("musig2: invalid partial signature of signer %d", i)
+	}
+	return nil
+}
+
+// Aggregate combines the partial signatures of all the signers into a
+// Schnorr signature R || s of the message under the aggregated key.
+func (s *Session) Aggregate(partials []kyber.Scalar) ([]byte, error) {
+	if len(partials) != len(s.ctx.publics) {
+		return nil, fmt.Errorf("musig2: %d partial signatures for %d signers",
+			len(partials), len(s.ctx.publics))
+	}
+	S := s.ctx.group.Scalar().Zero()
+	for _, p := range partials {
+		S.Add(S, p)
+	}
+	var b bytes.Buffer
+	if _, err := s.R.MarshalTo(&b); err != nil {
+		return nil, err
+	}
+	if _, err := S.MarshalTo(&b); err != nil {
+		return nil, err
+	}
+	return b.Bytes(), nil
+}
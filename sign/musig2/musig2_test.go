@@ -0,0 +1,126 @@
+package musig2
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"go.dedis.ch/kyber/v3"
+	"go.dedis.ch/kyber/v3/group/edwards25519"
+	"go.dedis.ch/kyber/v3/group/nist"
+	"go.dedis.ch/kyber/v3/pairing/bn256"
+	"go.dedis.ch/kyber/v3/sign/eddsa"
+	"go.dedis.ch/kyber/v3/sign/schnorr"
+	"go.dedis.ch/kyber/v3/util/key"
+)
+
+// run executes the protocol between n signers and returns the aggregated
+// key and signature.
+func run(t *testing.T, suite Suite, n int, msg []byte) (kyber.Point, []byte) {
+	privates := make([]kyber.Scalar, n)
+	publics := make([]kyber.Point, n)
+	for i := range privates {
+		kp := key.NewKeyPair(suite)
+		privates[i], publics[i] = kp.Private, kp.Public
+	}
+	ctx, err := KeyAgg(suite, publics)
+	require.NoError(t, err)
+
+	// first round, before the message is known
+	secrets := make([]*SecretNonce, n)
+	nonces := make([]*PublicNonce, n)
+	for i := range secrets {
+		secrets[i], err = NewNonce(suite, privates[i])
+		require.NoError(t, err)
+		nonces[i] = secrets[i].Public()
+	}
+	aggNonce, err := AggregateNonces(suite, nonces)
+	require.NoError(t, err)
+
+	// second round
+	partials := make([]kyber.Scalar, n)
+	for i := range partials {
+		session, err := NewSession(ctx, aggNonce, msg)
+		require.NoError(t, err)
+		partials[i], err = session.Sign(i, privates[i], secrets[i])
+		require.NoError(t, err)
+	}
+
+	session, err := NewSession(ctx, aggNonce, msg)
+	require.NoError(t, err)
+	for i := range partials {
+		require.NoError(t, session.VerifyPartial(i, partials[i], nonces[i]))
+	}
+	wrong := suite.Scalar().Add(partials[0], suite.Scalar().One())
+	require.Error(t, session.VerifyPartial(0, wrong, nonces[0]))
+	if n > 1 {
+		require.Error(t, session.VerifyPartial(1, partials[0], nonces[0]))
+	}
+
+	// the secret nonces cannot be used again
+	_, err = session.Sign(0, privates[0], secrets[0])
+	require.Error(t, err)
+
+	sig, err := session.Aggregate(partials)
+	require.NoError(t, err)
+	_, err = session.Aggregate(partials[1:])
+	require.Error(t, err)
+	return ctx.AggregateKey(), sig
+}
+
+func TestMuSig2(t *testing.T) {
+	msg := []byte("Hello MuSig2")
+	for _, suite := range []Suite{
+		edwards25519.NewBlakeSHA256Ed25519(),
+		nist.NewBlakeSHA256P256(),
+		bn256.NewSuiteG1(),
+	} {
+		for _, n := range []int{1, 2, 5} {
+			public, sig := run(t, suite, n, msg)
+			require.NoError(t, schnorr.Verify(suite, public, msg, sig), "%s, n = %d", suite, n)
+			require.Error(t, schnorr.Verify(suite, public, []byte("other"), sig))
+			if _, ok := suite.(*edwards25519.SuiteEd25519); ok {
+				require.NoError(t, eddsa.Verify(public, msg, sig))
+			}
+		}
+	}
+}
+
+func TestKeyAgg(t *testing.T) {
+	suite := edwards25519.NewBlakeSHA256Ed25519()
+	honest := key.NewKeyPair(suite)
+
+	// A rogue key chosen to cancel the honest one does not let its owner
+	// control the aggregated key.
+	target := key.NewKeyPair(suite)
+	rogue := suite.Point().Sub(target.Public, honest.Public)
+	ctx, err := KeyAgg(suite, []kyber.Point{honest.Public, rogue})
+	require.NoError(t, err)
+	require.False(t, ctx.AggregateKey().Equal(target.Public))
+
+	expected := suite.Point().Mul(ctx.Coefficient(0), honest.Public)
+	expected.Add(expected, suite.Point().Mul(ctx.Coefficient(1), rogue))
+	require.True(t, expected.Equal(ctx.AggregateKey()))
+
+	// The order of the keys matters.
+	swapped, err := KeyAgg(suite, []kyber.Point{rogue, honest.Public})
+	require.NoError(t, err)
+	require.False(t, swapped.AggregateKey().Equal(ctx.AggregateKey()))
+
+	_, err = KeyAgg(suite, nil)
+	require.Error(t, err)
+}
+
+func TestSignWrongKey(t *testing.T) {
+	suite := edwards25519.NewBlakeSHA256Ed25519()
+	kp1, kp2 := key.NewKeyPair(suite), key.NewKeyPair(suite)
+	ctx, err := KeyAgg(suite, []kyber.Point{kp1.Public, kp2.Public})
+	require.NoError(t, err)
+	nonce, err := NewNonce(suite, nil)
+	require.NoError(t, err)
+	session, err := NewSession(ctx, nonce.Public(), []byte("msg"))
+	require.NoError(t, err)
+	_, err = session.Sign(0, kp2.Private, nonce)
+	require.Error(t, err)
+	_, err = session.Sign(2, kp2.Private, nonce)
+	require.Error(t, err)
+}
@@ -0,0 +1,386 @@
+// Package oneofmany implements the one-out-of-many proofs of Groth and
+// Kohlweiss, "One-out-of-Many Proofs: Or How to Leak a Secret and Spend a
+// Coin", https://eprint.iacr.org/2014/764, and ring signatures built on
+// them, whose size grows with the logarithm of the size of the anonymity set.
+//
+// A proof shows the knowledge of the private key of one of the public keys of
+// an anon.Set, without revealing which one. The set is padded to N = 2^m keys
+// by repeating its last key, the index of the signer is written in binary,
+// and the proof commits to its m bits with Pedersen commitments, so that it
+// holds 4m points and 3m+1 scalars. Its verification computes a
+// multi-exponentiation over the N keys, as its generation does m times.
+//
+// A proof can optionally be linkable, as the signatures of the package
+// anon. It then holds the linkage tag x*B of the private key x, where the
+// base point B is derived from a linkage scope, and proves that its discrete
+// logarithm is the private key of the hidden public key, in the fashion of
+// Triptych, https://eprint.iacr.org/2020/018, with m more points. The tags
+// are those of anon.Sign in the same scope, so that the signatures of both
+// packages can be linked together.
+package oneofmany
+
+import (
+	"bytes"
+	"errors"
+
+	"go.dedis.ch/kyber/v3"
+	"go.dedis.ch/kyber/v3/proof/transcript"
+	"go.dedis.ch/kyber/v3/sign/anon"
+)
+
+// generatorSeed seeds the second generator of the Pedersen commitments,
+// whose discrete logarithm is unknown.
+const generatorSeed = "kyber one-out-of-many generator"
+
+// Proof is a one-out-of-many proof over a set of N = 2^m public keys.
+type Proof struct {
+	A  []kyber.Point // commitments to the blinding of each bit
+	B  []kyber.Point // commitments to each bit
+	C  []kyber.Point // commitments to the products of the bits and blindings
+	G  []kyber.Point // commitments to the coefficients over the keys
+	H  []kyber.Point // commitments to the coefficients over the tag, if linkable
+	F  []kyber.Scalar
+	ZA []kyber.Scalar
+	ZC []kyber.Scalar
+	Z  kyber.Scalar
+}
+
+// digits returns the number of bits m of the indices of a padded set of
+// size n, at least 1.
+func digits(n int) int {
+	m := 1
+	for 1<<uint(m) < n {
+		m++
+	}
+	return m
+}
+
+// padded returns the key of index i of the set padded to 2^m keys.
+func padded(set anon.Set, i int) kyber.Point {
+	if i >= len(set) {
+		return set[len(set)-1]
+	}
+	return set[i]
+}
+
+// generator returns the second generator of the Pedersen commitments.
+func generator(suite anon.Suite) kyber.Point {
+	return suite.Point().Pick(suite.XOF([]byte(generatorSeed)))
+}
+
+// commit returns the Pedersen commitment m*H + r*G.
+func commit(suite anon.Suite, H kyber.Point, m, r kyber.Scalar) kyber.Point {
+	C := suite.Point().Mul(m, H)
+	return C.Add(C, suite.Point().Mul(r, nil))
+}
+
+// appendStatement absorbs the set and tag into the transcript.
+func appendStatement(t *transcript.Transcript, set anon.Set, tag kyber.Point) {
+	for _, X := range set {
+		t.AppendPoint("key", X)
+	}
+	if tag != nil {
+		t.AppendPoint("tag", tag)
+	}
+}
+
+// challenge absorbs the commitments of the proof into the
+// transcript and returns the challenge.
+func (p *Proof) challenge(t *transcript.Transcript) kyber.Scalar {
+	for _, points := range [][]kyber.Point{p.A, p.B, p.C, p.G, p.H} {
+		for _, P := range points {
+			t.AppendPoint("commitment", P)
+		}
+	}
+	return t.ChallengeScalar("challenge")
+}
+
+// Prove returns a proof of the knowledge of the private key of set[mine],
+// bound to the transcript. Given a link base, the proof is linkable, and
+// Prove also returns the linkage tag private*linkBase.
+func Prove(suite anon.Suite, t *transcript.Transcript, set anon.Set, linkBase kyber.Point,
+	mine int, private kyber.Scalar) (*Proof, kyber.Point, error) {
+	if len(set) == 0 {
+		return nil, nil, errors.New("empty anonymity set")
+	}
+	if mine < 0 || mine >= len(set) {
+		return nil, nil, errors.New("signer index out of range")
+	}
+	if !suite.Point().Mul(private, nil).Equal(set[mine]) {
+		return nil, nil, errors.New("private key does not match the anonymity set")
+	}
+	m := digits(len(set))
+	N := 1 << uint(m)
+	H := generator(suite)
+	var tag kyber.Point
+	if linkBase != nil {
+		tag = suite.Point().Mul(private, linkBase)
+	}
+	appendStatement(t, set, tag)
+	rand := t.BuildRNG().RekeyWithWitnessScalar("private", private).Finalize(suite.RandomStream())
+	pick := func() kyber.Scalar { return suite.Scalar().Pick(rand) }
+
+	p := &Proof{
+		A:  make([]kyber.Point, m),
+		B:  make([]kyber.Point, m),
+		C:  make([]kyber.Point, m),
+		G:  make([]kyber.Point, m),
+		F:  make([]kyber.Scalar, m),
+		ZA: make([]kyber.Scalar, m),
+		ZC: make([]kyber.Scalar, m),
+	}
+
+	// Commit to the bits l_j of mine, and to the coefficients of the
+	// polynomials f_{j,1}(x) = l_j*x + a_j and f_{j,0}(x) = x - f_{j,1}(x)
+	zero, one := suite.Scalar().Zero(), suite.Scalar().One()
+	bits := make([]kyber.Scalar, m)
+	a := make([]kyber.Scalar, m)
+	r := make([]kyber.Scalar, m)
+	s := make([]kyber.Scalar, m)
+	tt := make([]kyber.Scalar, m)
+	f := make([][2][2]kyber.Scalar, m) // f[j][bit] = {constant, linear} coefficients
+	for j := 0; j < m; j++ {
+		bits[j] = zero
+		if mine>>uint(j)&1 == 1 {
+			bits[j] = one
+		}
+		a[j], r[j], s[j], tt[j] = pick(), pick(), pick(), pick()
+		p.A[j] = commit(suite, H, a[j], s[j])
+		p.B[j] = commit(suite, H, bits[j], r[j])
+		p.C[j] = commit(suite, H, suite.Scalar().Mul(bits[j], a[j]), tt[j])
+		f[j][1] = [2]kyber.Scalar{a[j], bits[j]}
+		f[j][0] = [2]kyber.Scalar{suite.Scalar().Neg(a[j]), suite.Scalar().Sub(one, bits[j])}
+	}
+
+	// p_i(x) = prod_j f_{j,i_j}(x) = delta(i, mine)*x^m + sum_k p_{i,k} x^k
+	polys := [][]kyber.Scalar{{one}}
+	for j := 0; j < m; j++ {
+		next := make([][]kyber.Scalar, 2*len(polys))
+		for i, poly := range polys {
+			for bit := 0; bit < 2; bit++ {
+				next[i+bit*len(polys)] = mulLinear(suite, poly, f[j][bit])
+			}
+		}
+		polys = next
+	}
+
+	rho := make([]kyber.Scalar, m)
+	P := suite.Point()
+	for k := 0; k < m; k++ {
+		rho[k] = pick()
+		p.G[k] = suite.Point().Mul(rho[k], nil)
+		for i := 0; i < N; i++ {
+			p.G[k].Add(p.G[k], P.Mul(polys[i][k], padded(set, i)))
+		}
+		if linkBase != nil {
+			p.H = append(p.H, suite.Point().Mul(rho[k], linkBase))
+		}
+	}
+
+	x := p.challenge(t)
+	for j := 0; j < m; j++ {
+		p.F[j] = suite.Scalar().Mul(bits[j], x)
+		p.F[j].Add(p.F[j], a[j])
+		p.ZA[j] = suite.Scalar().Mul(r[j], x)
+		p.ZA[j].Add(p.ZA[j], s[j])
+		p.ZC[j] = suite.Scalar().Sub(x, p.F[j])
+		p.ZC[j].Mul(p.ZC[j], r[j]).Add(p.ZC[j], tt[j])
+	}
+	// z = private*x^m - sum_k rho_k x^k
+	p.Z = suite.Scalar().Zero()
+	xk := suite.Scalar().One()
+	for k := 0; k < m; k++ {
+		p.Z.Sub(p.Z, suite.Scalar().Mul(rho[k], xk))
+		xk.Mul(xk, x)
+	}
+	p.Z.Add(p.Z, suite.Scalar().Mul(private, xk))
+	return p, tag, nil
+}
+
+// mulLinear returns the product of the polynomial and the linear polynomial
+// l[0] + l[1]*x.
+func mulLinear(suite anon.Suite, poly []kyber.Scalar, l [2]kyber.Scalar) []kyber.Scalar {
+	res := make([]kyber.Scalar, len(poly)+1)
+	for k := range res {
+		res[k] = suite.Scalar().Zero()
+	}
+	for k, c := range poly {
+		res[k].Add(res[k], suite.Scalar().Mul(c, l[0]))
+		res[k+1].Add(res[k+1], suite.Scalar().Mul(c, l[1]))
+	}
+	return res
+}
+
+// Verify checks the proof against the set, bound to the transcript. A
+// linkable proof is checked against its link base and linkage tag, which
+// must both be nil for an unlinkable proof.
+func (p *Proof) Verify(suite anon.Suite, t *transcript.Transcript, set anon.Set,
+	linkBase, tag kyber.Point) error {
+	if len(set) == 0 {
+		return errors.New("empty anonymity set")
+	}
+	if (linkBase == nil) != (tag == nil) {
+		return errors.New("linkable proofs need both a link base and a tag")
+	}
+	if tag != nil && !anon.InPrimeOrderSubgroup(suite, tag) {
+		return errors.New("linkage tag not in the prime-order subgroup")
+	}
+	m := digits(len(set))
+	N := 1 << uint(m)
+	nH := 0
+	if linkBase != nil {
+		nH = m
+	}
+	if len(p.A) != m || len(p.B) != m || len(p.C) != m || len(p.G) != m || len(p.H) != nH ||
+		len(p.F) != m || len(p.ZA) != m || len(p.ZC) != m || p.Z == nil {
+		return errors.New("invalid proof size")
+	}
+	H := generator(suite)
+	appendStatement(t, set, tag)
+	x := p.challenge(t)
+
+	// Check that the B_j commit to bits
+	P := suite.Point()
+	for j := 0; j < m; j++ {
+		left := suite.Point().Mul(x, p.B[j])
+		left.Add(left, p.A[j])
+		if !left.Equal(commit(suite, H, p.F[j], p.ZA[j])) {
+			return errors.New("invalid proof")
+		}
+		left.Mul(suite.Scalar().Sub(x, p.F[j]), p.B[j])
+		left.Add(left, p.C[j])
+		if !left.Equal(P.Mul(p.ZC[j], nil)) {
+			return errors.New("invalid proof")
+		}
+	}
+
+	// coefs[i] = prod_j f_{j,i_j}, with f_{j,1} = f_j and f_{j,0} = x - f_j
+	coefs := []kyber.Scalar{suite.Scalar().One()}
+	for j := 0; j < m; j++ {
+		f1 := p.F[j]
+		f0 := suite.Scalar().Sub(x, f1)
+		next := make([]kyber.Scalar, 2*len(coefs))
+		for i, c := range coefs {
+			next[i] = suite.Scalar().Mul(c, f0)
+			next[i+len(coefs)] = suite.Scalar().Mul(c, f1)
+		}
+		coefs = next
+	}
+
+	// sum_i coefs_i*P_i - sum_k x^k*G_k == z*G
+	left := suite.Point().Null()
+	for i := 0; i < N; i++ {
+		left.Add(left, P.Mul(coefs[i], padded(set, i)))
+	}
+	var leftH kyber.Point
+	xk := suite.Scalar().One()
+	for k := 0; k < m; k++ {
+		left.Sub(left, P.Mul(xk, p.G[k]))
+		xk = suite.Scalar().Mul(xk, x)
+	}
+	if !left.Equal(P.Mul(p.Z, nil)) {
+		return errors.New("invalid proof")
+	}
+
+	// x^m*tag - sum_k x^k*H_k == z*linkBase, as sum_i coefs_i = x^m
+	if linkBase != nil {
+		leftH = suite.Point().Mul(xk, tag)
+		xk = suite.Scalar().One()
+		for k := 0; k < m; k++ {
+			leftH.Sub(leftH, P.Mul(xk, p.H[k]))
+			xk = suite.Scalar().Mul(xk, x)
+		}
+		if !leftH.Equal(P.Mul(p.Z, linkBase)) {
+			return errors.New("invalid proof")
+		}
+	}
+	return nil
+}
+
+// signatureTranscript returns the transcript of a signature of message.
+func signatureTranscript(suite anon.Suite, message, linkScope []byte) *transcript.Transcript {
+	t := transcript.New(suite, "kyber one-out-of-many signature")
+	t.AppendMessage("message", message)
+	if linkScope != nil {
+		t.AppendMessage("scope", linkScope)
+	}
+	return t
+}
+
+// linkBase returns the base point of the linkage tags of the scope, which is
+// the one of anon.Sign.
+func linkBase(suite anon.Suite, linkScope []byte) kyber.Point {
+	if linkScope == nil {
+		return nil
+	}
+	return suite.Point().Pick(suite.XOF(linkScope))
+}
+
+// Sign creates an anonymous, optionally linkable, signature on a given
+// message, with the semantics of anon.Sign: it proves that the owner of one
+// of the public keys of the anonymity set signed the message. Given a
+// non-nil linkScope, the signature is linkable, and holds the linkage tag of
+// anon.Sign in the same scope.
+func Sign(suite anon.Suite, message []byte, anonymitySet anon.Set, linkScope []byte,
+	mine int, privateKey kyber.Scalar) ([]byte, error) {
+	t := signatureTranscript(suite, message, linkScope)
+	p, tag, err := Prove(suite, t, anonymitySet, linkBase(suite, linkScope), mine, privateKey)
+	if err != nil {
+		return nil, err
+	}
+	var buf bytes.Buffer
+	if tag != nil {
+		if err := suite.Write(&buf, tag); err != nil {
+			return nil, err
+		}
+	}
+	if err := suite.Write(&buf, p); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// Verify checks a signature generated by Sign. As anon.Verify, it returns the
+// linkage tag of the signer within linkScope if the signature is a valid
+// linkable signature, or an empty but non-nil byte-slice if the signature is
+// a valid unlinkable signature.
+func Verify(suite anon.Suite, message []byte, anonymitySet anon.Set, linkScope []byte,
+	signatureBuffer []byte) ([]byte, error) {
+	if len(anonymitySet) == 0 {
+		return nil, errors.New("empty anonymity set")
+	}
+	m := digits(len(anonymitySet))
+	p := &Proof{
+		A:  make([]kyber.Point, m),
+		B:  make([]kyber.Point, m),
+		C:  make([]kyber.Point, m),
+		G:  make([]kyber.Point, m),
+		F:  make([]kyber.Scalar, m),
+		ZA: make([]kyber.Scalar, m),
+		ZC: make([]kyber.Scalar, m),
+		H:  make([]kyber.Point, 0, m),
+	}
+	buf := bytes.NewBuffer(signatureBuffer)
+	var tag kyber.Point
+	if linkScope != nil {
+		p.H = p.H[:m]
+		tag = suite.Point()
+		if err := suite.Read(buf, tag); err != nil {
+			return nil, err
+		}
+	}
+	if err := suite.Read(buf, p); err != nil {
+		return nil, err
+	}
+	if buf.Len() != 0 {
+		return nil, errors.New("invalid signature length")
+	}
+	t := signatureTranscript(suite, message, linkScope)
+	if err := p.Verify(suite, t, anonymitySet, linkBase(suite, linkScope), tag); err != nil {
+		return nil, err
+	}
+	if tag != nil {
+		return tag.MarshalBinary()
+	}
+	return []byte{}, nil
+}
@@ -0,0 +1,146 @@
+package oneofmany
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"go.dedis.ch/kyber/v3"
+	"go.dedis.ch/kyber/v3/group/edwards25519"
+	"go.dedis.ch/kyber/v3/proof/transcript"
+	"go.dedis.ch/kyber/v3/sign/anon"
+	"go.dedis.ch/kyber/v3/sign/internal/torsion"
+	"go.dedis.ch/kyber/v3/util/random"
+)
+
+var suite = edwards25519.NewBlakeSHA256Ed25519()
+
+// ring returns a set of n keys, with the private key of member mine.
+func ring(n, mine int) (anon.Set, kyber.Scalar) {
+	set := make(anon.Set, n)
+	for i := range set {
+		set[i] = suite.Point().Pick(random.New())
+	}
+	x := suite.Scalar().Pick(random.New())
+	set[mine] = suite.Point().Mul(x, nil)
+	return set, x
+}
+
+func TestSignVerify(t *testing.T) {
+	M := []byte("Hello World!")
+	for _, n := range []int{1, 2, 3, 8, 13} {
+		for _, mine := range []int{0, n - 1, n / 2} {
+			set, x := ring(n, mine)
+			for _, scope := range [][]byte{nil, []byte("scope")} {
+				sig, err := Sign(suite, M, set, scope, mine, x)
+				require.NoError(t, err)
+				tag, err := Verify(suite, M, set, scope, sig)
+				require.NoError(t, err)
+				require.NotNil(t, tag)
+
+				_, err = Verify(suite, []byte("other"), set, scope, sig)
+				require.Error(t, err)
+				_, err = Verify(suite, M, set, []byte("other scope"), sig)
+				require.Error(t, err)
+				_, err = Verify(suite, M, set, scope, append(sig, 0))
+				require.Error(t, err)
+				_, err = Verify(suite, M, set, scope, sig[:len(sig)-1])
+				require.Error(t, err)
+				other, _ := ring(n, 0)
+				_, err = Verify(suite, M, other, scope, sig)
+				require.Error(t, err)
+			}
+		}
+	}
+}
+
+func TestSignErrors(t *testing.T) {
+	M := []byte("Hello World!")
+	set, x := ring(5, 2)
+	_, err := Sign(suite, M, set, nil, 1, x)
+	require.Error(t, err)
+	_, err = Sign(suite, M, set, nil, 5, x)
+	require.Error(t, err)
+	_, err = Sign(suite, M, nil, nil, 0, x)
+	require.Error(t, err)
+}
+
+func TestLinkage(t *testing.T) {
+	M := []byte("Hello World!")
+	scope := []byte("scope")
+	set, x := ring(6, 4)
+	sig, err := Sign(suite, M, set, scope, 4, x)
+	require.NoError(t, err)
+	tag, err := Verify(suite, M, set, scope, sig)
+	require.NoError(t, err)
+
+	// same tag as anon.Sign in the same scope, even in another set
+	sig, err = Sign(suite, []byte("other"), set[2:], scope, 2, x)
+	require.NoError(t, err)
+	tag2, err := Verify(suite, []byte("other"), set[2:], scope, sig)
+	require.NoError(t, err)
+	require.Equal(t, tag, tag2)
+	liu := anon.Sign(suite, M, set, scope, 4, x)
+	liuTag, err := anon.Verify(suite, M, set, scope, liu)
+	require.NoError(t, err)
+	require.Equal(t, liuTag, tag)
+
+	// a proof with the tag of another key does not verify
+	y := suite.Scalar().Pick(random.New())
+	tr := transcript.New(suite, "test")
+	base := linkBase(suite, scope)
+	p, _, err := Prove(suite, tr, set, base, 4, x)
+	require.NoError(t, err)
+	require.NoError(t, p.Verify(suite, transcript.New(suite, "test"), set, base, suite.Point().Mul(x, base)))
+	require.Error(t, p.Verify(suite, transcript.New(suite, "test"), set, base, suite.Point().Mul(y, base)))
+	require.Error(t, p.Verify(suite, transcript.New(suite, "other"), set, base, suite.Point().Mul(x, base)))
+	require.Error(t, p.Verify(suite, transcript.New(suite, "test"), set, nil, nil))
+
+	// a tag with a component of order 8 is rejected
+	T8 := torsion.Edwards25519Order8()
+	tagged := suite.Point().Add(suite.Point().Mul(x, base), T8)
+	err = p.Verify(suite, transcript.New(suite, "test"), set, base, tagged)
+	require.EqualError(t, err, "linkage tag not in the prime-order subgroup")
+	sig, err = Sign(suite, M, set, scope, 4, x)
+	require.NoError(t, err)
+	buf, err := tagged.MarshalBinary()
+	require.NoError(t, err)
+	_, err = Verify(suite, M, set, scope, append(buf, sig[len(buf):]...))
+	require.EqualError(t, err, "linkage tag not in the prime-order subgroup")
+}
+
+func TestSignatureSize(t *testing.T) {
+	M := []byte("Hello World!")
+	// 4m points and 3m+1 scalars, with m = log2(n)
+	for m := 1; m <= 8; m++ {
+		n := 1 << uint(m)
+		set, x := ring(n, n-1)
+		sig, err := Sign(suite, M, set, nil, n-1, x)
+		require.NoError(t, err)
+		require.Len(t, sig, 4*m*suite.PointLen()+(3*m+1)*suite.ScalarLen())
+	}
+}
+
+func BenchmarkSign(b *testing.B) {
+	for _, n := range []int{16, 256, 1024} {
+		set, x := ring(n, 0)
+		scope := []byte("bench scope")
+		b.Run(fmt.Sprintf("Sign/%d", n), func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				if _, err := Sign(suite, benchMessage, set, scope, 0, x); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+		sig, _ := Sign(suite, benchMessage, set, scope, 0, x)
+		b.Run(fmt.Sprintf("Verify/%d", n), func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				if _, err := Verify(suite, benchMessage, set, scope, sig); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	}
+}
+
+var benchMessage = []byte("Hello World!")
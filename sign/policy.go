@@ -1,5 +1,13 @@
 package sign
 
+import (
+	"errors"
+	"fmt"
+	"math"
+
+	"go.dedis.ch/protobuf"
+)
+
 // ParticipationMask is an interface to get the total number of candidates
 // and the number of participants.
 type ParticipationMask interface {
@@ -48,3 +56,306 @@ func NewThresholdPolicy(thold int) *ThresholdPolicy {
 func (p ThresholdPolicy) Check(m ParticipationMask) bool {
 	return m.CountEnabled() >= p.thold
 }
+
+// IndexedMask is a ParticipationMask which also tells which candidates, by
+// index in the roster, are participants. Mask and cosi.Mask implement it, as
+// required by the policies looking at the individual participants.
+type IndexedMask interface {
+	ParticipationMask
+	// IndexEnabled returns whether the candidate at index i participates
+	IndexEnabled(i int) (bool, error)
+}
+
+// WeightedPolicy requires that the sum of the weights of the participants,
+// such as their stake, reaches a threshold. The weights are given by index
+// in the roster.
+type WeightedPolicy struct {
+	weights []uint64
+	thold   uint64
+}
+
+// NewWeightedPolicy returns a new WeightedPolicy where weights[i] is the
+// weight of the candidate at index i. The sum of the weights must fit in a
+// uint64.
+func NewWeightedPolicy(weights []uint64, thold uint64) (*WeightedPolicy, error) {
+	var sum uint64
+	for _, w := range weights {
+		if sum > math.MaxUint64-w {
+			return nil, errors.New("sum of the weights overflows")
+		}
+		sum += w
+	}
+	return &WeightedPolicy{weights: weights, thold: thold}, nil
+}
+
+// Check verifies that the participants of the mask, which must implement
+// IndexedMask and have one candidate per weight, have enough weight.
+func (p WeightedPolicy) Check(m ParticipationMask) bool {
+	im, ok := m.(IndexedMask)
+	if !ok || m.CountTotal() != len(p.weights) {
+		return false
+	}
+	var sum uint64
+	for i, w := range p.weights {
+		if enabled, _ := im.IndexEnabled(i); enabled {
+			// stop before the sum can wrap around
+			if sum >= p.thold || sum > math.MaxUint64-w {
+				return true
+			}
+			sum += w
+		}
+	}
+	return sum >= p.thold
+}
+
+// GroupPolicy checks a policy against the participation of a group of
+// candidates only, such as the members of an organization. The group is
+// given by the indices of its members in the roster, and the inner policy
+// sees them at indices 0 to len(indices)-1.
+type GroupPolicy struct {
+	indices []int
+	policy  Policy
+}
+
+// NewGroupPolicy returns a new GroupPolicy checking the policy against the
+// candidates at the given indices.
+func NewGroupPolicy(indices []int, policy Policy) *GroupPolicy {
+	return &GroupPolicy{indices: indices, policy: policy}
+}
+
+// Check verifies that the participation of the group, in a mask which must
+// implement IndexedMask, fulfills the inner policy.
+func (p GroupPolicy) Check(m ParticipationMask) bool {
+	im, ok := m.(IndexedMask)
+	if !ok {
+		return false
+	}
+	for _, i := range p.indices {
+		if i < 0 || i >= m.CountTotal() {
+			return false
+		}
+	}
+	return p.policy.Check(groupMask{im, p.indices})
+}
+
+// groupMask is the view of a mask restricted to a group.
+type groupMask struct {
+	m       IndexedMask
+	indices []int
+}
+
+func (g groupMask) CountTotal() int {
+	return len(g.indices)
+}
+
+func (g groupMask) CountEnabled() int {
+	n := 0
+	for _, i := range g.indices {
+		if enabled, _ := g.m.IndexEnabled(i); enabled {
+			n++
+		}
+	}
+	return n
+}
+
+func (g groupMask) IndexEnabled(i int) (bool, error) {
+	if i < 0 || i >= len(g.indices) {
+		return false, errors.New("index out of range")
+	}
+	return g.m.IndexEnabled(g.indices[i])
+}
+
+// CompositePolicy requires that at least k of its sub-policies are fulfilled.
+type CompositePolicy struct {
+	k        int
+	policies []Policy
+}
+
+// NewKOfNPolicy returns a new CompositePolicy requiring that k of the
+// policies are fulfilled.
+func NewKOfNPolicy(k int, policies ...Policy) *CompositePolicy {
+	return &CompositePolicy{k: k, policies: policies}
+}
+
+// NewAndPolicy returns a new CompositePolicy requiring that all the policies
+// are fulfilled.
+func NewAndPolicy(policies ...Policy) *CompositePolicy {
+	return NewKOfNPolicy(len(policies), policies...)
+}
+
+// NewOrPolicy returns a new CompositePolicy requiring that one of the
+// policies is fulfilled.
+func NewOrPolicy(policies ...Policy) *CompositePolicy {
+	return NewKOfNPolicy(1, policies...)
+}
+
+// Check verifies that at least k of the sub-policies are fulfilled.
+func (p CompositePolicy) Check(m ParticipationMask) bool {
+	n := 0
+	for _, sub := range p.policies {
+		if sub.Check(m) {
+			n++
+		}
+		if n >= p.k {
+			return true
+		}
+	}
+	return false
+}
+
+// Policy types of a PolicyDescription.
+const (
+	PolicyComplete  = "complete"
+	PolicyThreshold = "threshold"
+	PolicyWeighted  = "weighted"
+	PolicyGroup     = "group"
+	PolicyKOfN      = "k-of-n"
+)
+
+// PolicyDescription is a serializable description of the policies of this
+// package, which can be exchanged with the verifiers of a collective
+// signature.
+type PolicyDescription struct {
+	// Type is one of the Policy* constants.
+	Type string
+	// Threshold is the threshold of the threshold, weighted and k-of-n
+	// policies.
+	Threshold uint64
+	// Weights are the weights of a weighted policy.
+	Weights []uint64
+	// Indices are the indices of the members of a group policy.
+	Indices []uint32
+	// Policies are the sub-policies of a k-of-n policy, or the inner policy
+	// of a group policy.
+	Policies []*PolicyDescription
+}
+
+// DescribedPolicy is a policy which can be described, and serialized.
+type DescribedPolicy interface {
+	Policy
+	Describe() (*PolicyDescription, error)
+}
+
+// Describe returns the description of the policy.
+func (p CompletePolicy) Describe() (*PolicyDescription, error) {
+	return &PolicyDescription{Type: PolicyComplete}, nil
+}
+
+// Describe returns the description of the policy.
+func (p ThresholdPolicy) Describe() (*PolicyDescription, error) {
+	if p.thold < 0 {
+		return nil, errors.New("negative threshold")
+	}
+	return &PolicyDescription{Type: PolicyThreshold, Threshold: uint64(p.thold)}, nil
+}
+
+// Describe returns the description of the policy.
+func (p WeightedPolicy) Describe() (*PolicyDescription, error) {
+	return &PolicyDescription{Type: PolicyWeighted, Threshold: p.thold, Weights: p.weights}, nil
+}
+
+// Describe returns the description of the policy, which requires the inner
+// policy to be a DescribedPolicy.
+func (p GroupPolicy) Describe() (*PolicyDescription, error) {
+	inner, err := describe(p.policy)
+	if err != nil {
+		return nil, err
+	}
+	indices := make([]uint32, len(p.indices))
+	for j, i := range p.indices {
+		if i < 0 {
+			return nil, errors.New("negative index")
+		}
+		indices[j] = uint32(i)
+	}
+	return &PolicyDescription{Type: PolicyGroup, Indices: indices, Policies: []*PolicyDescription{inner}}, nil
+}
+
+// Describe returns the description of the policy, which requires the
+// sub-policies to be DescribedPolicy.
+func (p CompositePolicy) Describe() (*PolicyDescription, error) {
+	if p.k < 0 {
+		return nil, errors.New("negative threshold")
+	}
+	d := &PolicyDescription{Type: PolicyKOfN, Threshold: uint64(p.k)}
+	for _, sub := range p.policies {
+		sd, err := describe(sub)
+		if err != nil {
+			return nil, err
+		}
+		d.Policies = append(d.Policies, sd)
+	}
+	return d, nil
+}
+
+func describe(p Policy) (*PolicyDescription, error) {
+	dp, ok := p.(DescribedPolicy)
+	if !ok {
+		return nil, fmt.Errorf("policy %T cannot be described", p)
+	}
+	return dp.Describe()
+}
+
+// maxPolicyThreshold bounds the thresholds stored in an int.
+const maxPolicyThreshold = 1<<31 - 1
+
+// Policy returns the policy described.
+func (d *PolicyDescription) Policy() (Policy, error) {
+	switch d.Type {
+	case PolicyComplete:
+		return CompletePolicy{}, nil
+	case PolicyThreshold:
+		if d.Threshold > maxPolicyThreshold {
+			return nil, errors.New("threshold too large")
+		}
+		return NewThresholdPolicy(int(d.Threshold)), nil
+	case PolicyWeighted:
+		return NewWeightedPolicy(d.Weights, d.Threshold)
+	case PolicyGroup:
+		if len(d.Policies) != 1 {
+			return nil, errors.New("group policy needs one inner policy")
+		}
+		inner, err := d.Policies[0].Policy()
+		if err != nil {
+			return nil, err
+		}
+		indices := make([]int, len(d.Indices))
+		for j, i := range d.Indices {
+			indices[j] = int(i)
+		}
+		return NewGroupPolicy(indices, inner), nil
+	case PolicyKOfN:
+		if d.Threshold > maxPolicyThreshold {
+			return nil, errors.New("threshold too large")
+		}
+		policies := make([]Policy, len(d.Policies))
+		for i, sd := range d.Policies {
+			var err error
+			if policies[i], err = sd.Policy(); err != nil {
+				return nil, err
+			}
+		}
+		return NewKOfNPolicy(int(d.Threshold), policies...), nil
+	default:
+		return nil, fmt.Errorf("unknown policy type %q", d.Type)
+	}
+}
+
+// MarshalPolicy returns the serialized description of the policy.
+func MarshalPolicy(p Policy) ([]byte, error) {
+	d, err := describe(p)
+	if err != nil {
+		return nil, err
+	}
+	return protobuf.Encode(d)
+}
+
+// UnmarshalPolicy returns the policy whose description was serialized by
+// MarshalPolicy.
+func UnmarshalPolicy(buf []byte) (Policy, error) {
+	d := &PolicyDescription{}
+	if err := protobuf.Decode(buf, d); err != nil {
+		return nil, err
+	}
+	return d.Policy()
+}
@@ -4,6 +4,8 @@ import (
 	"testing"
 
 	"github.com/stretchr/testify/require"
+	"go.dedis.ch/kyber/v3"
+	"go.dedis.ch/kyber/v3/pairing"
 )
 
 type testMask struct {
@@ -44,3 +46,95 @@ func TestPolicy_ThresholdPolicy(t *testing.T) {
 	mask.numParticipants = 3
 	require.True(t, policy.Check(mask))
 }
+
+func indexedMask(t *testing.T, n int, enabled ...int) *Mask {
+	publics := make([]kyber.Point, n)
+	mask, err := NewMask(pairing.NewSuiteBn256(), publics, nil)
+	require.NoError(t, err)
+	for _, i := range enabled {
+		require.NoError(t, mask.SetBit(i, true))
+	}
+	return mask
+}
+
+func TestPolicy_WeightedPolicy(t *testing.T) {
+	policy, err := NewWeightedPolicy([]uint64{10, 40, 20, 30}, 60)
+	require.NoError(t, err)
+	require.True(t, policy.Check(indexedMask(t, 4, 1, 2)))
+	require.True(t, policy.Check(indexedMask(t, 4, 0, 2, 3)))
+	require.False(t, policy.Check(indexedMask(t, 4, 0, 3)))
+	// the mask must have one candidate per weight
+	require.False(t, policy.Check(indexedMask(t, 5, 0, 1, 2, 3)))
+	require.False(t, policy.Check(testMask{4, 4}))
+
+	// weights whose sum overflows are rejected
+	_, err = NewWeightedPolicy([]uint64{1 << 63, 1 << 63, 1}, 1<<63+1)
+	require.Error(t, err)
+	buf, err := MarshalPolicy(WeightedPolicy{weights: []uint64{1 << 63, 1 << 63, 1}, thold: 1<<63 + 1})
+	require.NoError(t, err)
+	_, err = UnmarshalPolicy(buf)
+	require.Error(t, err)
+
+	// and the check stays monotone for weights near the limit
+	policy = &WeightedPolicy{weights: []uint64{1 << 63, 1 << 63, 2}, thold: 1<<63 + 1}
+	require.False(t, policy.Check(indexedMask(t, 3, 0)))
+	require.True(t, policy.Check(indexedMask(t, 3, 0, 2)))
+	require.True(t, policy.Check(indexedMask(t, 3, 0, 1)))
+	require.True(t, policy.Check(indexedMask(t, 3, 0, 1, 2)))
+}
+
+func TestPolicy_CompositePolicy(t *testing.T) {
+	// 2 of org A and 3 of org B, or 2 of org C
+	orgA := []int{0, 1, 2}
+	orgB := []int{3, 4, 5, 6}
+	orgC := []int{7, 8}
+	policy := NewOrPolicy(
+		NewAndPolicy(
+			NewGroupPolicy(orgA, NewThresholdPolicy(2)),
+			NewGroupPolicy(orgB, NewThresholdPolicy(3)),
+		),
+		NewGroupPolicy(orgC, CompletePolicy{}),
+	)
+	require.True(t, policy.Check(indexedMask(t, 9, 0, 2, 3, 5, 6)))
+	require.False(t, policy.Check(indexedMask(t, 9, 0, 3, 5, 6, 7)))
+	require.True(t, policy.Check(indexedMask(t, 9, 7, 8)))
+	require.False(t, policy.Check(indexedMask(t, 5, 0, 1, 2, 3, 4)))
+
+	kofn := NewKOfNPolicy(2, NewThresholdPolicy(3), NewGroupPolicy(orgC, NewThresholdPolicy(1)), CompletePolicy{})
+	require.True(t, kofn.Check(indexedMask(t, 9, 0, 1, 8)))
+	require.False(t, kofn.Check(indexedMask(t, 9, 0, 1, 2)))
+}
+
+func TestPolicy_Marshal(t *testing.T) {
+	weighted, err := NewWeightedPolicy([]uint64{1, 2, 3}, 4)
+	require.NoError(t, err)
+	policy := NewAndPolicy(
+		NewGroupPolicy([]int{0, 1, 2}, weighted),
+		NewThresholdPolicy(4),
+		NewOrPolicy(CompletePolicy{}, NewGroupPolicy([]int{5}, CompletePolicy{})),
+	)
+	buf, err := MarshalPolicy(policy)
+	require.NoError(t, err)
+	decoded, err := UnmarshalPolicy(buf)
+	require.NoError(t, err)
+	require.Equal(t, policy, decoded)
+
+	for _, mask := range []*Mask{
+		indexedMask(t, 6, 1, 2, 3, 4, 5),
+		indexedMask(t, 6, 0, 2, 3, 4, 5),
+		indexedMask(t, 6, 0, 1, 2, 3, 4),
+	} {
+		require.Equal(t, policy.Check(mask), decoded.Check(mask))
+	}
+
+	_, err = MarshalPolicy(NewOrPolicy(testPolicy{}))
+	require.Error(t, err)
+	_, err = (&PolicyDescription{Type: "unknown"}).Policy()
+	require.Error(t, err)
+	_, err = (&PolicyDescription{Type: PolicyGroup}).Policy()
+	require.Error(t, err)
+}
+
+type testPolicy struct{}
+
+func (testPolicy) Check(ParticipationMask) bool { return true }
@@ -0,0 +1,113 @@
+package schnorr
+
+import (
+	"bytes"
+	"errors"
+
+	"go.dedis.ch/kyber/v3"
+)
+
+// An adaptor signature, or pre-signature, under an adaptor point T = t*G is
+// a pair (R, s') such that s'*G = R + c*X, where the challenge
+// c = hash(R + T || X || msg) commits to R + T instead of R. It can be
+// checked by anyone knowing T, but it is not a signature: the holder of t
+// turns it into the signature (R + T, s' + t), which verifies with Verify,
+// and anyone seeing both the pre-signature and the signature learns t. This
+// makes the publication of a signature reveal a secret, as in atomic swaps
+// and payment channels.
+
+// PreSign creates a pre-signature of msg with the private key under the
+// adaptor point T. It has the R || s' layout of the signatures of Sign. The
+// nonce is hedged as Sign's, and bound to T.
+func PreSign(s Suite, private kyber.Scalar, T kyber.Point, msg []byte) ([]byte, error) {
+	var g kyber.Group = s
+	extra := make([]byte, 32)
+	s.RandomStream().XORKeyStream(extra, extra)
+	t, err := T.MarshalBinary()
+	if err != nil {
+		return nil, err
+	}
+	k, err := nonce(g, private, msg, append(extra, t...))
+	if err != nil {
+		return nil, err
+	}
+	R := g.Point().Mul(k, nil)
+
+	// create hash(public || R + T || message)
+	public := g.Point().Mul(private, nil)
+	h, err := hash(g, public, g.Point().Add(R, T), msg)
+	if err != nil {
+		return nil, err
+	}
+
+	// compute response s' = k + x*h
+	S := g.Scalar().Add(k, g.Scalar().Mul(private, h))
+	return encodeSig(R, S)
+}
+
+// PreVerify checks a pre-signature of msg under the public key and the
+// adaptor point T. It returns nil iff the pre-signature is valid, in which
+// case adapting it with the discrete logarithm of T yields a valid
+// signature.
+func PreVerify(g kyber.Group, public, T kyber.Point, msg, presig []byte) error {
+	R, s, err := decodeSig(g, presig)
+	if err != nil {
+		return err
+	}
+	h, err := hash(g, public, g.Point().Add(R, T), msg)
+	if err != nil {
+		return err
+	}
+
+	// check s'*G == R + h*public
+	S := g.Point().Mul(s, nil)
+	RAh := g.Point().Add(R, g.Point().Mul(h, public))
+	if !S.Equal(RAh) {
+		return errors.New("schnorr: invalid pre-signature")
+	}
+	return nil
+}
+
+// Adapt completes a pre-signature with the secret t of its adaptor point
+// T = t*G and returns the signature (R + T, s' + t), which verifies with
+// Verify if the pre-signature passes PreVerify.
+func Adapt(g kyber.Group, presig []byte, t kyber.Scalar) ([]byte, error) {
+	R, s, err := decodeSig(g, presig)
+	if err != nil {
+		return nil, err
+	}
+	R.Add(R, g.Point().Mul(t, nil))
+	s.Add(s, t)
+	return encodeSig(R, s)
+}
+
+// Extract returns the secret t of the adaptor point of a pre-signature from
+// the signature adapted from it. It returns an error if the signature is
+// not an adaptation of the pre-signature.
+func Extract(g kyber.Group, presig, sig []byte) (kyber.Scalar, error) {
+	R, s, err := decodeSig(g, presig)
+	if err != nil {
+		return nil, err
+	}
+	RT, st, err := decodeSig(g, sig)
+	if err != nil {
+		return nil, err
+	}
+	// t = (s' + t) - s', with R + t*G == R + T
+	t := g.Scalar().Sub(st, s)
+	if !g.Point().Add(R, g.Point().Mul(t, nil)).Equal(RT) {
+		return nil, errors.New("schnorr: signature does not adapt the pre-signature")
+	}
+	return t, nil
+}
+
+func encodeSig(R kyber.Point, s kyber.Scalar) ([]byte, error) {
+	var b bytes.Buffer
+	if _, err := R.MarshalTo(&b); err != nil {
+		return nil, err
+	}
+	if _, err := s.MarshalTo(&b); err != nil {
+		return nil, err
+	}
+	return b.Bytes(), nil
+}
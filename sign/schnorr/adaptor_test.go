@@ -0,0 +1,61 @@
+package schnorr
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"go.dedis.ch/kyber/v3/group/edwards25519"
+	"go.dedis.ch/kyber/v3/group/nist"
+	"go.dedis.ch/kyber/v3/sign/eddsa"
+	"go.dedis.ch/kyber/v3/util/key"
+)
+
+func TestAdaptorSignature(t *testing.T) {
+	msg := []byte("Hello Adaptor")
+	for _, suite := range []Suite{edwards25519.NewBlakeSHA256Ed25519(), nist.NewBlakeSHA256P256()} {
+		kp := key.NewKeyPair(suite)
+		adaptor := key.NewKeyPair(suite)
+
+		presig, err := PreSign(suite, kp.Private, adaptor.Public, msg)
+		require.NoError(t, err)
+		require.NoError(t, PreVerify(suite, kp.Public, adaptor.Public, msg, presig))
+		require.Error(t, Verify(suite, kp.Public, msg, presig))
+		require.Error(t, PreVerify(suite, kp.Public, adaptor.Public, []byte("other"), presig))
+		require.Error(t, PreVerify(suite, kp.Public, kp.Public, msg, presig))
+		require.Error(t, PreVerify(suite, adaptor.Public, adaptor.Public, msg, presig))
+
+		sig, err := Adapt(suite, presig, adaptor.Private)
+		require.NoError(t, err)
+		require.NoError(t, Verify(suite, kp.Public, msg, sig))
+
+		secret, err := Extract(suite, presig, sig)
+		require.NoError(t, err)
+		require.True(t, suite.Point().Mul(secret, nil).Equal(adaptor.Public))
+
+		// a wrong secret gives an invalid signature
+		wrong, err := Adapt(suite, presig, kp.Private)
+		require.NoError(t, err)
+		require.Error(t, Verify(suite, kp.Public, msg, wrong))
+
+		// an unrelated signature reveals nothing
+		other, err := Sign(suite, kp.Private, msg)
+		require.NoError(t, err)
+		_, err = Extract(suite, presig, other)
+		require.Error(t, err)
+		_, err = Extract(suite, presig, other[1:])
+		require.Error(t, err)
+	}
+}
+
+func TestAdaptorEdDSACompatibility(t *testing.T) {
+	msg := []byte("Hello Adaptor")
+	suite := edwards25519.NewBlakeSHA256Ed25519()
+	kp := key.NewKeyPair(suite)
+	adaptor := key.NewKeyPair(suite)
+
+	presig, err := PreSign(suite, kp.Private, adaptor.Public, msg)
+	require.NoError(t, err)
+	sig, err := Adapt(suite, presig, adaptor.Private)
+	require.NoError(t, err)
+	require.NoError(t, eddsa.Verify(kp.Public, msg, sig))
+}
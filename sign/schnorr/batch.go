@@ -0,0 +1,140 @@
+package schnorr
+
+import (
+	"crypto/cipher"
+	"errors"
+	"fmt"
+	"sort"
+
+	"go.dedis.ch/kyber/v3"
+	"go.dedis.ch/kyber/v3/util/random"
+)
+
+// BatchError is returned by BatchVerify when some signatures of the batch
+// are invalid.
+type BatchError struct {
+	// Invalid holds the indices of the invalid signatures, in increasing
+	// order.
+	Invalid []int
+}
+
+func (e *BatchError) Error() string {
+	return fmt.Sprintf("schnorr: invalid signatures in batch at indices %v", e.Invalid)
+}
+
+// multiScalarMuler is implemented by groups providing a fast multi-scalar
+// multiplication, such as edwards25519.
+type multiScalarMuler interface {
+	MultiScalarMulVarTime(scalars []kyber.Scalar, points []kyber.Point) kyber.Point
+}
+
+// batchEntry is a decoded signature of a batch.
+type batchEntry struct {
+	index int
+	R, A  kyber.Point
+	s, h  kyber.Scalar
+}
+
+// BatchVerify checks that sigs[i] is a valid signature of msgs[i] under
+// publics[i], for every i. It returns nil if all signatures are valid, and
+// a *BatchError holding the indices of the invalid ones otherwise.
+//
+// The signatures are checked together, by verifying a random linear
+// combination of their verification equations. If the group provides a
+// multi-scalar multiplication, as edwards25519 does, the combination is
+// computed with it, which is several times faster than verifying the
+// signatures one after the other. If the batch fails, it is split in halves
+// which are checked recursively, to locate the invalid signatures.
+//
+// In groups with a cofactor, a batch may be accepted with small
+// probability although some signatures, which differ from valid ones by a
+// small-order component, are rejected by Verify. Such signatures are never
+// produced by Sign.
+func BatchVerify(g kyber.Group, publics []kyber.Point, msgs, sigs [][]byte) error {
+	if len(publics) != len(msgs) || len(msgs) != len(sigs) {
+		return errors.New("schnorr: mismatched number of public keys, messages and signatures")
+	}
+
+	var invalid []int
+	entries := make([]*batchEntry, 0, len(sigs))
+	for i := range sigs {
+		R, s, err := decodeSig(g, sigs[i])
+		if err != nil {
+			invalid = append(invalid, i)
+			continue
+		}
+		h, err := hash(g, publics[i], R, msgs[i])
+		if err != nil {
+			invalid = append(invalid, i)
+			continue
+		}
+		entries = append(entries, &batchEntry{index: i, R: R, A: publics[i], s: s, h: h})
+	}
+
+	rand := random.New()
+	if !checkBatch(g, entries, rand) {
+		invalid = append(invalid, locateInvalid(g, entries, rand)...)
+	}
+	if len(invalid) == 0 {
+		return nil
+	}
+	sort.Ints(invalid)
+	return &BatchError{Invalid: invalid}
+}
+
+// checkBatch returns whether the entries satisfy
+//
+//	sum z_i*s_i*B == sum z_i*R_i + sum z_i*h_i*A_i
+//
+// for random 128-bit coefficients z_i. A single entry is checked exactly as
+// Verify does.
+func checkBatch(g kyber.Group, entries []*batchEntry, rand cipher.Stream) bool {
+	if len(entries) == 0 {
+		return true
+	}
+	if len(entries) == 1 {
+		e := entries[0]
+		S := g.Point().Mul(e.s, nil)
+		return S.Equal(g.Point().Add(e.R, g.Point().Mul(e.h, e.A)))
+	}
+
+	scalars := make([]kyber.Scalar, 0, 2*len(entries)+1)
+	points := make([]kyber.Point, 0, 2*len(entries)+1)
+	sB := g.Scalar().Zero()
+	for _, e := range entries {
+		z := g.Scalar().SetBytes(random.Bits(128, false, rand))
+		sB.Add(sB, g.Scalar().Mul(z, e.s))
+		scalars = append(scalars, g.Scalar().Neg(z),
+			g.Scalar().Neg(g.Scalar().Mul(z, e.h)))
+		points = append(points, e.R, e.A)
+	}
+	scalars = append(scalars, sB)
+	points = append(points, g.Point().Base())
+
+	var sum kyber.Point
+	if msm, ok := g.(multiScalarMuler); ok {
+		sum = msm.MultiScalarMulVarTime(scalars, points)
+	} else {
+		sum = g.Point().Null()
+		tmp := g.Point()
+		for i := range scalars {
+			sum.Add(sum, tmp.Mul(scalars[i], points[i]))
+		}
+	}
+	return sum.Equal(g.Point().Null())
+}
+
+// locateInvalid returns the indices of the invalid entries of a batch
+// which failed, by bisection.
+func locateInvalid(g kyber.Group, entries []*batchEntry, rand cipher.Stream) []int {
+	if len(entries) == 1 {
+		return []int{entries[0].index}
+	}
+	var invalid []int
+	for _, half := range [][]*batchEntry{entries[:len(entries)/2], entries[len(entries)/2:]} {
+		if !checkBatch(g, half, rand) {
+			invalid = append(invalid, locateInvalid(g, half, rand)...)
+		}
+	}
+	return invalid
+}
@@ -0,0 +1,107 @@
+package schnorr
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"go.dedis.ch/kyber/v3"
+	"go.dedis.ch/kyber/v3/group/edwards25519"
+	"go.dedis.ch/kyber/v3/group/nist"
+	"go.dedis.ch/kyber/v3/pairing/bn256"
+	"go.dedis.ch/kyber/v3/util/key"
+)
+
+var batchSuites = []Suite{
+	edwards25519.NewBlakeSHA256Ed25519(),
+	nist.NewBlakeSHA256P256(),
+	bn256.NewSuiteG1(),
+}
+
+// bumpResponse returns sig with its response s replaced by s+1, which keeps
+// a well-formed encoding but breaks the verification equation.
+func bumpResponse(t *testing.T, g kyber.Group, sig []byte) []byte {
+	R, s, err := decodeSig(g, sig)
+	require.NoError(t, err)
+	s.Add(s, g.Scalar().One())
+	bumped, err := encodeSig(R, s)
+	require.NoError(t, err)
+	return bumped
+}
+
+func TestBatchVerify(t *testing.T) {
+	for _, suite := range batchSuites {
+		// a few signers, each signing several messages, with both the
+		// hedged and the deterministic nonces
+		const n = 12
+		signers := []*key.Pair{key.NewKeyPair(suite), key.NewKeyPair(suite), key.NewKeyPair(suite)}
+		publics := make([]kyber.Point, n)
+		msgs := make([][]byte, n)
+		sigs := make([][]byte, n)
+		for i := range sigs {
+			kp := signers[i%len(signers)]
+			publics[i] = kp.Public
+			msgs[i] = []byte(fmt.Sprintf("message %d", i))
+			var err error
+			if i%2 == 0 {
+				sigs[i], err = Sign(suite, kp.Private, msgs[i])
+			} else {
+				sigs[i], err = SignDeterministic(suite, kp.Private, msgs[i])
+			}
+			require.NoError(t, err)
+		}
+		require.NoError(t, BatchVerify(suite, publics, msgs, sigs), "%s", suite)
+		require.NoError(t, BatchVerify(suite, nil, nil, nil))
+		require.Error(t, BatchVerify(suite, publics[1:], msgs, sigs))
+
+		// an invalid signature is located wherever it is in the batch
+		for _, pos := range []int{0, n / 2, n - 1} {
+			bad := append([][]byte{}, sigs...)
+			bad[pos] = bumpResponse(t, suite, sigs[pos])
+			require.Error(t, Verify(suite, publics[pos], msgs[pos], bad[pos]))
+			err := BatchVerify(suite, publics, msgs, bad)
+			require.Equal(t, &BatchError{Invalid: []int{pos}}, err, "%s, position %d", suite, pos)
+		}
+
+		// the signatures are bound to their signer
+		swapped := append([]kyber.Point{}, publics...)
+		swapped[4], swapped[5] = swapped[5], swapped[4]
+		err := BatchVerify(suite, swapped, msgs, sigs)
+		require.Equal(t, &BatchError{Invalid: []int{4, 5}}, err, "%s", suite)
+	}
+}
+
+func BenchmarkBatchVerify(b *testing.B) {
+	const n = 64
+	for _, suite := range batchSuites {
+		publics := make([]kyber.Point, n)
+		msgs := make([][]byte, n)
+		sigs := make([][]byte, n)
+		for i := range sigs {
+			kp := key.NewKeyPair(suite)
+			publics[i] = kp.Public
+			msgs[i] = []byte(fmt.Sprintf("message %d", i))
+			sig, err := Sign(suite, kp.Private, msgs[i])
+			if err != nil {
+				b.Fatal(err)
+			}
+			sigs[i] = sig
+		}
+		b.Run(fmt.Sprintf("%s/Verify/%d", suite, n), func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				for j := range sigs {
+					if err := Verify(suite, publics[j], msgs[j], sigs[j]); err != nil {
+						b.Fatal(err)
+					}
+				}
+			}
+		})
+		b.Run(fmt.Sprintf("%s/BatchVerify/%d", suite, n), func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				if err := BatchVerify(suite, publics, msgs, sigs); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	}
+}
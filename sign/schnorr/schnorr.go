@@ -18,6 +18,8 @@ import (
 	"fmt"
 
 	"go.dedis.ch/kyber/v3"
+	"go.dedis.ch/kyber/v3/proof/transcript"
+	"go.dedis.ch/kyber/v3/util/random"
 )
 
 // Suite represents the set of functionalities needed by the package schnorr.
@@ -29,10 +31,44 @@ type Suite interface {
 // Sign creates a Sign signature from a msg and a private key. This
 // signature can be verified with VerifySchnorr. It's also a valid EdDSA
 // signature when using the edwards25519 Group.
+//
+// The nonce is hedged: it is derived from the private key and the message
+// as in SignDeterministic, with fresh randomness from s.RandomStream()
+// mixed in, so that a broken random stream does not leak the private key.
 func Sign(s Suite, private kyber.Scalar, msg []byte) ([]byte, error) {
-	var g kyber.Group = s
-	// create random secret k and public point commitment R
-	k := g.Scalar().Pick(s.RandomStream())
+	extra := make([]byte, 32)
+	s.RandomStream().XORKeyStream(extra, extra)
+	return sign(s, private, msg, extra)
+}
+
+// SignDeterministic is like Sign, but derives the nonce only from the
+// private key and the message, in the manner of RFC 6979, so that signing
+// the same message twice yields the same signature and no randomness is
+// needed.
+func SignDeterministic(g kyber.Group, private kyber.Scalar, msg []byte) ([]byte, error) {
+	return sign(g, private, msg, nil)
+}
+
+// nonce derives the secret nonce of a signature of msg from an HMAC_DRBG
+// instantiated with the private key as entropy input, the hash of msg as
+// nonce and the optional extra randomness in the personalization string,
+// as RFC 6979, section 3.6, suggests.
+func nonce(g kyber.Group, private kyber.Scalar, msg, extra []byte) (kyber.Scalar, error) {
+	x, err := private.MarshalBinary()
+	if err != nil {
+		return nil, err
+	}
+	h := sha512.Sum512(msg)
+	personalization := append([]byte("kyber schnorr nonce"), extra...)
+	return g.Scalar().Pick(random.NewHMACDRBGWithHash(sha512.New, x, h[:], personalization)), nil
+}
+
+func sign(g kyber.Group, private kyber.Scalar, msg, extra []byte) ([]byte, error) {
+	// create secret nonce k and public point commitment R
+	k, err := nonce(g, private, msg, extra)
+	if err != nil {
+		return nil, err
+	}
 	R := g.Point().Mul(k, nil)
 
 	// create hash(public || R || message)
@@ -60,18 +96,8 @@ func Sign(s Suite, private kyber.Scalar, msg []byte) ([]byte, error) {
 // Verify verifies a given Schnorr signature. It returns nil iff the
 // given signature is valid.
 func Verify(g kyber.Group, public kyber.Point, msg, sig []byte) error {
-	R := g.Point()
-	s := g.Scalar()
-	pointSize := R.MarshalSize()
-	scalarSize := s.MarshalSize()
-	sigSize := scalarSize + pointSize
-	if len(sig) != sigSize {
-		return fmt.Errorf("schnorr: signature of invalid length %d instead of %d", len(sig), sigSize)
-	}
-	if err := R.UnmarshalBinary(sig[:pointSize]); err != nil {
-		return err
-	}
-	if err := s.UnmarshalBinary(sig[pointSize:]); err != nil {
+	R, s, err := decodeSig(g, sig)
+	if err != nil {
 		return err
 	}
 	// recompute hash(public || R || msg)
@@ -106,3 +132,92 @@ func hash(g kyber.Group, public, r kyber.Point, msg []byte) (kyber.Scalar, error
 	}
 	return g.Scalar().SetBytes(h.Sum(nil)), nil
 }
+
+// Challenge returns the challenge hash(R || public || msg) of a Schnorr
+// signature with commitment R. It is exported for the protocols producing
+// signatures checked by Verify, such as blind signatures.
+func Challenge(g kyber.Group, public, R kyber.Point, msg []byte) (kyber.Scalar, error) {
+	return hash(g, public, R, msg)
+}
+
+// TranscriptChallenge appends the public key, message and commitment R of
+// a Schnorr signature to t and returns the resulting challenge. It is
+// exported for the threshold protocols that produce signatures checked by
+// VerifyTranscript.
+func TranscriptChallenge(t *transcript.Transcript, public, R kyber.Point, msg []byte) kyber.Scalar {
+	appendSigningInput(t, public, msg)
+	t.AppendPoint("R", R)
+	return t.ChallengeScalar("c")
+}
+
+func appendSigningInput(t *transcript.Transcript, public kyber.Point, msg []byte) {
+	t.AppendMessage("dom-sep", []byte("schnorr"))
+	t.AppendPoint("public", public)
+	t.AppendMessage("msg", msg)
+}
+
+// SignTranscript creates a Schnorr signature whose challenge is derived from
+// the transcript t instead of a bare hash, so that it is bound to whatever
+// context was appended to t beforehand. The nonce is derived from t, the
+// private key, the message and s.RandomStream(). The signature has the same
+// R || s layout as Sign's, but can only be checked with VerifyTranscript.
+// t is updated by the call.
+func SignTranscript(s Suite, t *transcript.Transcript, private kyber.Scalar, msg []byte) ([]byte, error) {
+	var g kyber.Group = s
+	public := g.Point().Mul(private, nil)
+	appendSigningInput(t, public, msg)
+
+	rng := t.BuildRNG().RekeyWithWitnessScalar("private", private).Finalize(s.RandomStream())
+	k := g.Scalar().Pick(rng)
+	R := g.Point().Mul(k, nil)
+	t.AppendPoint("R", R)
+	c := t.ChallengeScalar("c")
+
+	// compute response s = k + x*c
+	S := g.Scalar().Add(k, g.Scalar().Mul(private, c))
+
+	var b bytes.Buffer
+	if _, err := R.MarshalTo(&b); err != nil {
+		return nil, err
+	}
+	if _, err := S.MarshalTo(&b); err != nil {
+		return nil, err
+	}
+	return b.Bytes(), nil
+}
+
+// VerifyTranscript verifies a signature created by SignTranscript. The
+// transcript t must be in the same state as the signer's and is updated by
+// the call. It returns nil iff the given signature is valid.
+func VerifyTranscript(g kyber.Group, t *transcript.Transcript, public kyber.Point, msg, sig []byte) error {
+	R, s, err := decodeSig(g, sig)
+	if err != nil {
+		return err
+	}
+	c := TranscriptChallenge(t, public, R, msg)
+
+	S := g.Point().Mul(s, nil)
+	RAc := g.Point().Add(R, g.Point().Mul(c, public))
+	if !S.Equal(RAc) {
+		return errors.New("schnorr: invalid signature")
+	}
+	return nil
+}
+
+func decodeSig(g kyber.Group, sig []byte) (kyber.Point, kyber.Scalar, error) {
+	R := g.Point()
+	s := g.Scalar()
+	pointSize := R.MarshalSize()
+	scalarSize := s.MarshalSize()
+	sigSize := scalarSize + pointSize
+	if len(sig) != sigSize {
+		return nil, nil, fmt.Errorf("schnorr: signature of invalid length %d instead of %d", len(sig), sigSize)
+	}
+	if err := R.UnmarshalBinary(sig[:pointSize]); err != nil {
+		return nil, nil, err
+	}
+	if err := s.UnmarshalBinary(sig[pointSize:]); err != nil {
+		return nil, nil, err
+	}
+	return R, s, nil
+}
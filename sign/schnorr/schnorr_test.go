@@ -8,6 +8,8 @@ import (
 
 	"github.com/stretchr/testify/assert"
 	"go.dedis.ch/kyber/v3/group/edwards25519"
+	"go.dedis.ch/kyber/v3/group/nist"
+	"go.dedis.ch/kyber/v3/proof/transcript"
 	"go.dedis.ch/kyber/v3/sign/eddsa"
 	"go.dedis.ch/kyber/v3/util/key"
 )
@@ -96,3 +98,65 @@ func TestQuickSchnorrSignature(t *testing.T) {
 		t.Error(err)
 	}
 }
+
+func TestSchnorrTranscript(t *testing.T) {
+	msg := []byte("Hello Schnorr")
+	suite := edwards25519.NewBlakeSHA256Ed25519()
+	kp := key.NewKeyPair(suite)
+	context := func() *transcript.Transcript {
+		tr := transcript.New(suite, "test")
+		tr.AppendMessage("context", []byte("session 1"))
+		return tr
+	}
+
+	s, err := SignTranscript(suite, context(), kp.Private, msg)
+	assert.NoError(t, err)
+	assert.NoError(t, VerifyTranscript(suite, context(), kp.Public, msg, s))
+
+	// The signature is bound to the context of the transcript.
+	tr := transcript.New(suite, "test")
+	tr.AppendMessage("context", []byte("session 2"))
+	assert.Error(t, VerifyTranscript(suite, tr, kp.Public, msg, s))
+
+	// It cannot be replayed as a plain signature.
+	assert.Error(t, Verify(suite, kp.Public, msg, s))
+
+	assert.Error(t, VerifyTranscript(suite, context(), kp.Public, []byte("other"), s))
+	assert.Error(t, VerifyTranscript(suite, context(), key.NewKeyPair(suite).Public, msg, s))
+	assert.Error(t, VerifyTranscript(suite, context(), kp.Public, msg, s[1:]))
+}
+
+func TestSchnorrNonce(t *testing.T) {
+	msg := []byte("Hello Schnorr")
+	for _, suite := range []Suite{edwards25519.NewBlakeSHA256Ed25519(), nist.NewBlakeSHA256P256()} {
+		kp := key.NewKeyPair(suite)
+
+		s1, err := SignDeterministic(suite, kp.Private, msg)
+		assert.NoError(t, err)
+		s2, err := SignDeterministic(suite, kp.Private, msg)
+		assert.NoError(t, err)
+		assert.Equal(t, s1, s2)
+		assert.NoError(t, Verify(suite, kp.Public, msg, s1))
+		s3, err := SignDeterministic(suite, kp.Private, []byte("Hello again"))
+		assert.NoError(t, err)
+		assert.NotEqual(t, s1, s3)
+
+		// Hedged signatures differ from deterministic ones.
+		s4, err := Sign(suite, kp.Private, msg)
+		assert.NoError(t, err)
+		assert.NotEqual(t, s1, s4)
+	}
+
+	// A random stream stuck on the same output does not make the nonce
+	// repeat across messages.
+	suite := edwards25519.NewBlakeSHA256Ed25519WithRand(&quickstream{rand: rand.New(rand.NewSource(0))})
+	kp := key.NewKeyPair(suite)
+	stuck := edwards25519.NewBlakeSHA256Ed25519WithRand(&quickstream{rand: rand.New(rand.NewSource(0))})
+	s1, err := Sign(stuck, kp.Private, msg)
+	assert.NoError(t, err)
+	stuck = edwards25519.NewBlakeSHA256Ed25519WithRand(&quickstream{rand: rand.New(rand.NewSource(0))})
+	s2, err := Sign(stuck, kp.Private, []byte("Hello again"))
+	assert.NoError(t, err)
+	assert.NotEqual(t, s1[:32], s2[:32])
+	assert.NoError(t, Verify(suite, kp.Public, msg, s1))
+}
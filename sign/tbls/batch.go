@@ -0,0 +1,131 @@
+package tbls
+
+import (
+	"crypto/cipher"
+	"sort"
+
+	"go.dedis.ch/kyber/v3"
+	"go.dedis.ch/kyber/v3/share"
+	"go.dedis.ch/kyber/v3/util/random"
+)
+
+// shareEntry is a decoded signature share.
+type shareEntry struct {
+	pos   int // position in the slice of shares
+	index int
+	S     kyber.Point
+}
+
+// VerifyShares checks signature shares of msg, and returns the positions in
+// sigs of the invalid ones, in increasing order, or nil if they are all
+// valid.
+//
+// The shares are checked together, by verifying
+//
+//	e(sum r_i*S_i, B) == e(H(m), sum r_i*X_i)
+//
+// for random 128-bit coefficients r_i, drawn from crypto/rand as in
+// bls.Scheme.VerifyBatch, where X_i is the evaluation of the public
+// polynomial at the index of the share. The sum of the X_i is computed from
+// the commitments of the polynomial, without evaluating it at each index,
+// so that the shares are checked with two pairings. If the check fails, the
+// shares are split in halves which are checked recursively, to locate the
+// invalid ones.
+func (s *ThresholdScheme) VerifyShares(public *share.PubPoly, msg []byte, sigs [][]byte) ([]int, error) {
+	_, invalid, err := s.verifyShares(public, msg, sigs)
+	return invalid, err
+}
+
+// verifyShares returns the valid shares and the positions of the invalid
+// ones.
+func (s *ThresholdScheme) verifyShares(public *share.PubPoly, msg []byte, sigs [][]byte) ([]*shareEntry, []int, error) {
+	H, err := s.bls.HashToPoint(msg)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var invalid []int
+	entries := make([]*shareEntry, 0, len(sigs))
+	for pos, sig := range sigs {
+		sh := SigShare(sig)
+		i, err := sh.Index()
+		if err != nil {
+			invalid = append(invalid, pos)
+			continue
+		}
+		S := s.bls.SignatureGroup().Point()
+		if err := S.UnmarshalBinary(sh.Value()); err != nil {
+			invalid = append(invalid, pos)
+			continue
+		}
+		entries = append(entries, &shareEntry{pos: pos, index: i, S: S})
+	}
+
+	rand := random.New()
+	var bad []*shareEntry
+	if !s.checkShares(public, H, entries, rand) {
+		bad = s.locateInvalid(public, H, entries, rand)
+	}
+	isBad := make(map[*shareEntry]bool, len(bad))
+	for _, e := range bad {
+		isBad[e] = true
+		invalid = append(invalid, e.pos)
+	}
+	valid := make([]*shareEntry, 0, len(entries)-len(bad))
+	for _, e := range entries {
+		if !isBad[e] {
+			valid = append(valid, e)
+		}
+	}
+	sort.Ints(invalid)
+	return valid, invalid, nil
+}
+
+// checkShares returns whether the entries satisfy the batch equation.
+func (s *ThresholdScheme) checkShares(public *share.PubPoly, H kyber.Point, entries []*shareEntry, rand cipher.Stream) bool {
+	if len(entries) == 0 {
+		return true
+	}
+	sigGroup, keyGroup := s.bls.SignatureGroup(), s.bls.KeyGroup()
+	_, commits := public.Info()
+
+	// sum r_i*X_i = sum_j (sum_i r_i*x_i^j)*C_j, where x_i = i+1
+	coeffs := make([]kyber.Scalar, len(commits))
+	for j := range coeffs {
+		coeffs[j] = keyGroup.Scalar().Zero()
+	}
+	sum := sigGroup.Point().Null()
+	for _, e := range entries {
+		r := keyGroup.Scalar().SetBytes(random.Bits(128, false, rand))
+		sum.Add(sum, sigGroup.Point().Mul(r, e.S))
+		x := keyGroup.Scalar().SetInt64(1 + int64(e.index))
+		xj := r
+		for j := range coeffs {
+			coeffs[j].Add(coeffs[j], xj)
+			xj = keyGroup.Scalar().Mul(xj, x)
+		}
+	}
+	key := keyGroup.Point().Null()
+	for j, c := range commits {
+		key.Add(key, keyGroup.Point().Mul(coeffs[j], c))
+	}
+
+	left := s.bls.Pair(sum, keyGroup.Point().Base())
+	right := s.bls.Pair(H, key)
+	return left.Equal(right)
+}
+
+// locateInvalid returns the invalid entries of a batch which failed, by
+// bisection.
+func (s *ThresholdScheme) locateInvalid(public *share.PubPoly, H kyber.Point, entries []*shareEntry, rand cipher.Stream) []*shareEntry {
+	if len(entries) == 1 {
+		return entries
+	}
+	var invalid []*shareEntry
+	for _, half := range [][]*shareEntry{entries[:len(entries)/2], entries[len(entries)/2:]} {
+		if !s.checkShares(public, H, half, rand) {
+			invalid = append(invalid, s.locateInvalid(public, H, half, rand)...)
+		}
+	}
+	return invalid
+}
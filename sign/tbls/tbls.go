@@ -6,13 +6,15 @@
 // partial (BLS) signatures Si on m using their individual key shares xi which
 // can then be used to recover the full (regular) BLS signature S via Lagrange
 // interpolation. The signature S can be verified with the initially
-// established group key X. Signatures are points on curve G1 and public keys
-// are points on curve G2.
+// established group key X. With the package-level functions, signatures are
+// points on curve G1 and public keys are points on curve G2; a ThresholdScheme
+// returned by NewThresholdSchemeOnG2 swaps the groups.
 package tbls
 
 import (
 	"bytes"
 	"encoding/binary"
+	"sort"
 
 	"go.dedis.ch/kyber/v3/pairing"
 	"go.dedis.ch/kyber/v3/share"
@@ -40,18 +42,44 @@ func (s *SigShare) Value() []byte {
 	return []byte(*s)[2:]
 }
 
+// ThresholdScheme is the threshold BLS signature scheme on top of a bare BLS
+// scheme, with either the signatures on G1 and the public keys on G2, or the
+// converse.
+type ThresholdScheme struct {
+	bls *bls.Scheme
+}
+
+// NewThresholdSchemeOnG1 returns the threshold scheme with signatures on G1
+// and public keys on G2, as the package-level functions.
+func NewThresholdSchemeOnG1(suite pairing.Suite) *ThresholdScheme {
+	return &ThresholdScheme{bls: bls.NewSchemeOnG1(suite)}
+}
+
+// NewThresholdSchemeOnG2 returns the threshold scheme with signatures on G2
+// and public keys on G1. The public sharing polynomial must be committed
+// with the base point of G1.
+func NewThresholdSchemeOnG2(suite pairing.Suite) *ThresholdScheme {
+	return &ThresholdScheme{bls: bls.NewSchemeOnG2(suite)}
+}
+
+// Scheme returns the underlying BLS scheme, with which recovered signatures
+// are verified.
+func (s *ThresholdScheme) Scheme() *bls.Scheme {
+	return s.bls
+}
+
 // Sign creates a threshold BLS signature Si = xi * H(m) on the given message m
 // using the provided secret key share xi.
-func Sign(suite pairing.Suite, private *share.PriShare, msg []byte) ([]byte, error) {
+func (s *ThresholdScheme) Sign(private *share.PriShare, msg []byte) ([]byte, error) {
 	buf := new(bytes.Buffer)
 	if err := binary.Write(buf, binary.BigEndian, uint16(private.I)); err != nil {
 		return nil, err
 	}
-	s, err := bls.Sign(suite, private.V, msg)
+	sig, err := s.bls.Sign(private.V, msg)
 	if err != nil {
 		return nil, err
 	}
-	if err := binary.Write(buf, binary.BigEndian, s); err != nil {
+	if err := binary.Write(buf, binary.BigEndian, sig); err != nil {
 		return nil, err
 	}
 	return buf.Bytes(), nil
@@ -61,47 +89,92 @@ func Sign(suite pairing.Suite, private *share.PriShare, msg []byte) ([]byte, err
 // the public key share Xi that is associated to the secret key share xi. This
 // public key share Xi can be computed by evaluating the public sharing
 // polynonmial at the share's index i.
-func Verify(suite pairing.Suite, public *share.PubPoly, msg, sig []byte) error {
-	s := SigShare(sig)
-	i, err := s.Index()
+func (s *ThresholdScheme) Verify(public *share.PubPoly, msg, sig []byte) error {
+	sh := SigShare(sig)
+	i, err := sh.Index()
 	if err != nil {
 		return err
 	}
-	return bls.Verify(suite, public.Eval(i).V, msg, s.Value())
+	return s.bls.Verify(public.Eval(i).V, msg, sh.Value())
 }
 
 // Recover reconstructs the full BLS signature S = x * H(m) from a threshold t
 // of signature shares Si using Lagrange interpolation. The full signature S
 // can be verified through the regular BLS verification routine using the
 // shared public key X. The shared public key can be computed by evaluating the
-// public sharing polynomial at index 0.
-func Recover(suite pairing.Suite, public *share.PubPoly, msg []byte, sigs [][]byte, t, n int) ([]byte, error) {
-	pubShares := make([]*share.PubShare, 0)
-	for _, sig := range sigs {
-		s := SigShare(sig)
-		i, err := s.Index()
-		if err != nil {
-			return nil, err
-		}
-		if err = bls.Verify(suite, public.Eval(i).V, msg, s.Value()); err != nil {
-			return nil, err
+// public sharing polynomial at index 0. Invalid shares are skipped, see
+// RecoverRobust.
+func (s *ThresholdScheme) Recover(public *share.PubPoly, msg []byte, sigs [][]byte, t, n int) ([]byte, error) {
+	sig, _, err := s.RecoverRobust(public, msg, sigs, t, n)
+	return sig, err
+}
+
+// RecoverRobust is like Recover, and also returns the indices of the signers
+// whose shares are invalid, in increasing order. The shares are checked
+// with VerifyShares, and the signature is recovered from the first t valid
+// shares of distinct signers, so that invalid shares cannot prevent the
+// recovery as long as t valid ones are present. The indices are returned
+// even if the recovery fails; malformed shares which do not hold an index
+// are skipped silently.
+func (s *ThresholdScheme) RecoverRobust(public *share.PubPoly, msg []byte, sigs [][]byte, t, n int) ([]byte, []int, error) {
+	valid, invalidPos, err := s.verifyShares(public, msg, sigs)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var invalid []int
+	seen := make(map[int]bool)
+	for _, pos := range invalidPos {
+		sh := SigShare(sigs[pos])
+		if i, err := sh.Index(); err == nil && !seen[i] {
+			seen[i] = true
+			invalid = append(invalid, i)
 		}
-		point := suite.G1().Point()
-		if err := point.UnmarshalBinary(s.Value()); err != nil {
-			return nil, err
+	}
+	sort.Ints(invalid)
+
+	pubShares := make([]*share.PubShare, 0, t)
+	used := make(map[int]bool)
+	for _, e := range valid {
+		if used[e.index] {
+			continue
 		}
-		pubShares = append(pubShares, &share.PubShare{I: i, V: point})
+		used[e.index] = true
+		pubShares = append(pubShares, &share.PubShare{I: e.index, V: e.S})
 		if len(pubShares) >= t {
 			break
 		}
 	}
-	commit, err := share.RecoverCommit(suite.G1(), pubShares, t, n)
+	commit, err := share.RecoverCommit(s.bls.SignatureGroup(), pubShares, t, n)
 	if err != nil {
-		return nil, err
+		return nil, invalid, err
 	}
 	sig, err := commit.MarshalBinary()
 	if err != nil {
-		return nil, err
+		return nil, invalid, err
 	}
-	return sig, nil
+	return sig, invalid, nil
+}
+
+// Sign creates a threshold BLS signature Si = xi * H(m) on the given message m
+// using the provided secret key share xi.
+func Sign(suite pairing.Suite, private *share.PriShare, msg []byte) ([]byte, error) {
+	return NewThresholdSchemeOnG1(suite).Sign(private, msg)
+}
+
+// Verify checks the given threshold BLS signature Si on the message m using
+// the public key share Xi that is associated to the secret key share xi. This
+// public key share Xi can be computed by evaluating the public sharing
+// polynonmial at the share's index i.
+func Verify(suite pairing.Suite, public *share.PubPoly, msg, sig []byte) error {
+	return NewThresholdSchemeOnG1(suite).Verify(public, msg, sig)
+}
+
+// Recover reconstructs the full BLS signature S = x * H(m) from a threshold t
+// of signature shares Si using Lagrange interpolation. The full signature S
+// can be verified through the regular BLS verification routine using the
+// shared public key X. The shared public key can be computed by evaluating the
+// public sharing polynomial at index 0. Invalid shares are skipped.
+func Recover(suite pairing.Suite, public *share.PubPoly, msg []byte, sigs [][]byte, t, n int) ([]byte, error) {
+	return NewThresholdSchemeOnG1(suite).Recover(public, msg, sigs, t, n)
 }
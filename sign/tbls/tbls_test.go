@@ -1,12 +1,16 @@
 package tbls
 
 import (
+	"bytes"
+	"crypto/cipher"
 	"testing"
 
 	"github.com/stretchr/testify/require"
+	"go.dedis.ch/kyber/v3/pairing"
 	"go.dedis.ch/kyber/v3/pairing/bn256"
 	"go.dedis.ch/kyber/v3/share"
 	"go.dedis.ch/kyber/v3/sign/bls"
+	"go.dedis.ch/kyber/v3/util/random"
 )
 
 func TestTBLS(test *testing.T) {
@@ -29,3 +33,163 @@ func TestTBLS(test *testing.T) {
 	err = bls.Verify(suite, pubPoly.Commit(), msg, sig)
 	require.Nil(test, err)
 }
+
+func TestTBLSOnG2(test *testing.T) {
+	msg := []byte("Hello threshold Boneh-Lynn-Shacham")
+	suite := bn256.NewSuite()
+	scheme := NewThresholdSchemeOnG2(suite)
+	n := 10
+	t := n/2 + 1
+	secret := suite.G1().Scalar().Pick(suite.RandomStream())
+	priPoly := share.NewPriPoly(suite.G1(), t, secret, suite.RandomStream())
+	pubPoly := priPoly.Commit(suite.G1().Point().Base())
+	sigShares := make([][]byte, 0)
+	for _, x := range priPoly.Shares(n) {
+		sig, err := scheme.Sign(x, msg)
+		require.Nil(test, err)
+		require.Nil(test, scheme.Verify(pubPoly, msg, sig))
+		sigShares = append(sigShares, sig)
+	}
+	sig, err := scheme.Recover(pubPoly, msg, sigShares[2:], t, n)
+	require.Nil(test, err)
+	require.Len(test, sig, suite.G2().PointLen())
+	require.Nil(test, scheme.Scheme().Verify(pubPoly.Commit(), msg, sig))
+	require.Nil(test, bls.NewSchemeOnG2(suite).Verify(pubPoly.Commit(), msg, sig))
+}
+
+func TestTBLSRecoverRobust(test *testing.T) {
+	msg := []byte("Hello threshold Boneh-Lynn-Shacham")
+	suite := bn256.NewSuite()
+	for _, scheme := range []*ThresholdScheme{NewThresholdSchemeOnG1(suite), NewThresholdSchemeOnG2(suite)} {
+		g := scheme.Scheme().KeyGroup()
+		n := 10
+		t := n/2 + 1
+		priPoly := share.NewPriPoly(g, t, nil, suite.RandomStream())
+		pubPoly := priPoly.Commit(g.Point().Base())
+		sigShares := make([][]byte, 0)
+		for _, x := range priPoly.Shares(n) {
+			sig, err := scheme.Sign(x, msg)
+			require.Nil(test, err)
+			sigShares = append(sigShares, sig)
+		}
+		invalid, err := scheme.VerifyShares(pubPoly, msg, sigShares)
+		require.Nil(test, err)
+		require.Empty(test, invalid)
+
+		// a share of another message, a share with a wrong index, a
+		// truncated share and a duplicated share
+		other, err := scheme.Sign(priPoly.Eval(1), []byte("other"))
+		require.Nil(test, err)
+		sigShares[1] = other
+		sigShares[2] = append([]byte{0, 3}, sigShares[2][2:]...)
+		sigShares[4] = sigShares[4][:20]
+		sigShares[5] = sigShares[6]
+
+		invalid, err = scheme.VerifyShares(pubPoly, msg, sigShares)
+		require.Nil(test, err)
+		require.Equal(test, []int{1, 2, 4}, invalid)
+
+		// 6 valid shares remain from distinct signers
+		sig, bad, err := scheme.RecoverRobust(pubPoly, msg, sigShares, t, n)
+		require.Nil(test, err)
+		require.Equal(test, []int{1, 3, 4}, bad)
+		require.Nil(test, scheme.Scheme().Verify(pubPoly.Commit(), msg, sig))
+
+		_, bad, err = scheme.RecoverRobust(pubPoly, msg, sigShares[:9], t, n)
+		require.Error(test, err)
+		require.Equal(test, []int{1, 3, 4}, bad)
+	}
+
+	// the package-level Recover skips invalid shares
+	n, t := 5, 3
+	priPoly := share.NewPriPoly(suite.G2(), t, nil, suite.RandomStream())
+	pubPoly := priPoly.Commit(suite.G2().Point().Base())
+	var sigShares [][]byte
+	for _, x := range priPoly.Shares(n) {
+		sig, err := Sign(suite, x, msg)
+		require.Nil(test, err)
+		sigShares = append(sigShares, sig)
+	}
+	sigShares[0] = sigShares[0][:10]
+	sig, err := Recover(suite, pubPoly, msg, sigShares, t, n)
+	require.Nil(test, err)
+	require.Nil(test, bls.Verify(suite, pubPoly.Commit(), msg, sig))
+}
+
+// fixedSuite is a pairing suite whose random stream is predictable.
+type fixedSuite struct {
+	pairing.Suite
+}
+
+func (fixedSuite) RandomStream() cipher.Stream {
+	return random.FromReader(bytes.NewReader(bytes.Repeat([]byte{1}, 1<<16)))
+}
+
+func TestTBLSVerifySharesOffsetting(test *testing.T) {
+	msg := []byte("Hello threshold Boneh-Lynn-Shacham")
+	suite := fixedSuite{bn256.NewSuite()}
+	scheme := NewThresholdSchemeOnG1(suite)
+	n, t := 5, 3
+	priPoly := share.NewPriPoly(suite.G2(), t, nil, random.New())
+	pubPoly := priPoly.Commit(suite.G2().Point().Base())
+	var sigShares [][]byte
+	for _, x := range priPoly.Shares(n) {
+		sig, err := scheme.Sign(x, msg)
+		require.Nil(test, err)
+		sigShares = append(sigShares, sig)
+	}
+
+	// the errors of two shares cancel out for equal coefficients
+	D := suite.G1().Point().Pick(random.New())
+	for i := 0; i < 2; i++ {
+		sh := SigShare(sigShares[i])
+		S := suite.G1().Point()
+		require.Nil(test, S.UnmarshalBinary(sh.Value()))
+		if i == 0 {
+			S.Add(S, D)
+		} else {
+			S.Sub(S, D)
+		}
+		buf, err := S.MarshalBinary()
+		require.Nil(test, err)
+		sigShares[i] = append(sigShares[i][:2:2], buf...)
+	}
+
+	invalid, err := scheme.VerifyShares(pubPoly, msg, sigShares)
+	require.Nil(test, err)
+	require.Equal(test, []int{0, 1}, invalid)
+	sig, bad, err := scheme.RecoverRobust(pubPoly, msg, sigShares, t, n)
+	require.Nil(test, err)
+	require.Equal(test, []int{0, 1}, bad)
+	require.Nil(test, scheme.Scheme().Verify(pubPoly.Commit(), msg, sig))
+}
+
+func BenchmarkTBLSVerifyShares(b *testing.B) {
+	msg := []byte("Hello threshold Boneh-Lynn-Shacham")
+	suite := bn256.NewSuite()
+	n := 64
+	t := n/2 + 1
+	priPoly := share.NewPriPoly(suite.G2(), t, nil, suite.RandomStream())
+	pubPoly := priPoly.Commit(suite.G2().Point().Base())
+	sigShares := make([][]byte, 0)
+	for _, x := range priPoly.Shares(n) {
+		sig, err := Sign(suite, x, msg)
+		require.Nil(b, err)
+		sigShares = append(sigShares, sig)
+	}
+	scheme := NewThresholdSchemeOnG1(suite)
+	b.Run("Verify", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			for _, sig := range sigShares {
+				require.Nil(b, scheme.Verify(pubPoly, msg, sig))
+			}
+		}
+	})
+	b.Run("VerifyShares", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			invalid, err := scheme.VerifyShares(pubPoly, msg, sigShares)
+			require.Nil(b, err)
+			require.Empty(b, invalid)
+		}
+	})
+}
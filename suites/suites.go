@@ -5,6 +5,7 @@
 package suites
 
 import (
+	"crypto/cipher"
 	"errors"
 	"strings"
 
@@ -54,6 +55,44 @@ func MustFind(name string) Suite {
 	return s
 }
 
+// xofSuite wraps a Suite so that its XOF method uses another
+// implementation.
+type xofSuite struct {
+	Suite
+	newXOF func(seed []byte) kyber.XOF
+}
+
+func (s *xofSuite) XOF(seed []byte) kyber.XOF {
+	return s.newXOF(seed)
+}
+
+// WithXOF returns a suite that behaves like s, except that its XOF method
+// returns the XOFs created by newXOF, for instance k12.New or blake3.New
+// from the packages under go.dedis.ch/kyber/v3/xof.
+func WithXOF(s Suite, newXOF func(seed []byte) kyber.XOF) Suite {
+	return &xofSuite{Suite: s, newXOF: newXOF}
+}
+
+// randSuite wraps a Suite so that its RandomStream method returns a given
+// stream.
+type randSuite struct {
+	Suite
+	r cipher.Stream
+}
+
+func (s *randSuite) RandomStream() cipher.Stream {
+	return s.r
+}
+
+// WithRandomStream returns a suite that behaves like s, except that its
+// RandomStream method returns r. Every protocol picking its randomness
+// from the suite then draws it from r, which can be a deterministic
+// generator such as random.NewHMACDRBG to replay a run, or
+// random.FromReader to feed it a known sequence of bytes.
+func WithRandomStream(s Suite, r cipher.Stream) Suite {
+	return &randSuite{Suite: s, r: r}
+}
+
 // RequireConstantTime causes all future calls to Find and MustFind to only
 // search for suites where the implementation is constant time.
 // It should be called in an init() function for the main package
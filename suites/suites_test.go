@@ -1,9 +1,15 @@
 package suites
 
 import (
+	"bytes"
 	"testing"
 
 	"github.com/stretchr/testify/require"
+	"go.dedis.ch/kyber/v3/proof/dleq"
+	"go.dedis.ch/kyber/v3/sign/schnorr"
+	"go.dedis.ch/kyber/v3/util/random"
+	"go.dedis.ch/kyber/v3/xof/blake2xb"
+	"go.dedis.ch/kyber/v3/xof/k12"
 )
 
 func TestSuites_Find(t *testing.T) {
@@ -38,3 +44,51 @@ func TestSuites_ConstTime(t *testing.T) {
 	require.NoError(t, err)
 	require.NotNil(t, s)
 }
+
+func TestSuites_WithXOF(t *testing.T) {
+	s := WithXOF(MustFind("ed25519"), k12.New)
+	require.Equal(t, "Ed25519", s.String())
+
+	seed := []byte("seed")
+	buf1 := make([]byte, 32)
+	buf2 := make([]byte, 32)
+	s.XOF(seed).Read(buf1)
+	k12.New(seed).Read(buf2)
+	require.Equal(t, buf1, buf2)
+
+	MustFind("ed25519").XOF(seed).Read(buf1)
+	blake2xb.New(seed).Read(buf2)
+	require.Equal(t, buf1, buf2)
+}
+
+func TestSuites_WithRandomStream(t *testing.T) {
+	s := MustFind("Ed25519")
+	seed := []byte("some seed of at least thirty-two bytes")
+	s1 := WithRandomStream(s, random.NewChaCha20DRBG(seed))
+	s2 := WithRandomStream(s, random.NewChaCha20DRBG(seed))
+	require.True(t, s1.Scalar().Pick(s1.RandomStream()).Equal(s2.Scalar().Pick(s2.RandomStream())))
+	require.Equal(t, s.String(), s1.String())
+
+	// A known sequence of bytes can be fed as is.
+	b := make([]byte, 4)
+	WithRandomStream(s, random.FromReader(bytes.NewReader([]byte{1, 2, 3, 4}))).RandomStream().XORKeyStream(b, b)
+	require.Equal(t, []byte{1, 2, 3, 4}, b)
+}
+
+func TestSuites_WithRandomStreamReplay(t *testing.T) {
+	seed := []byte("some seed of at least thirty-two bytes")
+	run := func() ([]byte, *dleq.Proof) {
+		s := WithRandomStream(MustFind("Ed25519"), random.NewHMACDRBG(seed, nil, nil))
+		x := s.Scalar().Pick(s.RandomStream())
+		sig, err := schnorr.Sign(s, x, []byte("message"))
+		require.NoError(t, err)
+		proof, _, _, err := dleq.NewDLEQProof(s, s.Point().Base(), s.Point().Pick(s.RandomStream()), x)
+		require.NoError(t, err)
+		return sig, proof
+	}
+	sig1, proof1 := run()
+	sig2, proof2 := run()
+	require.Equal(t, sig1, sig2)
+	require.True(t, proof1.C.Equal(proof2.C))
+	require.True(t, proof1.R.Equal(proof2.R))
+}
@@ -0,0 +1,151 @@
+package random
+
+import (
+	"crypto/sha256"
+	"encoding/binary"
+	"io"
+	"math/bits"
+	"sync"
+)
+
+const chachaKeySize = 32
+
+// chachaBlock computes the ChaCha20 block function of RFC 8439, section
+// 2.3, and writes the resulting 64 bytes to out.
+func chachaBlock(out *[64]byte, key *[chachaKeySize]byte, counter uint32, nonce *[12]byte) {
+	var s, x [16]uint32
+	s[0], s[1], s[2], s[3] = 0x61707865, 0x3320646e, 0x79622d32, 0x6b206574
+	for i := 0; i < 8; i++ {
+		s[4+i] = binary.LittleEndian.Uint32(key[4*i:])
+	}
+	s[12] = counter
+	for i := 0; i < 3; i++ {
+		s[13+i] = binary.LittleEndian.Uint32(nonce[4*i:])
+	}
+	x = s
+	qr := func(a, b, c, d int) {
+		x[a] += x[b]
+		x[d] = bits.RotateLeft32(x[d]^x[a], 16)
+		x[c] += x[d]
+		x[b] = bits.RotateLeft32(x[b]^x[c], 12)
+		x[a] += x[b]
+		x[d] = bits.RotateLeft32(x[d]^x[a], 8)
+		x[c] += x[d]
+		x[b] = bits.RotateLeft32(x[b]^x[c], 7)
+	}
+	for i := 0; i < 10; i++ {
+		qr(0, 4, 8, 12)
+		qr(1, 5, 9, 13)
+		qr(2, 6, 10, 14)
+		qr(3, 7, 11, 15)
+		qr(0, 5, 10, 15)
+		qr(1, 6, 11, 12)
+		qr(2, 7, 8, 13)
+		qr(3, 4, 9, 14)
+	}
+	for i := range x {
+		binary.LittleEndian.PutUint32(out[4*i:], x[i]+s[i])
+	}
+}
+
+// ChaCha20DRBG is a deterministic random bit generator built on the
+// ChaCha20 stream cipher. Each request is answered with the keystream of
+// the current key, whose first 32 bytes replace the key afterwards, so that
+// a compromise of the state does not reveal earlier outputs. It implements
+// DRBG and is safe for concurrent use.
+type ChaCha20DRBG struct {
+	mu     sync.Mutex
+	key    [chachaKeySize]byte
+	source io.Reader
+}
+
+// NewChaCha20DRBG returns a ChaCha20DRBG whose key is derived from seed,
+// which should hold at least 32 bytes of entropy.
+func NewChaCha20DRBG(seed []byte) *ChaCha20DRBG {
+	d := &ChaCha20DRBG{}
+	d.rekey([]byte("seed"), seed, nil)
+	return d
+}
+
+// rekey derives the new key from the current one and the given inputs.
+func (d *ChaCha20DRBG) rekey(op, entropy, additional []byte) {
+	h := sha256.New()
+	var l [8]byte
+	for _, b := range [][]byte{op, d.key[:], entropy, additional} {
+		binary.LittleEndian.PutUint64(l[:], uint64(len(b)))
+		h.Write(l[:])
+		h.Write(b)
+	}
+	h.Sum(d.key[:0])
+}
+
+// Reseed implements DRBG.
+func (d *ChaCha20DRBG) Reseed(entropy, additional []byte) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.rekey([]byte("reseed"), entropy, additional)
+}
+
+// SetPredictionResistance implements DRBG.
+func (d *ChaCha20DRBG) SetPredictionResistance(source io.Reader) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.source = source
+}
+
+// Generate fills out with the output of the generator, after mixing in the
+// optional additional input.
+func (d *ChaCha20DRBG) Generate(out, additional []byte) error {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if d.source != nil {
+		entropy, err := readPredictionEntropy(d.source)
+		if err != nil {
+			return err
+		}
+		d.rekey([]byte("reseed"), entropy, additional)
+	} else if len(additional) > 0 {
+		d.rekey([]byte("additional"), nil, additional)
+	}
+
+	var nonce [12]byte
+	var block [64]byte
+	var counter uint32
+	chachaBlock(&block, &d.key, counter, &nonce)
+	next := block
+	n := copy(out, block[chachaKeySize:])
+	for n < len(out) {
+		counter++
+		if counter == 0 {
+			panic("random: ChaCha20DRBG request too large")
+		}
+		chachaBlock(&block, &d.key, counter, &nonce)
+		n += copy(out[n:], block[:])
+	}
+	copy(d.key[:], next[:chachaKeySize])
+	return nil
+}
+
+// Read implements io.Reader as a request without additional input.
+func (d *ChaCha20DRBG) Read(b []byte) (int, error) {
+	if err := d.Generate(b, nil); err != nil {
+		return 0, err
+	}
+	return len(b), nil
+}
+
+// XORKeyStream implements cipher.Stream.
+func (d *ChaCha20DRBG) XORKeyStream(dst, src []byte) {
+	xorKeyStream(d, dst, src)
+}
+
+// Fork implements DRBG.
+func (d *ChaCha20DRBG) Fork(label []byte) DRBG {
+	seed := make([]byte, chachaKeySize)
+	if err := d.Generate(seed, append([]byte("fork"), label...)); err != nil {
+		panic(err.Error())
+	}
+	child := &ChaCha20DRBG{}
+	child.rekey([]byte("fork"), seed, label)
+	return child
+}
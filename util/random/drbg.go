@@ -0,0 +1,76 @@
+package random
+
+import (
+	"crypto/cipher"
+	"errors"
+	"io"
+)
+
+// DRBG is a deterministic random bit generator: a cipher.Stream whose
+// output is entirely determined by its seed, the calls to Reseed and the
+// sequence of requests made to it. Two generators created with the same
+// seed and used the same way produce the same bits, which makes them
+// suitable for reproducible tests, simulations and deterministic nonce
+// derivation.
+//
+// The output of a DRBG depends on how it is consumed: each call to Read or
+// XORKeyStream is a separate request, so that reading 64 bytes at once
+// does not produce the same bits as reading 32 bytes twice.
+type DRBG interface {
+	// XORKeyStream XORs src with the output of the generator. It panics if
+	// the generator fails, see Read.
+	cipher.Stream
+
+	// Read fills its argument with the output of the generator. It returns
+	// an error if the generator must be reseeded or if its prediction
+	// resistance source fails.
+	io.Reader
+
+	// Reseed mixes entropy and additional input into the state of the
+	// generator. Either can be empty.
+	Reseed(entropy, additional []byte)
+
+	// SetPredictionResistance makes the generator reseed itself with
+	// fresh entropy read from source before every request, so that its
+	// output stays unpredictable even if its state is compromised. A nil
+	// source disables prediction resistance.
+	SetPredictionResistance(source io.Reader)
+
+	// Fork derives a new generator, independent from the parent, whose
+	// output is determined by the state of the parent and the label. The
+	// state of the parent advances, so forking twice with the same label
+	// yields different generators. The child does not inherit the
+	// prediction resistance source of the parent.
+	Fork(label []byte) DRBG
+}
+
+// ErrReseedRequired is returned by a DRBG that produced the maximum number
+// of requests allowed between two reseeds.
+var ErrReseedRequired = errors.New("random: DRBG must be reseeded")
+
+// predictionEntropySize is the number of bytes read from the prediction
+// resistance source before each request.
+const predictionEntropySize = 32
+
+// readPredictionEntropy reads fresh entropy from source.
+func readPredictionEntropy(source io.Reader) ([]byte, error) {
+	entropy := make([]byte, predictionEntropySize)
+	if _, err := io.ReadFull(source, entropy); err != nil {
+		return nil, errors.New("random: prediction resistance source failed: " + err.Error())
+	}
+	return entropy, nil
+}
+
+// xorKeyStream implements cipher.Stream on top of a DRBG's Read method.
+func xorKeyStream(r io.Reader, dst, src []byte) {
+	if len(dst) < len(src) {
+		panic("XORKeyStream: dst too short")
+	}
+	key := make([]byte, len(src))
+	if _, err := io.ReadFull(r, key); err != nil {
+		panic(err.Error())
+	}
+	for i := range src {
+		dst[i] = src[i] ^ key[i]
+	}
+}
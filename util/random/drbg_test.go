@@ -0,0 +1,145 @@
+package random
+
+import (
+	"bytes"
+	"encoding/hex"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func unhex(s string) []byte {
+	b, err := hex.DecodeString(s)
+	if err != nil {
+		panic(err)
+	}
+	return b
+}
+
+// From the NIST CAVP HMAC_DRBG test vectors, [SHA-256], no prediction
+// resistance, no reseed, COUNT = 0: the output is the result of the second
+// generate call.
+func TestHMACDRBGVector(t *testing.T) {
+	d := NewHMACDRBG(
+		unhex("ca851911349384bffe89de1cbdc46e6831e44d34a4fb935ee285dd14b71a7488"),
+		unhex("659ba96c601dc69fc902940805ec0ca8"), nil)
+	out := make([]byte, 128)
+	require.NoError(t, d.Generate(out, nil))
+	require.NoError(t, d.Generate(out, nil))
+	require.Equal(t, "e528e9abf2dece54d47c7e75e5fe302149f817ea9fb4bee6f4199697d04d5b89"+
+		"d54fbb978a15b5c443c9ec21036d2460b6f73ebad0dc2aba6e624abf07745bc1"+
+		"07694bb7547bb0995f70de25d6b29e2d3011bb19d27676c07162c8b5ccde0668"+
+		"961df86803482cb37ed6d5c0bb8d50cf1f50d476aa0458bdaba806f48be9dcb8",
+		hex.EncodeToString(out))
+}
+
+// From RFC 8439, section 2.3.2.
+func TestChaChaBlock(t *testing.T) {
+	var key [chachaKeySize]byte
+	for i := range key {
+		key[i] = byte(i)
+	}
+	nonce := [12]byte{0, 0, 0, 9, 0, 0, 0, 0x4a}
+	var out [64]byte
+	chachaBlock(&out, &key, 1, &nonce)
+	require.Equal(t, "10f1e7e4d13b5915500fdd1fa32071c4c7d1f4c733c068030422aa9ac3d46c4e"+
+		"d2826446079faa0914c2d705d98b02a2b5129cd1de164eb9cbd083e8a2503c4e",
+		hex.EncodeToString(out[:]))
+}
+
+var drbgs = map[string]func(seed []byte) DRBG{
+	"hmac":     func(seed []byte) DRBG { return NewHMACDRBG(seed, nil, nil) },
+	"chacha20": func(seed []byte) DRBG { return NewChaCha20DRBG(seed) },
+}
+
+func read(d DRBG, n int) []byte {
+	b := make([]byte, n)
+	d.XORKeyStream(b, b)
+	return b
+}
+
+func TestDRBGDeterminism(t *testing.T) {
+	for name, newDRBG := range drbgs {
+		seed := []byte("some seed of at least thirty-two bytes")
+		d1, d2 := newDRBG(seed), newDRBG(seed)
+		a := read(d1, 100)
+		require.Equal(t, a, read(d2, 100), name)
+		b := read(d1, 100)
+		require.NotEqual(t, a, b, name)
+		require.Equal(t, b, read(d2, 100), name)
+		require.NotEqual(t, a, read(newDRBG([]byte("another seed")), 100), name)
+
+		// Large requests span several internal requests or blocks.
+		require.Equal(t, read(d1, 100000), read(d2, 100000), name)
+
+		d1.Reseed([]byte("entropy"), nil)
+		d2.Reseed([]byte("entropy"), []byte("additional"))
+		require.NotEqual(t, read(d1, 32), read(d2, 32), name)
+	}
+}
+
+func TestDRBGFork(t *testing.T) {
+	for name, newDRBG := range drbgs {
+		seed := []byte("some seed of at least thirty-two bytes")
+		d1, d2 := newDRBG(seed), newDRBG(seed)
+		c1, c2 := d1.Fork([]byte("a")), d2.Fork([]byte("a"))
+		require.Equal(t, read(c1, 32), read(c2, 32), name)
+		// The parents advanced identically.
+		require.Equal(t, read(d1, 32), read(d2, 32), name)
+
+		// Children with different labels, or forked at a different time,
+		// differ from each other and from their parent.
+		b := d1.Fork([]byte("b"))
+		a := d1.Fork([]byte("a"))
+		outA, outB := read(a, 32), read(b, 32)
+		require.NotEqual(t, outA, outB, name)
+		require.NotEqual(t, outA, read(c1, 32), name)
+		require.NotEqual(t, outA, read(d1, 32), name)
+	}
+}
+
+type failingReader struct{}
+
+func (failingReader) Read([]byte) (int, error) {
+	return 0, errors.New("broken source")
+}
+
+func TestDRBGPredictionResistance(t *testing.T) {
+	for name, newDRBG := range drbgs {
+		seed := []byte("some seed of at least thirty-two bytes")
+		d1, d2 := newDRBG(seed), newDRBG(seed)
+		d1.SetPredictionResistance(strings.NewReader(strings.Repeat("a", 64)))
+		d2.SetPredictionResistance(strings.NewReader(strings.Repeat("b", 64)))
+		// The output depends on the fresh entropy even though the states
+		// were identical.
+		require.NotEqual(t, read(d1, 32), read(d2, 32), name)
+
+		d1.SetPredictionResistance(failingReader{})
+		_, err := d1.Read(make([]byte, 32))
+		require.Error(t, err, name)
+		require.Panics(t, func() { read(d1, 32) }, name)
+
+		// The source is consumed at every request: exhausting it fails.
+		d1.SetPredictionResistance(bytes.NewReader(make([]byte, predictionEntropySize)))
+		_, err = d1.Read(make([]byte, 32))
+		require.NoError(t, err, name)
+		_, err = d1.Read(make([]byte, 32))
+		require.Error(t, err, name)
+
+		d1.SetPredictionResistance(nil)
+		_, err = d1.Read(make([]byte, 32))
+		require.NoError(t, err, name)
+	}
+}
+
+func TestHMACDRBGReseedRequired(t *testing.T) {
+	d := NewHMACDRBG([]byte("some seed of at least thirty-two bytes"), nil, nil)
+	d.reseedCounter = hmacReseedInterval + 1
+	_, err := d.Read(make([]byte, 32))
+	require.Equal(t, ErrReseedRequired, err)
+	d.Reseed([]byte("fresh entropy"), nil)
+	_, err = d.Read(make([]byte, 32))
+	require.NoError(t, err)
+}
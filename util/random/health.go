@@ -0,0 +1,135 @@
+package random
+
+import (
+	"fmt"
+	"math"
+)
+
+// The continuous health tests of NIST SP 800-90B, section 4.4, applied to
+// the bytes returned by each reader of a stream created by NewWithHealth.
+// Each byte is a sample.
+
+const (
+	// healthAlpha is the false positive probability of the health tests,
+	// as recommended by SP 800-90B: 2^-20.
+	healthAlpha = 1.0 / (1 << 20)
+
+	// aptWindow is the window size of the adaptive proportion test for
+	// non-binary samples.
+	aptWindow = 512
+
+	// defaultMinEntropy is the min-entropy per byte, in bits, assumed when
+	// none is given. It is deliberately low so that user-provided readers
+	// of modest quality pass, while stuck sources are still caught.
+	defaultMinEntropy = 1
+)
+
+// Names of the health tests, as reported in a HealthError.
+const (
+	RepetitionCountTest    = "repetition count"
+	AdaptiveProportionTest = "adaptive proportion"
+)
+
+// HealthError reports that the output of a reader failed a health test.
+type HealthError struct {
+	// Source is the index of the failing reader, in the order they were
+	// given to NewWithHealth.
+	Source int
+	// Test is the name of the failed test.
+	Test string
+}
+
+func (e *HealthError) Error() string {
+	return fmt.Sprintf("random: reader %d failed the %s health test", e.Source, e.Test)
+}
+
+// healthTest runs the repetition count test and the adaptive proportion
+// test over the samples of one source.
+type healthTest struct {
+	rctCutoff, aptCutoff int
+
+	started bool
+	last    byte // last sample seen
+	run     int  // number of consecutive samples equal to last
+
+	aptFirst byte // first sample of the current window
+	aptCount int  // occurrences of aptFirst in the current window
+	aptSeen  int  // number of samples of the current window
+}
+
+func newHealthTest(minEntropy float64) *healthTest {
+	if minEntropy <= 0 {
+		minEntropy = defaultMinEntropy
+	}
+	if minEntropy > 8 {
+		minEntropy = 8
+	}
+	return &healthTest{
+		rctCutoff: rctCutoff(minEntropy),
+		aptCutoff: aptCutoff(minEntropy),
+	}
+}
+
+// rctCutoff returns the cutoff of the repetition count test, per SP
+// 800-90B, section 4.4.1: 1 + ceil(-log2(alpha) / H).
+func rctCutoff(h float64) int {
+	return 1 + int(math.Ceil(-math.Log2(healthAlpha)/h))
+}
+
+// aptCutoff returns the cutoff of the adaptive proportion test, per SP
+// 800-90B, section 4.4.2: 1 + CRITBINOM(W, 2^-H, 1 - alpha), that is one
+// more than the smallest k such that P(X > k) <= alpha, for X following a
+// binomial distribution with W trials of probability 2^-H.
+func aptCutoff(h float64) int {
+	p := math.Exp2(-h)
+	logPMF := func(k int) float64 {
+		n := float64(aptWindow)
+		lc, _ := math.Lgamma(n + 1)
+		lk, _ := math.Lgamma(float64(k) + 1)
+		lnk, _ := math.Lgamma(n - float64(k) + 1)
+		return lc - lk - lnk + float64(k)*math.Log(p) + (n-float64(k))*math.Log1p(-p)
+	}
+	k := aptWindow
+	tail := 0.0 // P(X > k)
+	for k > 0 {
+		next := tail + math.Exp(logPMF(k))
+		if next > healthAlpha {
+			break
+		}
+		tail = next
+		k--
+	}
+	return k + 1
+}
+
+// check feeds the samples to both tests and returns the name of the first
+// test that fails, or the empty string.
+func (t *healthTest) check(samples []byte) string {
+	for _, s := range samples {
+		if t.started && s == t.last {
+			t.run++
+			if t.run >= t.rctCutoff {
+				return RepetitionCountTest
+			}
+		} else {
+			t.started = true
+			t.last = s
+			t.run = 1
+		}
+
+		if t.aptSeen == 0 {
+			t.aptFirst = s
+			t.aptCount = 1
+		} else if s == t.aptFirst {
+			t.aptCount++
+			if t.aptCount >= t.aptCutoff {
+				return AdaptiveProportionTest
+			}
+		}
+		t.aptSeen++
+		if t.aptSeen == aptWindow {
+			t.aptSeen = 0
+		}
+	}
+	return ""
+}
@@ -0,0 +1,165 @@
+package random
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha256"
+	"hash"
+	"io"
+	"sync"
+)
+
+const (
+	// hmacReseedInterval is the maximum number of requests between two
+	// reseeds of an HMAC_DRBG, per SP 800-90A Rev. 1, Table 2.
+	hmacReseedInterval = 1 << 48
+
+	// hmacMaxRequestSize is the maximum number of bytes produced by a
+	// single Generate call, per SP 800-90A Rev. 1, Table 2.
+	hmacMaxRequestSize = (1 << 19) / 8
+)
+
+// HMACDRBG is the HMAC_DRBG of NIST SP 800-90A Rev. 1. It implements DRBG
+// and is safe for concurrent use.
+type HMACDRBG struct {
+	mu            sync.Mutex
+	newHash       func() hash.Hash
+	k, v          []byte
+	reseedCounter uint64
+	source        io.Reader
+}
+
+// NewHMACDRBG instantiates an HMAC_DRBG based on SHA-256 with the given
+// entropy input, nonce and personalization string. The security of the
+// generator relies on entropy holding at least 32 bytes of entropy;
+// nonce and personalization can be empty.
+func NewHMACDRBG(entropy, nonce, personalization []byte) *HMACDRBG {
+	return NewHMACDRBGWithHash(sha256.New, entropy, nonce, personalization)
+}
+
+// NewHMACDRBGWithHash is like NewHMACDRBG but uses the given hash function.
+func NewHMACDRBGWithHash(h func() hash.Hash, entropy, nonce, personalization []byte) *HMACDRBG {
+	size := h().Size()
+	d := &HMACDRBG{
+		newHash: h,
+		k:       make([]byte, size),
+		v:       bytes.Repeat([]byte{0x01}, size),
+	}
+	d.update(entropy, nonce, personalization)
+	d.reseedCounter = 1
+	return d
+}
+
+func (d *HMACDRBG) mac(data ...[]byte) []byte {
+	m := hmac.New(d.newHash, d.k)
+	for _, b := range data {
+		m.Write(b)
+	}
+	return m.Sum(nil)
+}
+
+// update is HMAC_DRBG_Update of SP 800-90A, section 10.1.2.2. The provided
+// data is the concatenation of the given slices.
+func (d *HMACDRBG) update(data ...[]byte) {
+	var empty = true
+	for _, b := range data {
+		if len(b) > 0 {
+			empty = false
+		}
+	}
+	d.k = d.mac(append([][]byte{d.v, {0x00}}, data...)...)
+	d.v = d.mac(d.v)
+	if empty {
+		return
+	}
+	d.k = d.mac(append([][]byte{d.v, {0x01}}, data...)...)
+	d.v = d.mac(d.v)
+}
+
+// Reseed implements the HMAC_DRBG reseed algorithm of SP 800-90A.
+func (d *HMACDRBG) Reseed(entropy, additional []byte) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.reseed(entropy, additional)
+}
+
+func (d *HMACDRBG) reseed(entropy, additional []byte) {
+	d.update(entropy, additional)
+	d.reseedCounter = 1
+}
+
+// SetPredictionResistance implements DRBG.
+func (d *HMACDRBG) SetPredictionResistance(source io.Reader) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.source = source
+}
+
+// Generate fills out with the output of the generator, after mixing in the
+// optional additional input, as specified by the HMAC_DRBG generate
+// algorithm. Requests larger than the maximum request size of SP 800-90A
+// are split into several requests.
+func (d *HMACDRBG) Generate(out, additional []byte) error {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	for {
+		n := len(out)
+		if n > hmacMaxRequestSize {
+			n = hmacMaxRequestSize
+		}
+		if err := d.generate(out[:n], additional); err != nil {
+			return err
+		}
+		out = out[n:]
+		if len(out) == 0 {
+			return nil
+		}
+	}
+}
+
+func (d *HMACDRBG) generate(out, additional []byte) error {
+	if d.source != nil {
+		entropy, err := readPredictionEntropy(d.source)
+		if err != nil {
+			return err
+		}
+		d.reseed(entropy, additional)
+		additional = nil
+	}
+	if d.reseedCounter > hmacReseedInterval {
+		return ErrReseedRequired
+	}
+	if len(additional) > 0 {
+		d.update(additional)
+	}
+	for i := 0; i < len(out); {
+		d.v = d.mac(d.v)
+		i += copy(out[i:], d.v)
+	}
+	d.update(additional)
+	d.reseedCounter++
+	return nil
+}
+
+// Read implements io.Reader as a request without additional input.
+func (d *HMACDRBG) Read(b []byte) (int, error) {
+	if err := d.Generate(b, nil); err != nil {
+		return 0, err
+	}
+	return len(b), nil
+}
+
+// XORKeyStream implements cipher.Stream.
+func (d *HMACDRBG) XORKeyStream(dst, src []byte) {
+	xorKeyStream(d, dst, src)
+}
+
+// Fork implements DRBG. The child is instantiated with entropy generated by
+// the parent, and the label as personalization string.
+func (d *HMACDRBG) Fork(label []byte) DRBG {
+	seed := make([]byte, d.newHash().Size())
+	if err := d.Generate(seed, append([]byte("fork"), label...)); err != nil {
+		panic(err.Error())
+	}
+	return NewHMACDRBGWithHash(d.newHash, seed, nil, label)
+}
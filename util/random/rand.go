@@ -7,8 +7,10 @@ import (
 	"crypto/cipher"
 	"crypto/rand"
 	"crypto/sha256"
+	"errors"
 	"io"
 	"math/big"
+	"sync"
 
 	"go.dedis.ch/kyber/v3/xof/blake2xb"
 )
@@ -49,15 +51,66 @@ func Bytes(b []byte, rand cipher.Stream) {
 	rand.XORKeyStream(b, b)
 }
 
+// ErrAllReadersFailed is returned, or used as panic value, when none of the
+// readers of a stream provided the expected amount of data.
+var ErrAllReadersFailed = errors.New("random: all readers failed")
+
+// HealthPolicy tells a stream what to do when one of its readers fails a
+// health test.
+type HealthPolicy int
+
+const (
+	// FailClosed makes the stream unusable as soon as any of its readers
+	// fails a health test: every subsequent request returns, or panics
+	// with, the corresponding *HealthError.
+	FailClosed HealthPolicy = iota
+	// DropReader discards the output of a reader that failed a health test
+	// and stops reading from it. The stream fails with
+	// ErrAllReadersFailed once no reader remains.
+	DropReader
+)
+
+// HealthConfig configures the continuous health tests run on the readers
+// of a stream.
+type HealthConfig struct {
+	// MinEntropy is the assessed min-entropy of the readers, in bits per
+	// byte, between 0 and 8, from which the cutoffs of the tests are
+	// derived. Zero selects a conservative default of 1 bit per byte.
+	MinEntropy float64
+	// Policy is applied when a reader fails a test.
+	Policy HealthPolicy
+	// Monitor, if not nil, is called with every health test failure. It
+	// must not use the stream.
+	Monitor func(*HealthError)
+}
+
+// Stream is a cipher.Stream gathering entropy from a set of readers, as
+// returned by NewWithHealth. XORKeyStream panics when the stream has
+// failed; Read returns the error instead.
+type Stream interface {
+	cipher.Stream
+	io.Reader
+	// Err returns the error that made the stream fail permanently, or nil.
+	Err() error
+}
+
 type randstream struct {
 	Readers []io.Reader
-}
 
-func (r *randstream) XORKeyStream(dst, src []byte) {
+	mu      sync.Mutex
+	config  HealthConfig
+	health  []*healthTest
+	dropped []bool
+	err     error
+}
 
-	l := len(dst)
-	if len(src) != l {
-		panic("XORKeyStream: mismatched buffer lengths")
+// seed gathers data from the readers, runs the health tests on it and
+// returns the hash of the accepted data.
+func (r *randstream) seed() ([]byte, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.err != nil {
+		return nil, r.err
 	}
 
 	// readerBytes is how many bytes we expect from each source
@@ -66,37 +119,145 @@ func (r *randstream) XORKeyStream(dst, src []byte) {
 	// try to read readerBytes bytes from all readers and write them in a buffer
 	var b bytes.Buffer
 	var nerr int
+	var failures []*HealthError
 	buff := make([]byte, readerBytes)
-	for _, reader := range r.Readers {
+	for i, reader := range r.Readers {
+		if r.dropped[i] {
+			nerr++
+			continue
+		}
 		n, err := io.ReadFull(reader, buff)
+		if test := r.check(i, buff[:n]); test != "" {
+			herr := &HealthError{Source: i, Test: test}
+			failures = append(failures, herr)
+			if r.config.Policy == FailClosed {
+				r.err = herr
+				break
+			}
+			r.dropped[i] = true
+			nerr++
+			continue
+		}
 		if err != nil {
 			nerr++
 		}
 		b.Write(buff[:n])
 	}
+	if r.config.Monitor != nil {
+		for _, f := range failures {
+			r.config.Monitor(f)
+		}
+	}
+	if r.err != nil {
+		return nil, r.err
+	}
 
 	// we are ok with few sources being insecure (i.e., providing less than
 	// readerBytes bytes), but not all of them
 	if nerr == len(r.Readers) {
-		panic("all readers failed")
+		if r.config.Policy == DropReader && len(failures) > 0 {
+			r.err = ErrAllReadersFailed
+		}
+		return nil, ErrAllReadersFailed
 	}
 
-	// create the XOF output, with hash of collected data as seed
+	// hash the collected data to get the seed of the XOF
 	h := sha256.New()
 	h.Write(b.Bytes())
-	seed := h.Sum(nil)
+	return h.Sum(nil), nil
+}
+
+// check runs the health tests of reader i, if any, on its samples.
+func (r *randstream) check(i int, samples []byte) string {
+	if r.health == nil {
+		return ""
+	}
+	return r.health[i].check(samples)
+}
+
+func (r *randstream) XORKeyStream(dst, src []byte) {
+	l := len(dst)
+	if len(src) != l {
+		panic("XORKeyStream: mismatched buffer lengths")
+	}
+	seed, err := r.seed()
+	if err != nil {
+		panic(err)
+	}
 	blake2 := blake2xb.New(seed)
 	blake2.XORKeyStream(dst, src)
 }
 
+// Read fills b with random bytes. It returns an error if the readers
+// failed or did not pass the health tests.
+func (r *randstream) Read(b []byte) (int, error) {
+	seed, err := r.seed()
+	if err != nil {
+		return 0, err
+	}
+	return blake2xb.New(seed).Read(b)
+}
+
+// Err implements Stream.
+func (r *randstream) Err() error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.err
+}
+
+type readerStream struct {
+	r io.Reader
+}
+
+func (s *readerStream) XORKeyStream(dst, src []byte) {
+	xorKeyStream(s.r, dst, src)
+}
+
+// FromReader returns a cipher.Stream whose key stream is read directly
+// from r, without any mixing or health testing: the bytes of r are XORed
+// into the output as is. It is meant to feed a known sequence of bytes to
+// a protocol, for instance to replay it from a recorded transcript, and
+// panics if r fails or runs out of data.
+func FromReader(r io.Reader) cipher.Stream {
+	return &readerStream{r}
+}
+
 // New returns a new cipher.Stream that gets random data from the given
 // readers. If no reader was provided, Go's crypto/rand package is used.
 // Otherwise, for each source, 32 bytes are read. They are concatenated and
 // then hashed, and the resulting hash is used as a seed to a PRNG.
 // The resulting cipher.Stream can be used in multiple threads.
+//
+// The output of the readers is not tested, so that any reader, even a
+// deterministic one, can be used; see NewWithHealth to detect broken
+// entropy sources.
 func New(readers ...io.Reader) cipher.Stream {
 	if len(readers) == 0 {
 		readers = []io.Reader{rand.Reader}
 	}
-	return &randstream{readers}
+	return &randstream{
+		Readers: readers,
+		dropped: make([]bool, len(readers)),
+	}
+}
+
+// NewWithHealth is like New, but continuously checks the output of the
+// readers with the health tests of NIST SP 800-90B configured by config,
+// and returns a Stream that surfaces errors. With the default FailClosed
+// policy, the stream fails, panicking with a *HealthError in
+// XORKeyStream, as soon as a reader gets stuck.
+func NewWithHealth(config HealthConfig, readers ...io.Reader) Stream {
+	if len(readers) == 0 {
+		readers = []io.Reader{rand.Reader}
+	}
+	health := make([]*healthTest, len(readers))
+	for i := range health {
+		health[i] = newHealthTest(config.MinEntropy)
+	}
+	return &randstream{
+		Readers: readers,
+		config:  config,
+		health:  health,
+		dropped: make([]bool, len(readers)),
+	}
 }
@@ -5,6 +5,8 @@ import (
 	"crypto/rand"
 	"strings"
 	"testing"
+
+	"github.com/stretchr/testify/require"
 )
 
 const size = 32
@@ -84,3 +86,101 @@ func TestIncorrectSize(t *testing.T) {
 	dst := make([]byte, size+1)
 	cipher.XORKeyStream(dst, src)
 }
+
+// The cutoffs of SP 800-90B, section 4.4, tables 1 and 2, for alpha = 2^-20.
+func TestHealthCutoffs(t *testing.T) {
+	for _, c := range []struct {
+		h        float64
+		rct, apt int
+	}{{0.5, 41, 410}, {1, 21, 311}, {2, 11, 177}, {4, 6, 62}, {8, 4, 13}} {
+		require.Equal(t, c.rct, rctCutoff(c.h))
+		require.Equal(t, c.apt, aptCutoff(c.h))
+	}
+}
+
+func TestHealthFailClosed(t *testing.T) {
+	var events []*HealthError
+	stuck := bytes.NewReader(make([]byte, 1024))
+	s := NewWithHealth(HealthConfig{
+		Monitor: func(e *HealthError) { events = append(events, e) },
+	}, rand.Reader, stuck)
+
+	_, err := s.Read(make([]byte, size))
+	require.Equal(t, &HealthError{Source: 1, Test: RepetitionCountTest}, err)
+	require.Equal(t, err, s.Err())
+	require.Len(t, events, 1)
+
+	// The stream stays unusable.
+	_, err = s.Read(make([]byte, size))
+	require.Equal(t, s.Err(), err)
+	require.Panics(t, func() { s.XORKeyStream(make([]byte, size), make([]byte, size)) })
+	require.Len(t, events, 1)
+
+	// New does not run the health tests.
+	require.NotPanics(t, func() {
+		New(bytes.NewReader(make([]byte, size))).XORKeyStream(make([]byte, size), make([]byte, size))
+	})
+}
+
+// biasedReader returns a byte which is not repeated consecutively, but
+// appears much more often than it should.
+type biasedReader struct{ i int }
+
+func (r *biasedReader) Read(b []byte) (int, error) {
+	for j := range b {
+		if r.i%2 == 0 {
+			b[j] = 0
+		} else {
+			b[j] = byte(r.i)
+		}
+		r.i++
+	}
+	return len(b), nil
+}
+
+func TestHealthAdaptiveProportion(t *testing.T) {
+	s := NewWithHealth(HealthConfig{MinEntropy: 4}, &biasedReader{})
+	var err error
+	for i := 0; i < aptWindow/size && err == nil; i++ {
+		_, err = s.Read(make([]byte, size))
+	}
+	require.Equal(t, &HealthError{Source: 0, Test: AdaptiveProportionTest}, err)
+}
+
+func TestHealthDropReader(t *testing.T) {
+	var events []*HealthError
+	stuck := bytes.NewReader(make([]byte, 1024))
+	s := NewWithHealth(HealthConfig{
+		Policy:  DropReader,
+		Monitor: func(e *HealthError) { events = append(events, e) },
+	}, stuck, rand.Reader)
+
+	for i := 0; i < 3; i++ {
+		_, err := s.Read(make([]byte, size))
+		require.NoError(t, err)
+	}
+	require.NoError(t, s.Err())
+	require.Equal(t, []*HealthError{{Source: 0, Test: RepetitionCountTest}}, events)
+
+	s = NewWithHealth(HealthConfig{Policy: DropReader}, bytes.NewReader(make([]byte, 1024)))
+	_, err := s.Read(make([]byte, size))
+	require.Equal(t, ErrAllReadersFailed, err)
+	require.Equal(t, ErrAllReadersFailed, s.Err())
+}
+
+func TestHealthUserReader(t *testing.T) {
+	// Text passes the health tests with the default configuration.
+	s := NewWithHealth(HealthConfig{}, strings.NewReader(strings.Repeat("some io.Reader stream to be used for testing. ", 100)))
+	for i := 0; i < 50; i++ {
+		_, err := s.Read(make([]byte, size))
+		require.NoError(t, err)
+	}
+}
+
+func TestFromReader(t *testing.T) {
+	s := FromReader(strings.NewReader("known bytes"))
+	dst := make([]byte, 5)
+	s.XORKeyStream(dst, make([]byte, 5))
+	require.Equal(t, []byte("known"), dst)
+	require.Panics(t, func() { s.XORKeyStream(make([]byte, 10), make([]byte, 10)) })
+}
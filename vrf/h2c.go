@@ -0,0 +1,146 @@
+package vrf
+
+import (
+	"crypto/sha512"
+	"errors"
+	"hash"
+	"math/big"
+
+	"go.dedis.ch/kyber/v3"
+)
+
+// expandMessageXMD is expand_message_xmd of RFC 9380, section 5.3.1.
+func expandMessageXMD(h func() hash.Hash, dst, msg []byte, length int) ([]byte, error) {
+	H := h()
+	bLen := H.Size()
+	ell := (length + bLen - 1) / bLen
+	if ell > 255 || length > 65535 || len(dst) > 255 {
+		return nil, errors.New("vrf: invalid expand_message_xmd parameters")
+	}
+	dstPrime := append(append([]byte{}, dst...), byte(len(dst)))
+
+	// b_0 = H(Z_pad || msg || l_i_b_str || 0x00 || DST_prime)
+	H.Write(make([]byte, H.BlockSize()))
+	H.Write(msg)
+	H.Write([]byte{byte(length >> 8), byte(length), 0})
+	H.Write(dstPrime)
+	b0 := H.Sum(nil)
+
+	// b_i = H(strxor(b_0, b_(i-1)) || I2OSP(i, 1) || DST_prime)
+	out := make([]byte, 0, ell*bLen)
+	bi := make([]byte, bLen)
+	for i := 1; i <= ell; i++ {
+		for j := range bi {
+			bi[j] ^= b0[j]
+		}
+		H.Reset()
+		H.Write(bi)
+		H.Write([]byte{byte(i)})
+		H.Write(dstPrime)
+		bi = H.Sum(nil)
+		out = append(out, bi...)
+	}
+	return out[:length], nil
+}
+
+// the field of curve25519 and edwards25519, and the constants of the maps
+var (
+	fieldP = new(big.Int).Sub(new(big.Int).Lsh(big.NewInt(1), 255), big.NewInt(19))
+	montJ  = big.NewInt(486662)
+	// sqrt(-486664) with sgn0 equal to 0
+	edwardsC1 = func() *big.Int {
+		c := new(big.Int).Sub(fieldP, big.NewInt(486664))
+		return sqrtSgn0(c, 0)
+	}()
+)
+
+// sqrtSgn0 returns the square root of v whose sgn0 is sign, or nil if v is
+// not a square.
+func sqrtSgn0(v *big.Int, sign uint) *big.Int {
+	y := new(big.Int).ModSqrt(v, fieldP)
+	if y == nil {
+		return nil
+	}
+	if y.Bit(0) != sign {
+		y.Sub(fieldP, y).Mod(y, fieldP)
+	}
+	return y
+}
+
+// montgomeryRHS returns x^3 + J*x^2 + x, the right hand side of the equation
+// of curve25519.
+func montgomeryRHS(x *big.Int) *big.Int {
+	g := new(big.Int).Add(x, montJ)
+	g.Mul(g, x).Add(g, big.NewInt(1)).Mul(g, x)
+	return g.Mod(g, fieldP)
+}
+
+// elligator2 is map_to_curve_elligator2 of RFC 9380, section 6.7.1, for
+// curve25519 with Z = 2, followed by the rational map to edwards25519 of
+// section 6.8.2. It returns the affine coordinates of the point.
+func elligator2(u *big.Int) (x, y *big.Int) {
+	// x1 = -J / (1 + Z*u^2), or -J if the denominator is zero
+	tv := new(big.Int).Mul(u, u)
+	tv.Lsh(tv, 1).Add(tv, big.NewInt(1)).Mod(tv, fieldP)
+	x1 := new(big.Int).Neg(montJ)
+	if tv.Sign() != 0 {
+		x1.Mul(x1, tv.ModInverse(tv, fieldP))
+	}
+	x1.Mod(x1, fieldP)
+
+	// pick the abscissa x1 or x2 = -x1 - J with a square right hand side
+	s, t := x1, sqrtSgn0(montgomeryRHS(x1), 1)
+	if t == nil {
+		s = new(big.Int).Add(x1, montJ)
+		s.Neg(s).Mod(s, fieldP)
+		t = sqrtSgn0(montgomeryRHS(s), 0)
+	}
+
+	// (x, y) = (sqrt(-486664)*s/t, (s-1)/(s+1)), or the identity if t or
+	// s+1 is zero
+	den := new(big.Int).Add(s, big.NewInt(1))
+	den.Mod(den, fieldP)
+	if t.Sign() == 0 || den.Sign() == 0 {
+		return big.NewInt(0), big.NewInt(1)
+	}
+	x = new(big.Int).Mul(edwardsC1, s)
+	x.Mul(x, new(big.Int).ModInverse(t, fieldP)).Mod(x, fieldP)
+	y = new(big.Int).Sub(s, big.NewInt(1))
+	y.Mul(y, den.ModInverse(den, fieldP)).Mod(y, fieldP)
+	return x, y
+}
+
+// edwardsPoint returns the point of g with the affine coordinates (x, y).
+func edwardsPoint(g kyber.Group, x, y *big.Int) (kyber.Point, error) {
+	// little-endian y with the sign of x in the top bit
+	buf := make([]byte, 32)
+	yb := y.Bytes()
+	for i, b := range yb {
+		buf[len(yb)-1-i] = b
+	}
+	buf[31] |= byte(x.Bit(0)) << 7
+	P := g.Point()
+	if err := P.UnmarshalBinary(buf); err != nil {
+		return nil, err
+	}
+	return P, nil
+}
+
+// encodeToEdwards25519 is encode_to_curve of the suite
+// edwards25519_XMD:SHA-512_ELL2_NU_ of RFC 9380, which maps the message to a
+// point of the prime order subgroup of edwards25519. It is not constant
+// time, as the inputs of a VRF are public.
+func encodeToEdwards25519(g kyber.Group, dst, msg []byte) (kyber.Point, error) {
+	uniform, err := expandMessageXMD(sha512.New, dst, msg, 48)
+	if err != nil {
+		return nil, err
+	}
+	u := new(big.Int).SetBytes(uniform)
+	x, y := elligator2(u.Mod(u, fieldP))
+	P, err := edwardsPoint(g, x, y)
+	if err != nil {
+		return nil, err
+	}
+	// clear the cofactor
+	return P.Mul(g.Scalar().SetInt64(8), P), nil
+}
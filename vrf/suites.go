@@ -0,0 +1,189 @@
+package vrf
+
+import (
+	"crypto/cipher"
+	"crypto/elliptic"
+	"crypto/sha256"
+	"crypto/sha512"
+	"errors"
+	"math/big"
+
+	"go.dedis.ch/kyber/v3"
+	"go.dedis.ch/kyber/v3/group/edwards25519"
+	"go.dedis.ch/kyber/v3/group/nist"
+	"go.dedis.ch/kyber/v3/util/random"
+)
+
+// suite_string of the suites of RFC 9381
+const (
+	p256TAISuite          = 0x01
+	edwards25519ELL2Suite = 0x04
+)
+
+// Edwards25519SHA512ELL2 returns the suite ECVRF-EDWARDS25519-SHA512-ELL2 of
+// RFC 9381. Its secret keys are the 32-byte seeds of Ed25519 and its public
+// keys are the Ed25519 public keys of the seeds.
+func Edwards25519SHA512ELL2() *Suite {
+	curve := new(edwards25519.Curve)
+	dst := []byte("ECVRF_edwards25519_XMD:SHA-512_ELL2_NU_\x04")
+	return &Suite{
+		group:    curve,
+		id:       edwards25519ELL2Suite,
+		hash:     sha512.New,
+		cLen:     16,
+		ptLen:    32,
+		cofactor: curve.Scalar().SetInt64(8),
+		newKey: func(rand cipher.Stream) ([]byte, error) {
+			return random.Bits(256, false, rand), nil
+		},
+		secret: func(sk []byte) (kyber.Scalar, []byte, error) {
+			if len(sk) != 32 {
+				return nil, nil, errors.New("vrf: secret key of invalid length")
+			}
+			// x and the prefix of the nonces as Ed25519, RFC 8032, 5.1.5
+			x, _, prefix := curve.NewKeyAndSeedWithInput(sk)
+			return x, prefix, nil
+		},
+		nonce: func(prefix, h []byte) kyber.Scalar {
+			// RFC 8032, 5.1.6
+			H := sha512.New()
+			H.Write(prefix)
+			H.Write(h)
+			return curve.Scalar().SetBytes(H.Sum(nil))
+		},
+		encode: func(salt, alpha []byte) (kyber.Point, error) {
+			msg := append(append([]byte{}, salt...), alpha...)
+			return encodeToEdwards25519(curve, dst, msg)
+		},
+		pointToString: func(P kyber.Point) ([]byte, error) {
+			return P.MarshalBinary()
+		},
+		stringToPoint: func(buf []byte) (kyber.Point, error) {
+			P := curve.Point()
+			if err := P.UnmarshalBinary(buf); err != nil {
+				return nil, err
+			}
+			return P, nil
+		},
+	}
+}
+
+// P256SHA256TAI returns the suite ECVRF-P256-SHA256-TAI of RFC 9381, which
+// hashes to the curve by try-and-increment. Its secret keys are the 32-byte
+// big-endian encodings of the secret scalars and its public keys are
+// compressed SEC1 points.
+func P256SHA256TAI() *Suite {
+	g := nist.NewBlakeSHA256P256()
+	params := elliptic.P256().Params()
+	s := &Suite{
+		group:    g,
+		id:       p256TAISuite,
+		hash:     sha256.New,
+		cLen:     16,
+		ptLen:    33,
+		cofactor: g.Scalar().One(),
+		newKey: func(rand cipher.Stream) ([]byte, error) {
+			return g.Scalar().Pick(rand).MarshalBinary()
+		},
+		secret: func(sk []byte) (kyber.Scalar, []byte, error) {
+			x, err := decodeSecret(g, sk)
+			if err != nil {
+				return nil, nil, err
+			}
+			return x, sk, nil
+		},
+		nonce: func(sk, h []byte) kyber.Scalar {
+			return rfc6979(g, params.N, sk, h)
+		},
+		pointToString: func(P kyber.Point) ([]byte, error) {
+			return compressP256(P)
+		},
+		stringToPoint: func(buf []byte) (kyber.Point, error) {
+			return decompressP256(g, params, buf)
+		},
+	}
+	s.encode = func(salt, alpha []byte) (kyber.Point, error) {
+		// ECVRF_encode_to_curve_try_and_increment, RFC 9381, 5.4.1.1
+		for ctr := 0; ctr < 256; ctr++ {
+			H := sha256.New()
+			H.Write([]byte{s.id, encodeFront})
+			H.Write(salt)
+			H.Write(alpha)
+			H.Write([]byte{byte(ctr), domainBack})
+			if P, err := decompressP256(g, params, append([]byte{0x02}, H.Sum(nil)...)); err == nil {
+				return P, nil
+			}
+		}
+		return nil, errors.New("vrf: no point found by try-and-increment")
+	}
+	return s
+}
+
+// rfc6979 returns the nonce of RFC 6979, section 3.2, with SHA-256, for the
+// secret key x and the message m, in the group g of order q of 256 bits.
+func rfc6979(g kyber.Group, q *big.Int, x, m []byte) kyber.Scalar {
+	// bits2octets(H(m))
+	h1 := sha256.Sum256(m)
+	z := new(big.Int).SetBytes(h1[:])
+	h := leftPad(z.Mod(z, q).Bytes(), 32)
+
+	// steps d to h are the instantiation and generation of HMAC_DRBG
+	drbg := random.NewHMACDRBGWithHash(sha256.New, x, h, nil)
+	T := make([]byte, 32)
+	for {
+		drbg.Read(T)
+		k := new(big.Int).SetBytes(T)
+		if k.Sign() > 0 && k.Cmp(q) < 0 {
+			return g.Scalar().SetBytes(T)
+		}
+	}
+}
+
+// compressP256 returns the compressed SEC1 encoding of a point of P-256.
+func compressP256(P kyber.Point) ([]byte, error) {
+	buf, err := P.MarshalBinary()
+	if err != nil {
+		return nil, err
+	}
+	// 0x04 || x || y
+	if len(buf) != 65 {
+		return nil, errors.New("vrf: invalid point encoding")
+	}
+	out := append([]byte{0x02 | buf[64]&1}, buf[1:33]...)
+	return out, nil
+}
+
+// decompressP256 decodes the compressed SEC1 encoding of a point of P-256.
+func decompressP256(g kyber.Group, params *elliptic.CurveParams, buf []byte) (kyber.Point, error) {
+	if len(buf) != 33 || (buf[0] != 0x02 && buf[0] != 0x03) {
+		return nil, errors.New("vrf: invalid point encoding")
+	}
+	x := new(big.Int).SetBytes(buf[1:])
+	if x.Cmp(params.P) >= 0 {
+		return nil, errors.New("vrf: invalid point encoding")
+	}
+	// y^2 = x^3 - 3x + b
+	y2 := new(big.Int).Mul(x, x)
+	y2.Sub(y2, big.NewInt(3)).Mul(y2, x).Add(y2, params.B).Mod(y2, params.P)
+	y := new(big.Int).ModSqrt(y2, params.P)
+	if y == nil {
+		return nil, errors.New("vrf: point not on the curve")
+	}
+	if y.Bit(0) != uint(buf[0]&1) {
+		y.Sub(params.P, y)
+	}
+	uncompressed := append([]byte{0x04}, leftPad(x.Bytes(), 32)...)
+	uncompressed = append(uncompressed, leftPad(y.Bytes(), 32)...)
+	P := g.Point()
+	if err := P.UnmarshalBinary(uncompressed); err != nil {
+		return nil, err
+	}
+	return P, nil
+}
+
+// leftPad pads b with zeros on the left to n bytes.
+func leftPad(b []byte, n int) []byte {
+	out := make([]byte, n)
+	copy(out[n-len(b):], b)
+	return out
+}
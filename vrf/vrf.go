@@ -0,0 +1,311 @@
+// Package vrf implements the elliptic curve verifiable random functions
+// (ECVRF) of RFC 9381, https://www.rfc-editor.org/rfc/rfc9381.
+//
+// A VRF maps an input alpha to a pseudorandom output beta with a secret key,
+// and gives a proof pi that anyone holding the public key can check, so that
+// the output is unpredictable without the secret key but unique and publicly
+// verifiable. This makes VRFs suited to, e.g., leader election, where each
+// node proves its pseudorandom draw for a given round.
+//
+// The proof is a proof of equality of the discrete logarithms of the public
+// key Y = x*B and of Gamma = x*H, with H the input hashed to the curve, as
+// the proofs of the package proof/dleq, in the compact and deterministic
+// encoding of the RFC. The output is the hash of Gamma.
+//
+// The suites ECVRF-EDWARDS25519-SHA512-ELL2 and ECVRF-P256-SHA256-TAI of the
+// RFC are provided, and NewSuite builds the same construction over any
+// kyber.Group whose points can be hashed to, such as the groups of
+// pairing/bn256. Keys and proofs are byte strings, encoded as the RFC
+// specifies.
+package vrf
+
+import (
+	"bytes"
+	"crypto/cipher"
+	"errors"
+	"hash"
+
+	"go.dedis.ch/kyber/v3"
+	"go.dedis.ch/kyber/v3/util/random"
+)
+
+// domain separators of the hashes, as of RFC 9381, section 5
+const (
+	encodeFront    = 0x01
+	challengeFront = 0x02
+	proofFront     = 0x03
+	domainBack     = 0x00
+)
+
+var errInvalidProof = errors.New("vrf: invalid proof")
+
+// Suite is a ciphersuite of ECVRF. It is safe for concurrent use.
+type Suite struct {
+	group    kyber.Group
+	id       byte // suite_string
+	hash     func() hash.Hash
+	cLen     int
+	ptLen    int
+	cofactor kyber.Scalar
+
+	// newKey draws a secret key
+	newKey func(rand cipher.Stream) ([]byte, error)
+	// secret returns the secret scalar and the key of the nonces of sk
+	secret func(sk []byte) (kyber.Scalar, []byte, error)
+	// nonce derives the nonce from the key of the nonces and h_string
+	nonce func(key, h []byte) kyber.Scalar
+	// encode hashes the salt and alpha to a point, encode_to_curve
+	encode func(salt, alpha []byte) (kyber.Point, error)
+	// pointToString and stringToPoint encode and decode points
+	pointToString func(P kyber.Point) ([]byte, error)
+	stringToPoint func(buf []byte) (kyber.Point, error)
+}
+
+// hashablePoint is a point supporting hash-to-curve.
+type hashablePoint interface {
+	Hash([]byte) kyber.Point
+}
+
+// NewSuite returns the ECVRF over the group g with the hash function h,
+// whose input is hashed to the curve with the Hash method of the points of
+// g. The group must have a prime order; the public keys and the proofs use
+// the binary encodings of the points and scalars of g, and the secret keys
+// are encoded scalars. The nonces are derived from the secret key and the
+// input in the manner of RFC 6979. The suite_string of the suite is 0x00.
+func NewSuite(g kyber.Group, h func() hash.Hash) (*Suite, error) {
+	if _, ok := g.Point().(hashablePoint); !ok {
+		return nil, errors.New("vrf: points of the group do not support hash-to-curve")
+	}
+	s := &Suite{
+		group:    g,
+		id:       0x00,
+		hash:     h,
+		cLen:     (g.ScalarLen() + 1) / 2,
+		ptLen:    g.PointLen(),
+		cofactor: g.Scalar().One(),
+		newKey: func(rand cipher.Stream) ([]byte, error) {
+			return g.Scalar().Pick(rand).MarshalBinary()
+		},
+		secret: func(sk []byte) (kyber.Scalar, []byte, error) {
+			x, err := decodeSecret(g, sk)
+			if err != nil {
+				return nil, nil, err
+			}
+			return x, sk, nil
+		},
+		pointToString: func(P kyber.Point) ([]byte, error) {
+			return P.MarshalBinary()
+		},
+		stringToPoint: func(buf []byte) (kyber.Point, error) {
+			P := g.Point()
+			if err := P.UnmarshalBinary(buf); err != nil {
+				return nil, err
+			}
+			return P, nil
+		},
+	}
+	s.nonce = func(key, hString []byte) kyber.Scalar {
+		H := h()
+		H.Write(hString)
+		return g.Scalar().Pick(random.NewHMACDRBGWithHash(h, key, H.Sum(nil), nil))
+	}
+	s.encode = func(salt, alpha []byte) (kyber.Point, error) {
+		buf := append([]byte{s.id, encodeFront}, salt...)
+		buf = append(buf, alpha...)
+		buf = append(buf, domainBack)
+		return g.Point().(hashablePoint).Hash(buf), nil
+	}
+	return s, nil
+}
+
+// Group returns the group of the suite.
+func (s *Suite) Group() kyber.Group {
+	return s.group
+}
+
+// GenerateKey returns a new secret key and its public key, drawn from rand.
+func (s *Suite) GenerateKey(rand cipher.Stream) (sk, pk []byte, err error) {
+	sk, err = s.newKey(rand)
+	if err != nil {
+		return nil, nil, err
+	}
+	pk, err = s.PublicKey(sk)
+	if err != nil {
+		return nil, nil, err
+	}
+	return sk, pk, nil
+}
+
+// PublicKey returns the public key of the secret key sk.
+func (s *Suite) PublicKey(sk []byte) ([]byte, error) {
+	x, _, err := s.secret(sk)
+	if err != nil {
+		return nil, err
+	}
+	return s.pointToString(s.group.Point().Mul(x, nil))
+}
+
+// Prove returns the proof pi of the output of the VRF for the input alpha
+// with the secret key sk, which is ECVRF_prove of RFC 9381.
+func (s *Suite) Prove(sk, alpha []byte) ([]byte, error) {
+	x, nonceKey, err := s.secret(sk)
+	if err != nil {
+		return nil, err
+	}
+	g := s.group
+	Y := g.Point().Mul(x, nil)
+	pk, err := s.pointToString(Y)
+	if err != nil {
+		return nil, err
+	}
+	H, err := s.encode(pk, alpha)
+	if err != nil {
+		return nil, err
+	}
+	hString, err := s.pointToString(H)
+	if err != nil {
+		return nil, err
+	}
+	Gamma := g.Point().Mul(x, H)
+	k := s.nonce(nonceKey, hString)
+	cString, err := s.challenge(Y, H, Gamma, g.Point().Mul(k, nil), g.Point().Mul(k, H))
+	if err != nil {
+		return nil, err
+	}
+
+	// s = k + c*x
+	c := g.Scalar().SetBytes(cString)
+	S := g.Scalar().Add(k, g.Scalar().Mul(c, x))
+
+	gamma, err := s.pointToString(Gamma)
+	if err != nil {
+		return nil, err
+	}
+	sString, err := S.MarshalBinary()
+	if err != nil {
+		return nil, err
+	}
+	pi := append(gamma, cString...)
+	return append(pi, sString...), nil
+}
+
+// Verify checks the proof pi of the output of the VRF for the input alpha
+// under the public key pk, which is ECVRF_verify of RFC 9381 with the
+// validation of the public key. It returns the output beta iff the proof is
+// valid.
+func (s *Suite) Verify(pk, pi, alpha []byte) ([]byte, error) {
+	g := s.group
+	Y, err := s.stringToPoint(pk)
+	if err != nil {
+		return nil, err
+	}
+	if g.Point().Mul(s.cofactor, Y).Equal(g.Point().Null()) {
+		return nil, errors.New("vrf: public key of small order")
+	}
+	Gamma, cString, S, err := s.decodeProof(pi)
+	if err != nil {
+		return nil, err
+	}
+	H, err := s.encode(pk, alpha)
+	if err != nil {
+		return nil, err
+	}
+
+	// U = s*B - c*Y and V = s*H - c*Gamma
+	c := g.Scalar().SetBytes(cString)
+	U := g.Point().Mul(S, nil)
+	U.Sub(U, g.Point().Mul(c, Y))
+	V := g.Point().Mul(S, H)
+	V.Sub(V, g.Point().Mul(c, Gamma))
+	check, err := s.challenge(Y, H, Gamma, U, V)
+	if err != nil {
+		return nil, err
+	}
+	if !bytes.Equal(check, cString) {
+		return nil, errInvalidProof
+	}
+	return s.gammaToHash(Gamma)
+}
+
+// ProofToHash returns the output beta of the VRF from its proof pi, which is
+// ECVRF_proof_to_hash of RFC 9381. It does not check the proof.
+func (s *Suite) ProofToHash(pi []byte) ([]byte, error) {
+	Gamma, _, _, err := s.decodeProof(pi)
+	if err != nil {
+		return nil, err
+	}
+	return s.gammaToHash(Gamma)
+}
+
+func (s *Suite) gammaToHash(Gamma kyber.Point) ([]byte, error) {
+	buf, err := s.pointToString(s.group.Point().Mul(s.cofactor, Gamma))
+	if err != nil {
+		return nil, err
+	}
+	H := s.hash()
+	H.Write([]byte{s.id, proofFront})
+	H.Write(buf)
+	H.Write([]byte{domainBack})
+	return H.Sum(nil), nil
+}
+
+// challenge is ECVRF_challenge_generation of RFC 9381. It returns the
+// challenge as a string of cLen bytes.
+func (s *Suite) challenge(points ...kyber.Point) ([]byte, error) {
+	H := s.hash()
+	H.Write([]byte{s.id, challengeFront})
+	for _, P := range points {
+		buf, err := s.pointToString(P)
+		if err != nil {
+			return nil, err
+		}
+		H.Write(buf)
+	}
+	H.Write([]byte{domainBack})
+	return H.Sum(nil)[:s.cLen], nil
+}
+
+// decodeProof is ECVRF_decode_proof of RFC 9381.
+func (s *Suite) decodeProof(pi []byte) (kyber.Point, []byte, kyber.Scalar, error) {
+	if len(pi) != s.ptLen+s.cLen+s.group.ScalarLen() {
+		return nil, nil, nil, errors.New("vrf: proof of invalid length")
+	}
+	Gamma, err := s.stringToPoint(pi[:s.ptLen])
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	S, err := decodeScalar(s.group, pi[s.ptLen+s.cLen:])
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	return Gamma, pi[s.ptLen : s.ptLen+s.cLen], S, nil
+}
+
+// decodeScalar decodes a scalar, which must be reduced modulo the order of
+// the group.
+func decodeScalar(g kyber.Group, buf []byte) (kyber.Scalar, error) {
+	x := g.Scalar()
+	if err := x.UnmarshalBinary(buf); err != nil {
+		return nil, err
+	}
+	reduced, err := g.Scalar().Add(x, g.Scalar().Zero()).MarshalBinary()
+	if err != nil {
+		return nil, err
+	}
+	if !bytes.Equal(reduced, buf) {
+		return nil, errors.New("vrf: scalar is not reduced")
+	}
+	return x, nil
+}
+
+// decodeSecret decodes a secret scalar, which must be reduced and non-zero.
+func decodeSecret(g kyber.Group, sk []byte) (kyber.Scalar, error) {
+	x, err := decodeScalar(g, sk)
+	if err != nil {
+		return nil, err
+	}
+	if x.Equal(g.Scalar().Zero()) {
+		return nil, errors.New("vrf: secret key is zero")
+	}
+	return x, nil
+}
@@ -0,0 +1,210 @@
+package vrf
+
+import (
+	"encoding/hex"
+	"math/big"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"go.dedis.ch/kyber/v3/group/edwards25519"
+	"go.dedis.ch/kyber/v3/pairing/bn256"
+	"go.dedis.ch/kyber/v3/util/random"
+)
+
+type vector struct {
+	sk, pk, alpha, pi, beta string
+}
+
+func unhex(t *testing.T, s string) []byte {
+	b, err := hex.DecodeString(s)
+	require.NoError(t, err)
+	return b
+}
+
+func testVectors(t *testing.T, s *Suite, vectors []vector) {
+	for _, v := range vectors {
+		sk := unhex(t, v.sk)
+		alpha := unhex(t, v.alpha)
+		pk, err := s.PublicKey(sk)
+		require.NoError(t, err)
+		require.Equal(t, v.pk, hex.EncodeToString(pk))
+
+		pi, err := s.Prove(sk, alpha)
+		require.NoError(t, err)
+		require.Equal(t, v.pi, hex.EncodeToString(pi))
+
+		beta, err := s.ProofToHash(pi)
+		require.NoError(t, err)
+		require.Equal(t, v.beta, hex.EncodeToString(beta))
+
+		beta, err = s.Verify(pk, pi, alpha)
+		require.NoError(t, err)
+		require.Equal(t, v.beta, hex.EncodeToString(beta))
+	}
+}
+
+// RFC 9381, appendix B.3, examples 16 to 18
+func TestEdwards25519SHA512ELL2(t *testing.T) {
+	testVectors(t, Edwards25519SHA512ELL2(), []vector{
+		{
+			sk:    "9d61b19deffd5a60ba844af492ec2cc44449c5697b326919703bac031cae7f60",
+			pk:    "d75a980182b10ab7d54bfed3c964073a0ee172f3daa62325af021a68f707511a",
+			alpha: "",
+			pi:    "7d9c633ffeee27349264cf5c667579fc583b4bda63ab71d001f89c10003ab46f14adf9a3cd8b8412d9038531e865c341cafa73589b023d14311c331a9ad15ff2fb37831e00f0acaa6d73bc9997b06501",
+			beta:  "9d574bf9b8302ec0fc1e21c3ec5368269527b87b462ce36dab2d14ccf80c53cccf6758f058c5b1c856b116388152bbe509ee3b9ecfe63d93c3b4346c1fbc6c54",
+		},
+		{
+			sk:    "4ccd089b28ff96da9db6c346ec114e0f5b8a319f35aba624da8cf6ed4fb8a6fb",
+			pk:    "3d4017c3e843895a92b70aa74d1b7ebc9c982ccf2ec4968cc0cd55f12af4660c",
+			alpha: "72",
+			pi:    "47b327393ff2dd81336f8a2ef10339112401253b3c714eeda879f12c509072ef055b48372bb82efbdce8e10c8cb9a2f9d60e93908f93df1623ad78a86a028d6bc064dbfc75a6a57379ef855dc6733801",
+			beta:  "38561d6b77b71d30eb97a062168ae12b667ce5c28caccdf76bc88e093e4635987cd96814ce55b4689b3dd2947f80e59aac7b7675f8083865b46c89b2ce9cc735",
+		},
+		{
+			sk:    "c5aa8df43f9f837bedb7442f31dcb7b166d38535076f094b85ce3a2e0b4458f7",
+			pk:    "fc51cd8e6218a1a38da47ed00230f0580816ed13ba3303ac5deb911548908025",
+			alpha: "af82",
+			pi:    "926e895d308f5e328e7aa159c06eddbe56d06846abf5d98c2512235eaa57fdce35b46edfc655bc828d44ad09d1150f31374e7ef73027e14760d42e77341fe05467bb286cc2c9d7fde29120a0b2320d04",
+			beta:  "121b7f9b9aaaa29099fc04a94ba52784d44eac976dd1a3cca458733be5cd090a7b5fbd148444f17f8daf1fb55cb04b1ae85a626e30a54b4b0f8abf4a43314a58",
+		},
+	})
+}
+
+// RFC 9381, appendix B.1, examples 10 to 12
+func TestP256SHA256TAI(t *testing.T) {
+	testVectors(t, P256SHA256TAI(), []vector{
+		{
+			sk:    "c9afa9d845ba75166b5c215767b1d6934e50c3db36e89b127b8a622b120f6721",
+			pk:    "0360fed4ba255a9d31c961eb74c6356d68c049b8923b61fa6ce669622e60f29fb6",
+			alpha: hex.EncodeToString([]byte("sample")),
+			pi:    "035b5c726e8c0e2c488a107c600578ee75cb702343c153cb1eb8dec77f4b5071b4a53f0a46f018bc2c56e58d383f2305e0975972c26feea0eb122fe7893c15af376b33edf7de17c6ea056d4d82de6bc02f",
+			beta:  "a3ad7b0ef73d8fc6655053ea22f9bede8c743f08bbed3d38821f0e16474b505e",
+		},
+		{
+			sk:    "c9afa9d845ba75166b5c215767b1d6934e50c3db36e89b127b8a622b120f6721",
+			pk:    "0360fed4ba255a9d31c961eb74c6356d68c049b8923b61fa6ce669622e60f29fb6",
+			alpha: hex.EncodeToString([]byte("test")),
+			pi:    "034dac60aba508ba0c01aa9be80377ebd7562c4a52d74722e0abae7dc3080ddb56c19e067b15a8a8174905b13617804534214f935b94c2287f797e393eb0816969d864f37625b443f30f1a5a33f2b3c854",
+			beta:  "a284f94ceec2ff4b3794629da7cbafa49121972671b466cab4ce170aa365f26d",
+		},
+		{
+			sk:    "2ca1411a41b17b24cc8c3b089cfd033f1920202a6c0de8abb97df1498d50d2c8",
+			pk:    "03596375e6ce57e0f20294fc46bdfcfd19a39f8161b58695b3ec5b3d16427c274d",
+			alpha: hex.EncodeToString([]byte("Example using ECDSA key from Appendix L.4.2 of ANSI.X9-62-2005")),
+			pi:    "03d03398bf53aa23831d7d1b2937e005fb0062cbefa06796579f2a1fc7e7b8c667d091c00b0f5c3619d10ecea44363b5a599cadc5b2957e223fec62e81f7b4825fc799a771a3d7334b9186bdbee87316b1",
+			beta:  "90871e06da5caa39a3c61578ebb844de8635e27ac0b13e829997d0d95dd98c19",
+		},
+	})
+}
+
+func testSuite(t *testing.T, s *Suite) {
+	sk, pk, err := s.GenerateKey(random.New())
+	require.NoError(t, err)
+	alpha := []byte("round 42")
+	pi, err := s.Prove(sk, alpha)
+	require.NoError(t, err)
+	beta, err := s.Verify(pk, pi, alpha)
+	require.NoError(t, err)
+	hash, err := s.ProofToHash(pi)
+	require.NoError(t, err)
+	require.Equal(t, hash, beta)
+
+	// the output is unique
+	again, err := s.Prove(sk, alpha)
+	require.NoError(t, err)
+	require.Equal(t, pi, again)
+	other, err := s.Prove(sk, []byte("round 43"))
+	require.NoError(t, err)
+	hash, err = s.ProofToHash(other)
+	require.NoError(t, err)
+	require.NotEqual(t, hash, beta)
+
+	_, err = s.Verify(pk, pi, []byte("round 43"))
+	require.Error(t, err)
+	_, otherPk, err := s.GenerateKey(random.New())
+	require.NoError(t, err)
+	_, err = s.Verify(otherPk, pi, alpha)
+	require.Error(t, err)
+	_, err = s.Verify(pk, pi[1:], alpha)
+	require.Error(t, err)
+	for _, i := range []int{s.ptLen, len(pi) - 2} {
+		tampered := append([]byte{}, pi...)
+		tampered[i] ^= 1
+		_, err = s.Verify(pk, tampered, alpha)
+		require.Error(t, err)
+	}
+}
+
+func TestSuites(t *testing.T) {
+	testSuite(t, Edwards25519SHA512ELL2())
+	testSuite(t, P256SHA256TAI())
+
+	suite := bn256.NewSuite()
+	for _, s := range []func() (*Suite, error){
+		func() (*Suite, error) { return NewSuite(suite.G1(), suite.Hash) },
+		func() (*Suite, error) { return NewSuite(suite.G2(), suite.Hash) },
+	} {
+		s, err := s()
+		require.NoError(t, err)
+		testSuite(t, s)
+	}
+
+	_, err := NewSuite(edwards25519.NewBlakeSHA256Ed25519(), suite.Hash)
+	require.Error(t, err)
+}
+
+func TestVerifyInvalid(t *testing.T) {
+	s := Edwards25519SHA512ELL2()
+	sk, pk, err := s.GenerateKey(random.New())
+	require.NoError(t, err)
+	alpha := []byte("round 42")
+	pi, err := s.Prove(sk, alpha)
+	require.NoError(t, err)
+
+	// the response must be reduced
+	l, _ := new(big.Int).SetString("7237005577332262213973186563042994240857116359379907606001950938285454250989", 10)
+	S := new(big.Int)
+	for i := len(pi) - 1; i >= len(pi)-32; i-- {
+		S.Lsh(S, 8).Or(S, big.NewInt(int64(pi[i])))
+	}
+	S.Add(S, l)
+	unreduced := append([]byte{}, pi[:len(pi)-32]...)
+	for i := 0; i < 32; i++ {
+		unreduced = append(unreduced, byte(S.Uint64()))
+		S.Rsh(S, 8)
+	}
+	_, err = s.Verify(pk, unreduced, alpha)
+	require.Error(t, err)
+
+	// public keys of small order are rejected
+	_, err = s.Verify(make([]byte, 32), pi, alpha)
+	require.Error(t, err)
+	null, err := s.group.Point().Null().MarshalBinary()
+	require.NoError(t, err)
+	_, err = s.Verify(null, pi, alpha)
+	require.Error(t, err)
+
+	_, err = s.Prove(sk[1:], alpha)
+	require.Error(t, err)
+	_, err = P256SHA256TAI().Prove(make([]byte, 32), alpha)
+	require.Error(t, err)
+}
+
+// RFC 9380, appendix J.5.2
+func TestEncodeToEdwards25519(t *testing.T) {
+	g := edwards25519.NewBlakeSHA256Ed25519()
+	dst := []byte("QUUX-V01-CS02-with-edwards25519_XMD:SHA-512_ELL2_NU_")
+	for _, v := range []struct{ msg, x, y string }{
+		{"", "1ff2b70ecf862799e11b7ae744e3489aa058ce805dd323a936375a84695e76da", "222e314d04a4d5725e9f2aff9fb2a6b69ef375a1214eb19021ceab2d687f0f9b"},
+		{"abc", "5f13cc69c891d86927eb37bd4afc6672360007c63f68a33ab423a3aa040fd2a8", "67732d50f9a26f73111dd1ed5dba225614e538599db58ba30aaea1f5c827fa42"},
+		{"abcdef0123456789", "1dd2fefce934ecfd7aae6ec998de088d7dd03316aa1847198aecf699ba6613f1", "2f8a6c24dd1adde73909cada6a4a137577b0f179d336685c4a955a0a8e1a86fb"},
+	} {
+		P, err := encodeToEdwards25519(g, dst, []byte(v.msg))
+		require.NoError(t, err)
+		x, _ := new(big.Int).SetString(v.x, 16)
+		y, _ := new(big.Int).SetString(v.y, 16)
+		Q, err := edwardsPoint(g, x, y)
+		require.NoError(t, err)
+		require.True(t, P.Equal(Q))
+	}
+}
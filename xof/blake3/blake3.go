@@ -0,0 +1,333 @@
+// Package blake3 provides an implementation of kyber.XOF based on the
+// BLAKE3 hash function.
+//
+// BLAKE3 hashes its input as a binary tree of 1 KiB chunks and has an
+// unbounded output, which is produced by running the compression function
+// of the root node in counter mode.
+package blake3
+
+import (
+	"encoding/binary"
+	"math/bits"
+
+	"go.dedis.ch/kyber/v3"
+)
+
+const (
+	// KeySize is the size of the key taken by NewKeyed.
+	KeySize = 32
+
+	blockSize = 64
+	chunkSize = 1024
+
+	flagChunkStart = 1 << 0
+	flagChunkEnd   = 1 << 1
+	flagParent     = 1 << 2
+	flagRoot       = 1 << 3
+	flagKeyedHash  = 1 << 4
+)
+
+var iv = [8]uint32{
+	0x6A09E667, 0xBB67AE85, 0x3C6EF372, 0xA54FF53A,
+	0x510E527F, 0x9B05688C, 0x1F83D9AB, 0x5BE0CD19,
+}
+
+// msgPermutation is the permutation applied to the message words
+// between rounds.
+var msgPermutation = [16]int{2, 6, 3, 10, 7, 0, 4, 13, 1, 11, 12, 5, 9, 14, 15, 8}
+
+// msgSchedule holds the message word order of each of the seven rounds.
+var msgSchedule [7][16]int
+
+func init() {
+	for i := range msgSchedule[0] {
+		msgSchedule[0][i] = i
+	}
+	for r := 1; r < len(msgSchedule); r++ {
+		for i, p := range msgPermutation {
+			msgSchedule[r][i] = msgSchedule[r-1][p]
+		}
+	}
+}
+
+func g(s *[16]uint32, a, b, c, d int, mx, my uint32) {
+	s[a] += s[b] + mx
+	s[d] = bits.RotateLeft32(s[d]^s[a], -16)
+	s[c] += s[d]
+	s[b] = bits.RotateLeft32(s[b]^s[c], -12)
+	s[a] += s[b] + my
+	s[d] = bits.RotateLeft32(s[d]^s[a], -8)
+	s[c] += s[d]
+	s[b] = bits.RotateLeft32(s[b]^s[c], -7)
+}
+
+// compress is the BLAKE3 compression function. It returns the full
+// 16-word output; chaining values are its first 8 words.
+func compress(cv *[8]uint32, block *[16]uint32, counter uint64, blockLen, flags uint32) [16]uint32 {
+	s := [16]uint32{
+		cv[0], cv[1], cv[2], cv[3], cv[4], cv[5], cv[6], cv[7],
+		iv[0], iv[1], iv[2], iv[3],
+		uint32(counter), uint32(counter >> 32), blockLen, flags,
+	}
+	for r := range msgSchedule {
+		m := &msgSchedule[r]
+		g(&s, 0, 4, 8, 12, block[m[0]], block[m[1]])
+		g(&s, 1, 5, 9, 13, block[m[2]], block[m[3]])
+		g(&s, 2, 6, 10, 14, block[m[4]], block[m[5]])
+		g(&s, 3, 7, 11, 15, block[m[6]], block[m[7]])
+		g(&s, 0, 5, 10, 15, block[m[8]], block[m[9]])
+		g(&s, 1, 6, 11, 12, block[m[10]], block[m[11]])
+		g(&s, 2, 7, 8, 13, block[m[12]], block[m[13]])
+		g(&s, 3, 4, 9, 14, block[m[14]], block[m[15]])
+	}
+	for i := 0; i < 8; i++ {
+		s[i] ^= s[i+8]
+		s[i+8] ^= cv[i]
+	}
+	return s
+}
+
+func first8(w [16]uint32) (cv [8]uint32) {
+	copy(cv[:], w[:8])
+	return
+}
+
+func wordsFromBytes(b []byte, w []uint32) {
+	for i := range w {
+		w[i] = binary.LittleEndian.Uint32(b[4*i:])
+	}
+}
+
+// node holds the inputs of a compression whose output has not been
+// computed yet, so that it can be used either as a chaining value or as
+// the root of the tree.
+type node struct {
+	cv       [8]uint32
+	block    [16]uint32
+	counter  uint64
+	blockLen uint32
+	flags    uint32
+}
+
+func (n *node) chainingValue() [8]uint32 {
+	return first8(compress(&n.cv, &n.block, n.counter, n.blockLen, n.flags))
+}
+
+func parentNode(left, right [8]uint32, key *[8]uint32, flags uint32) node {
+	n := node{cv: *key, blockLen: blockSize, flags: flags | flagParent}
+	copy(n.block[:8], left[:])
+	copy(n.block[8:], right[:])
+	return n
+}
+
+// chunkState absorbs the input of a single chunk.
+type chunkState struct {
+	cv       [8]uint32
+	counter  uint64
+	block    [blockSize]byte
+	blockLen int
+	blocks   int
+	flags    uint32
+}
+
+func newChunkState(key *[8]uint32, counter uint64, flags uint32) chunkState {
+	return chunkState{cv: *key, counter: counter, flags: flags}
+}
+
+func (c *chunkState) len() int {
+	return blockSize*c.blocks + c.blockLen
+}
+
+func (c *chunkState) startFlag() uint32 {
+	if c.blocks == 0 {
+		return flagChunkStart
+	}
+	return 0
+}
+
+func (c *chunkState) update(src []byte) {
+	for len(src) > 0 {
+		if c.blockLen == blockSize {
+			var w [16]uint32
+			wordsFromBytes(c.block[:], w[:])
+			c.cv = first8(compress(&c.cv, &w, c.counter, blockSize, c.flags|c.startFlag()))
+			c.blocks++
+			c.block = [blockSize]byte{}
+			c.blockLen = 0
+		}
+		n := copy(c.block[c.blockLen:], src)
+		c.blockLen += n
+		src = src[n:]
+	}
+}
+
+func (c *chunkState) output() node {
+	n := node{
+		cv:       c.cv,
+		counter:  c.counter,
+		blockLen: uint32(c.blockLen),
+		flags:    c.flags | c.startFlag() | flagChunkEnd,
+	}
+	wordsFromBytes(c.block[:], n.block[:])
+	return n
+}
+
+type xof struct {
+	key   [8]uint32
+	flags uint32
+	chunk chunkState
+	// stack holds the chaining values of the complete subtrees to the
+	// left of the current chunk.
+	stack [][8]uint32
+
+	// squeezing is set on the first Read, after which root holds the
+	// root node of the tree, and out the block of output at position
+	// outCounter, of which outPos bytes were already consumed.
+	squeezing  bool
+	root       node
+	out        [blockSize]byte
+	outCounter uint64
+	outPos     int
+
+	// stream is here to not make excess garbage during repeated calls
+	// to XORKeyStream.
+	stream []byte
+}
+
+// New creates a new XOF using BLAKE3 in hash mode.
+func New(seed []byte) kyber.XOF {
+	x := newXOF(iv, 0)
+	x.Write(seed)
+	return x
+}
+
+// NewKeyed creates a new XOF using BLAKE3 in keyed hash mode. The key
+// must be KeySize bytes long.
+func NewKeyed(key, seed []byte) kyber.XOF {
+	if len(key) != KeySize {
+		panic("blake3: invalid key size")
+	}
+	var k [8]uint32
+	wordsFromBytes(key, k[:])
+	x := newXOF(k, flagKeyedHash)
+	x.Write(seed)
+	return x
+}
+
+func newXOF(key [8]uint32, flags uint32) *xof {
+	return &xof{
+		key:   key,
+		flags: flags,
+		chunk: newChunkState(&key, 0, flags),
+	}
+}
+
+func (x *xof) Clone() kyber.XOF {
+	c := *x
+	c.stack = append([][8]uint32{}, x.stack...)
+	c.stream = nil
+	return &c
+}
+
+func (x *xof) Reseed() {
+	if len(x.stream) < 128 {
+		x.stream = make([]byte, 128)
+	} else {
+		x.stream = x.stream[0:128]
+	}
+	x.Read(x.stream)
+	y := newXOF(x.key, x.flags)
+	y.Write(x.stream)
+	y.stream = x.stream
+	*x = *y
+}
+
+func (x *xof) Write(src []byte) (int, error) {
+	if x.squeezing {
+		panic("blake3: write after read")
+	}
+	n := len(src)
+	for len(src) > 0 {
+		if x.chunk.len() == chunkSize {
+			cv := x.chunk.output()
+			total := x.chunk.counter + 1
+			x.addChunk(cv.chainingValue(), total)
+			x.chunk = newChunkState(&x.key, total, x.flags)
+		}
+		c := chunkSize - x.chunk.len()
+		if c > len(src) {
+			c = len(src)
+		}
+		x.chunk.update(src[:c])
+		src = src[c:]
+	}
+	return n, nil
+}
+
+// addChunk pushes the chaining value of a completed chunk, merging
+// complete subtrees as indicated by the number of trailing zero bits of
+// the total number of chunks.
+func (x *xof) addChunk(cv [8]uint32, total uint64) {
+	for total&1 == 0 {
+		top := x.stack[len(x.stack)-1]
+		x.stack = x.stack[:len(x.stack)-1]
+		p := parentNode(top, cv, &x.key, x.flags)
+		cv = p.chainingValue()
+		total >>= 1
+	}
+	x.stack = append(x.stack, cv)
+}
+
+func (x *xof) finalize() {
+	n := x.chunk.output()
+	for i := len(x.stack) - 1; i >= 0; i-- {
+		n = parentNode(x.stack[i], n.chainingValue(), &x.key, x.flags)
+	}
+	x.root = n
+	x.squeezing = true
+	x.outPos = blockSize
+}
+
+func (x *xof) Read(dst []byte) (int, error) {
+	if !x.squeezing {
+		x.finalize()
+	}
+	n := len(dst)
+	for len(dst) > 0 {
+		if x.outPos == blockSize {
+			w := compress(&x.root.cv, &x.root.block, x.outCounter, x.root.blockLen, x.root.flags|flagRoot)
+			for i, v := range w {
+				binary.LittleEndian.PutUint32(x.out[4*i:], v)
+			}
+			x.outCounter++
+			x.outPos = 0
+		}
+		c := copy(dst, x.out[x.outPos:])
+		x.outPos += c
+		dst = dst[c:]
+	}
+	return n, nil
+}
+
+func (x *xof) XORKeyStream(dst, src []byte) {
+	if len(dst) < len(src) {
+		panic("dst too short")
+	}
+	if len(x.stream) < len(src) {
+		x.stream = make([]byte, len(src))
+	} else {
+		x.stream = x.stream[0:len(src)]
+	}
+
+	n, err := x.Read(x.stream)
+	if err != nil {
+		panic("xof error getting key: " + err.Error())
+	}
+	if n != len(src) {
+		panic("short read on key")
+	}
+
+	for i := range src {
+		dst[i] = src[i] ^ x.stream[i]
+	}
+}
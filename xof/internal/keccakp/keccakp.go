@@ -0,0 +1,183 @@
+// Package keccakp provides the Keccak-p[1600, n_r] permutation and a
+// sponge built on top of it, as used by TurboSHAKE and KangarooTwelve.
+package keccakp
+
+import "encoding/binary"
+
+// MaxRate is the largest rate, in bytes, that a Sponge supports.
+const MaxRate = 200
+
+// rc holds the round constants of Keccak-f[1600]. Keccak-p[1600, n_r]
+// uses the last n_r of them.
+var rc = [24]uint64{
+	0x0000000000000001, 0x0000000000008082, 0x800000000000808A, 0x8000000080008000,
+	0x000000000000808B, 0x0000000080000001, 0x8000000080008081, 0x8000000000008009,
+	0x000000000000008A, 0x0000000000000088, 0x0000000080008009, 0x000000008000000A,
+	0x000000008000808B, 0x800000000000008B, 0x8000000000008089, 0x8000000000008003,
+	0x8000000000008002, 0x8000000000000080, 0x000000000000800A, 0x800000008000000A,
+	0x8000000080008081, 0x8000000000008080, 0x0000000080000001, 0x8000000080008008,
+}
+
+// rotc and piln are the rho rotation offsets and the pi lane order.
+var rotc = [24]uint{
+	1, 3, 6, 10, 15, 21, 28, 36, 45, 55, 2, 14,
+	27, 41, 56, 8, 25, 43, 62, 18, 39, 61, 20, 44,
+}
+
+var piln = [24]int{
+	10, 7, 11, 17, 18, 3, 5, 16, 8, 21, 24, 4,
+	15, 23, 19, 13, 12, 2, 20, 14, 22, 9, 6, 1,
+}
+
+func rotl(x uint64, n uint) uint64 {
+	return x<<n | x>>(64-n)
+}
+
+// Permute applies Keccak-p[1600, rounds] to a. rounds must be between
+// 1 and 24.
+func Permute(a *[25]uint64, rounds int) {
+	var bc [5]uint64
+	for r := 24 - rounds; r < 24; r++ {
+		// theta
+		for i := 0; i < 5; i++ {
+			bc[i] = a[i] ^ a[i+5] ^ a[i+10] ^ a[i+15] ^ a[i+20]
+		}
+		for i := 0; i < 5; i++ {
+			t := bc[(i+4)%5] ^ rotl(bc[(i+1)%5], 1)
+			for j := 0; j < 25; j += 5 {
+				a[j+i] ^= t
+			}
+		}
+
+		// rho and pi
+		t := a[1]
+		for i := 0; i < 24; i++ {
+			j := piln[i]
+			bc[0] = a[j]
+			a[j] = rotl(t, rotc[i])
+			t = bc[0]
+		}
+
+		// chi
+		for j := 0; j < 25; j += 5 {
+			for i := 0; i < 5; i++ {
+				bc[i] = a[j+i]
+			}
+			for i := 0; i < 5; i++ {
+				a[j+i] ^= ^bc[(i+1)%5] & bc[(i+2)%5]
+			}
+		}
+
+		// iota
+		a[0] ^= rc[r]
+	}
+}
+
+// Sponge is a sponge construction over Keccak-p[1600, n_r] with a
+// configurable rate and domain separation byte. The zero value is not
+// usable; create one with New.
+type Sponge struct {
+	a      [25]uint64
+	buf    [MaxRate]byte
+	n      int
+	rate   int
+	rounds int
+	ds     byte
+	// squeezing is set once the input has been padded: afterwards, buf
+	// holds output and n is the number of bytes of it already consumed.
+	squeezing bool
+}
+
+// New returns a sponge that absorbs rate bytes per call to the
+// permutation, uses the given number of rounds and pads the input
+// with the domain separation byte ds.
+func New(rate, rounds int, ds byte) *Sponge {
+	if rate <= 0 || rate > MaxRate || rate%8 != 0 {
+		panic("keccakp: invalid rate")
+	}
+	if rounds < 1 || rounds > 24 {
+		panic("keccakp: invalid number of rounds")
+	}
+	return &Sponge{rate: rate, rounds: rounds, ds: ds}
+}
+
+// SetDomain changes the domain separation byte used when the sponge
+// switches from absorbing to squeezing. It panics if called after Read.
+func (s *Sponge) SetDomain(ds byte) {
+	if s.squeezing {
+		panic("keccakp: domain set after read")
+	}
+	s.ds = ds
+}
+
+// Clone returns an independent copy of the sponge in its current state.
+func (s *Sponge) Clone() *Sponge {
+	c := *s
+	return &c
+}
+
+func (s *Sponge) xorBuf() {
+	for i := 0; i < s.rate/8; i++ {
+		s.a[i] ^= binary.LittleEndian.Uint64(s.buf[8*i:])
+	}
+}
+
+func (s *Sponge) fillBuf() {
+	for i := 0; i < s.rate/8; i++ {
+		binary.LittleEndian.PutUint64(s.buf[8*i:], s.a[i])
+	}
+}
+
+// Write absorbs more data into the sponge. It panics if called after
+// Read.
+func (s *Sponge) Write(src []byte) (int, error) {
+	if s.squeezing {
+		panic("keccakp: write after read")
+	}
+	n := len(src)
+	for len(src) > 0 {
+		c := copy(s.buf[s.n:s.rate], src)
+		s.n += c
+		src = src[c:]
+		if s.n == s.rate {
+			s.xorBuf()
+			Permute(&s.a, s.rounds)
+			s.n = 0
+		}
+	}
+	return n, nil
+}
+
+func (s *Sponge) pad() {
+	for i := s.n; i < s.rate; i++ {
+		s.buf[i] = 0
+	}
+	s.buf[s.n] ^= s.ds
+	s.buf[s.rate-1] ^= 0x80
+	s.xorBuf()
+	Permute(&s.a, s.rounds)
+	s.fillBuf()
+	s.n = 0
+	s.squeezing = true
+}
+
+// Read squeezes output from the sponge. The first call pads the
+// absorbed input; Write may not be called afterwards. Read never
+// returns an error.
+func (s *Sponge) Read(dst []byte) (int, error) {
+	if !s.squeezing {
+		s.pad()
+	}
+	n := len(dst)
+	for len(dst) > 0 {
+		if s.n == s.rate {
+			Permute(&s.a, s.rounds)
+			s.fillBuf()
+			s.n = 0
+		}
+		c := copy(dst, s.buf[s.n:s.rate])
+		s.n += c
+		dst = dst[c:]
+	}
+	return n, nil
+}
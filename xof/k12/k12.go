@@ -0,0 +1,191 @@
+// Package k12 provides an implementation of kyber.XOF based on
+// KangarooTwelve (KT128), as specified in RFC 9861.
+//
+// KangarooTwelve splits its input into 8 KiB chunks that are hashed
+// independently with TurboSHAKE128 before being combined, which makes it
+// considerably faster than SHAKE on long inputs.
+package k12
+
+import (
+	"go.dedis.ch/kyber/v3"
+	"go.dedis.ch/kyber/v3/xof/internal/keccakp"
+)
+
+const (
+	rounds    = 12
+	rate      = 168
+	chunkSize = 8192
+	cvSize    = 32
+
+	// Domain separation bytes of RFC 9861, section 3.
+	dsSingle = 0x07
+	dsFinal  = 0x06
+	dsLeaf   = 0x0B
+)
+
+type xof struct {
+	// final absorbs the first chunk followed by the chaining values of
+	// the leaves.
+	final *keccakp.Sponge
+	// leaf absorbs the current chunk when there is more than one.
+	leaf *keccakp.Sponge
+	// custom is the customization string appended to the input.
+	custom []byte
+	// n is the number of input bytes absorbed so far.
+	n int
+	// leaves is the number of leaves whose chaining value went into final.
+	leaves int
+	// squeezing is set once the input has been finalized.
+	squeezing bool
+	// key is here to not make excess garbage during repeated calls
+	// to XORKeyStream.
+	key []byte
+}
+
+// New creates a new XOF using KangarooTwelve with an empty
+// customization string.
+func New(seed []byte) kyber.XOF {
+	return NewWithCustomization(seed, nil)
+}
+
+// NewWithCustomization creates a new XOF using KangarooTwelve with the
+// given customization string, which can be used for domain separation.
+func NewWithCustomization(seed, custom []byte) kyber.XOF {
+	x := &xof{
+		final:  keccakp.New(rate, rounds, dsSingle),
+		custom: append([]byte{}, custom...),
+	}
+	x.Write(seed)
+	return x
+}
+
+func (x *xof) Clone() kyber.XOF {
+	c := *x
+	c.final = x.final.Clone()
+	if x.leaf != nil {
+		c.leaf = x.leaf.Clone()
+	}
+	c.key = nil
+	return &c
+}
+
+func (x *xof) Reseed() {
+	if len(x.key) < 128 {
+		x.key = make([]byte, 128)
+	} else {
+		x.key = x.key[0:128]
+	}
+	x.Read(x.key)
+	y := NewWithCustomization(x.key, x.custom).(*xof)
+	y.key = x.key
+	*x = *y
+}
+
+func (x *xof) Write(src []byte) (int, error) {
+	if x.squeezing {
+		panic("k12: write after read")
+	}
+	return x.absorb(src), nil
+}
+
+// absorb feeds src into the tree, starting a new leaf every chunkSize
+// bytes after the first chunk.
+func (x *xof) absorb(src []byte) int {
+	n := len(src)
+	for len(src) > 0 {
+		if x.n < chunkSize {
+			c := min(chunkSize-x.n, len(src))
+			x.final.Write(src[:c])
+			x.n += c
+			src = src[c:]
+			continue
+		}
+
+		off := (x.n - chunkSize) % chunkSize
+		if off == 0 {
+			if x.leaf == nil {
+				// The input does not fit in a single chunk: switch the
+				// final node to tree hashing.
+				x.final.Write([]byte{0x03, 0, 0, 0, 0, 0, 0, 0})
+				x.final.SetDomain(dsFinal)
+			} else {
+				x.flushLeaf()
+			}
+			x.leaf = keccakp.New(rate, rounds, dsLeaf)
+		}
+		c := min(chunkSize-off, len(src))
+		x.leaf.Write(src[:c])
+		x.n += c
+		src = src[c:]
+	}
+	return n
+}
+
+func (x *xof) flushLeaf() {
+	var cv [cvSize]byte
+	x.leaf.Read(cv[:])
+	x.final.Write(cv[:])
+	x.leaves++
+}
+
+func (x *xof) finalize() {
+	x.absorb(x.custom)
+	x.absorb(lengthEncode(len(x.custom)))
+	if x.leaf != nil {
+		x.flushLeaf()
+		x.final.Write(lengthEncode(x.leaves))
+		x.final.Write([]byte{0xff, 0xff})
+	}
+	x.squeezing = true
+}
+
+func (x *xof) Read(dst []byte) (int, error) {
+	if !x.squeezing {
+		x.finalize()
+	}
+	return x.final.Read(dst)
+}
+
+func (x *xof) XORKeyStream(dst, src []byte) {
+	if len(dst) < len(src) {
+		panic("dst too short")
+	}
+	if len(x.key) < len(src) {
+		x.key = make([]byte, len(src))
+	} else {
+		x.key = x.key[0:len(src)]
+	}
+
+	n, err := x.Read(x.key)
+	if err != nil {
+		panic("xof error getting key: " + err.Error())
+	}
+	if n != len(src) {
+		panic("short read on key")
+	}
+
+	for i := range src {
+		dst[i] = src[i] ^ x.key[i]
+	}
+}
+
+// lengthEncode returns the length_encode(x) of RFC 9861: the big-endian
+// encoding of x with no leading zeros, followed by the length of that
+// encoding.
+func lengthEncode(x int) []byte {
+	var buf [9]byte
+	i := 8
+	for ; x > 0; x >>= 8 {
+		i--
+		buf[i] = byte(x)
+	}
+	buf[8] = byte(8 - i)
+	return buf[i:]
+}
+
+func min(a, b int) int {
+	if a < b {
+		return a
+	}
+	return b
+}
@@ -0,0 +1,70 @@
+package k12
+
+import (
+	"encoding/hex"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+// ptn returns the pattern message of RFC 9861, section 5.
+func ptn(n int) []byte {
+	buf := make([]byte, n)
+	for i := range buf {
+		buf[i] = byte(i % 0xfb)
+	}
+	return buf
+}
+
+func testVector(t *testing.T, msg, custom []byte, outLen int, want string) {
+	for _, writeSize := range []int{1024, 7919, chunkSize} {
+		x := NewWithCustomization(nil, custom)
+		for m := msg; len(m) > 0; {
+			c := min(writeSize, len(m))
+			x.Write(m[:c])
+			m = m[c:]
+		}
+		out := make([]byte, outLen)
+		_, err := x.Read(out)
+		require.NoError(t, err)
+		require.Equal(t, want, hex.EncodeToString(out), "len %d, write size %d", len(msg), writeSize)
+	}
+}
+
+// Test vectors from RFC 9861, section 5.
+func TestVectors(t *testing.T) {
+	testVector(t, nil, nil, 32, "1ac2d450fc3b4205d19da7bfca1b37513c0803577ac7167f06fe2ce1f0ef39e5")
+	testVector(t, ptn(1), nil, 32, "2bda92450e8b147f8a7cb629e784a058efca7cf7d8218e02d345dfaa65244a1f")
+	n := 17
+	testVector(t, ptn(n), nil, 32, "6bf75fa2239198db4772e36478f8e19b0f371205f6a9a93a273f51df37122888")
+	n *= 17
+	testVector(t, ptn(n), nil, 32, "0c315ebcdedbf61426de7dcf8fb725d1e74675d7f5327a5067f367b108ecb67c")
+	n *= 17
+	testVector(t, ptn(n), nil, 32, "cb552e2ec77d9910701d578b457ddf772c12e322e4ee7fe417f92c758f0d59d0")
+	n *= 17
+	testVector(t, ptn(n), nil, 32, "8701045e22205345ff4dda05555cbb5c3af1a771c2b89baef37db43d9998b9fe")
+	n *= 17
+	testVector(t, ptn(n), nil, 32, "844d610933b1b9963cbdeb5ae3b6b05cc7cbd67ceedf883eb678a0a8e0371682")
+	n *= 17
+	testVector(t, ptn(n), nil, 32, "3c390782a8a4e89fa6367f72feaaf13255c8d95878481d3cd8ce85f58e880af8")
+	testVector(t, nil, ptn(1), 32, "fab658db63e94a246188bf7af69a133045f46ee984c56e3c3328caaf1aa1a583")
+	testVector(t, []byte{0xff}, ptn(41), 32, "d848c5068ced736f4462159b9867fd4c20b808acc3d5bc48e0b06ba0a3762ec4")
+	testVector(t, []byte{0xff, 0xff, 0xff}, ptn(41*41), 32, "c389e5009ae57120854c2e8c64670ac01358cf4c1baf89447a724234dc7ced74")
+	testVector(t, []byte{0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff}, ptn(41*41*41), 32, "75d2f86a2e644566726b4fbcfc5657b9dbcf070c7b0dca06450ab291d7443bcf")
+}
+
+// Corner cases around the chunk size, from the CIRCL test suite.
+func TestChunkBoundaries(t *testing.T) {
+	testVector(t, ptn(chunkSize), nil, 16, "48f256f6772f9edfb6a8b661ec92dc93")
+	testVector(t, ptn(chunkSize+1), nil, 16, "bb66fe72eaea5179418d5295ee134485")
+	testVector(t, ptn(2*chunkSize), nil, 16, "82778f7f7234c83352e76837b721fbdb")
+	testVector(t, ptn(2*chunkSize+1), nil, 16, "5f8d2b943922b451842b4e82740d0236")
+	testVector(t, ptn(3*chunkSize), nil, 16, "f4082a8fe7d1635aa042cd1da63bf235")
+	testVector(t, ptn(3*chunkSize+1), nil, 16, "38cb940999aca742d69dd79298c6051c")
+}
+
+func TestLengthEncode(t *testing.T) {
+	require.Equal(t, []byte{0x00}, lengthEncode(0))
+	require.Equal(t, []byte{0x0c, 0x01}, lengthEncode(12))
+	require.Equal(t, []byte{0x0a, 0xa8, 0x02}, lengthEncode(2728))
+}
@@ -0,0 +1,109 @@
+// Package turboshake provides an implementation of kyber.XOF based on
+// TurboSHAKE128 and TurboSHAKE256, the reduced-round Keccak XOFs
+// specified in RFC 9861.
+package turboshake
+
+import (
+	"go.dedis.ch/kyber/v3"
+	"go.dedis.ch/kyber/v3/xof/internal/keccakp"
+)
+
+const (
+	// rounds is the number of rounds of the Keccak-p[1600, n_r] permutation
+	// used by TurboSHAKE.
+	rounds = 12
+
+	rate128 = 168
+	rate256 = 136
+
+	// DefaultDomain is the domain separation byte used by New128 and New256.
+	DefaultDomain = 0x1F
+)
+
+type xof struct {
+	sh   *keccakp.Sponge
+	rate int
+	ds   byte
+	// key is here to not make excess garbage during repeated calls
+	// to XORKeyStream.
+	key []byte
+}
+
+// New128 creates a new XOF using TurboSHAKE128 with the default domain
+// separation byte.
+func New128(seed []byte) kyber.XOF {
+	return newXOF(rate128, DefaultDomain, seed)
+}
+
+// New256 creates a new XOF using TurboSHAKE256 with the default domain
+// separation byte.
+func New256(seed []byte) kyber.XOF {
+	return newXOF(rate256, DefaultDomain, seed)
+}
+
+// New128WithDomain creates a new XOF using TurboSHAKE128 with the given
+// domain separation byte, which must be in the range 0x01 to 0x7F.
+func New128WithDomain(seed []byte, ds byte) kyber.XOF {
+	return newXOF(rate128, ds, seed)
+}
+
+// New256WithDomain creates a new XOF using TurboSHAKE256 with the given
+// domain separation byte, which must be in the range 0x01 to 0x7F.
+func New256WithDomain(seed []byte, ds byte) kyber.XOF {
+	return newXOF(rate256, ds, seed)
+}
+
+func newXOF(rate int, ds byte, seed []byte) *xof {
+	if ds < 0x01 || ds > 0x7F {
+		panic("turboshake: domain separation byte out of range")
+	}
+	x := &xof{sh: keccakp.New(rate, rounds, ds), rate: rate, ds: ds}
+	x.sh.Write(seed)
+	return x
+}
+
+func (x *xof) Clone() kyber.XOF {
+	return &xof{sh: x.sh.Clone(), rate: x.rate, ds: x.ds}
+}
+
+func (x *xof) Reseed() {
+	if len(x.key) < 128 {
+		x.key = make([]byte, 128)
+	} else {
+		x.key = x.key[0:128]
+	}
+	x.Read(x.key)
+	x.sh = keccakp.New(x.rate, rounds, x.ds)
+	x.sh.Write(x.key)
+}
+
+func (x *xof) Read(dst []byte) (int, error) {
+	return x.sh.Read(dst)
+}
+
+func (x *xof) Write(src []byte) (int, error) {
+	return x.sh.Write(src)
+}
+
+func (x *xof) XORKeyStream(dst, src []byte) {
+	if len(dst) < len(src) {
+		panic("dst too short")
+	}
+	if len(x.key) < len(src) {
+		x.key = make([]byte, len(src))
+	} else {
+		x.key = x.key[0:len(src)]
+	}
+
+	n, err := x.Read(x.key)
+	if err != nil {
+		panic("xof error getting key: " + err.Error())
+	}
+	if n != len(src) {
+		panic("short read on key")
+	}
+
+	for i := range src {
+		dst[i] = src[i] ^ x.key[i]
+	}
+}
@@ -0,0 +1,66 @@
+package turboshake
+
+import (
+	"encoding/hex"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"go.dedis.ch/kyber/v3"
+)
+
+// ptn returns the pattern message of RFC 9861, section 5.
+func ptn(n int) []byte {
+	buf := make([]byte, n)
+	for i := range buf {
+		buf[i] = byte(i % 0xfb)
+	}
+	return buf
+}
+
+type vector struct {
+	newXOF func(seed []byte, ds byte) kyber.XOF
+	msg    []byte
+	ds     byte
+	outLen int
+	// skip is the number of output bytes discarded before comparing.
+	skip int
+	want string
+}
+
+// Test vectors from RFC 9861, section 5.
+var vectors = []vector{
+	{New128WithDomain, nil, 0x1F, 32, 0, "1e415f1c5983aff2169217277d17bb538cd945a397ddec541f1ce41af2c1b74c"},
+	{New128WithDomain, nil, 0x1F, 32, 10000, "a3b9b0385900ce761f22aed548e754da10a5242d62e8c658e3f3a923a7555607"},
+	{New128WithDomain, ptn(1), 0x1F, 32, 0, "55cedd6f60af7bb29a4042ae832ef3f58db7299f893ebb9247247d856958daa9"},
+	{New128WithDomain, ptn(17), 0x1F, 32, 0, "9c97d036a3bac819db70ede0ca554ec6e4c2a1a4ffbfd9ec269ca6a111161233"},
+	{New128WithDomain, ptn(17 * 17), 0x1F, 32, 0, "96c77c279e0126f7fc07c9b07f5cdae1e0be60bdbe10620040e75d7223a624d2"},
+	{New128WithDomain, ptn(17 * 17 * 17), 0x1F, 32, 0, "d4976eb56bcf118520582b709f73e1d6853e001fdaf80e1b13e0d0599d5fb372"},
+	{New128WithDomain, nil, 0x07, 64, 0, "5a223ad30b3b8c66a243048cfced430f54e7529287d15150b973133adfac6a2ffe2708e73061e09a4000168ba9c8ca1813198f7bbed4984b4185f2c2580ee623"},
+	{New128WithDomain, []byte{0xff}, 0x06, 32, 0, "8ec9c66465ed0d4a6c35d13506718d687a25cb05c74cca1e42501abd83874a67"},
+	{New256WithDomain, nil, 0x1F, 64, 0, "367a329dafea871c7802ec67f905ae13c57695dc2c6663c61035f59a18f8e7db11edc0e12e91ea60eb6b32df06dd7f002fbafabb6e13ec1cc20d995547600db0"},
+	{New256WithDomain, ptn(1), 0x1F, 64, 0, "3e1712f928f8eaf1054632b2aa0a246ed8b0c378728f60bc970410155c28820e90cc90d8a3006aa2372c5c5ea176b0682bf22bae7467ac94f74d43d39b0482e2"},
+	{New256WithDomain, ptn(17), 0x1F, 64, 0, "b3bab0300e6a191fbe6137939835923578794ea54843f5011090fa2f3780a9e5cb22c59d78b40a0fbff9e672c0fbe0970bd2c845091c6044d687054da5d8e9c7"},
+	{New256WithDomain, ptn(17 * 17), 0x1F, 64, 0, "66b810db8e90780424c0847372fdc95710882fde31c6df75beb9d4cd9305cfcae35e7b83e8b7e6eb4b78605880116316fe2c078a09b94ad7b8213c0a738b65c0"},
+	{New256WithDomain, ptn(17 * 17 * 17), 0x1F, 64, 0, "c74ebc919a5b3b0dd1228185ba02d29ef442d69d3d4276a93efe0bf9a16a7dc0cd4eabadab8cd7a5edd96695f5d360abe09e2c6511a3ec397da3b76b9e1674fb"},
+}
+
+func TestVectors(t *testing.T) {
+	for i, v := range vectors {
+		x := v.newXOF(v.msg, v.ds)
+		out := make([]byte, v.skip+v.outLen)
+		_, err := x.Read(out)
+		require.NoError(t, err)
+		require.Equal(t, v.want, hex.EncodeToString(out[v.skip:]), "vector %d", i)
+	}
+}
+
+func TestDefaultDomain(t *testing.T) {
+	a := make([]byte, 32)
+	b := make([]byte, 32)
+	New128([]byte("seed")).Read(a)
+	New128WithDomain([]byte("seed"), DefaultDomain).Read(b)
+	require.Equal(t, a, b)
+
+	require.Panics(t, func() { New256WithDomain(nil, 0) })
+	require.Panics(t, func() { New256WithDomain(nil, 0x80) })
+}
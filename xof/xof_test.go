@@ -8,7 +8,10 @@ import (
 	"github.com/stretchr/testify/require"
 	"go.dedis.ch/kyber/v3"
 	"go.dedis.ch/kyber/v3/xof/blake2xb"
+	"go.dedis.ch/kyber/v3/xof/blake3"
+	"go.dedis.ch/kyber/v3/xof/k12"
 	"go.dedis.ch/kyber/v3/xof/keccak"
+	"go.dedis.ch/kyber/v3/xof/turboshake"
 )
 
 type blakeF struct{}
@@ -19,7 +22,24 @@ type keccakF struct{}
 
 func (b *keccakF) XOF(seed []byte) kyber.XOF { return keccak.New(seed) }
 
-var impls = []kyber.XOFFactory{&blakeF{}, &keccakF{}}
+type turboshake128F struct{}
+
+func (b *turboshake128F) XOF(seed []byte) kyber.XOF { return turboshake.New128(seed) }
+
+type turboshake256F struct{}
+
+func (b *turboshake256F) XOF(seed []byte) kyber.XOF { return turboshake.New256(seed) }
+
+type k12F struct{}
+
+func (b *k12F) XOF(seed []byte) kyber.XOF { return k12.New(seed) }
+
+type blake3F struct{}
+
+func (b *blake3F) XOF(seed []byte) kyber.XOF { return blake3.New(seed) }
+
+var impls = []kyber.XOFFactory{&blakeF{}, &keccakF{}, &turboshake128F{},
+	&turboshake256F{}, &k12F{}, &blake3F{}}
 
 func TestEncDec(t *testing.T) {
 	lengths := []int{0, 1, 16, 1024, 8192}